@@ -6,12 +6,19 @@ import (
 	v1 "sondth-test_soa/app/controller/v1"
 	"sondth-test_soa/app/middleware"
 	"sondth-test_soa/app/service"
+	"sondth-test_soa/config"
 )
 
-func RegisterControllers(router *gin.Engine, services service.ServiceCollections, mws middleware.MiddlewareCollections) {
+func RegisterControllers(router *gin.Engine, services service.ServiceCollections, mws middleware.MiddlewareCollections, conf config.Configuration) {
 	v1.NewCategoryControllerV1(router, services, mws)
 	v1.NewReviewControllerV1(router, services, mws)
 	v1.NewProductControllerV1(router, services, mws)
 	v1.NewUserControllerV1(router, services, mws)
 	v1.NewWishlistControllerV1(router, services)
+	v1.NewRoleControllerV1(router, services, mws)
+	v1.NewOAuthControllerV1(router, services)
+	v1.NewApiKeyControllerV1(router, services, mws)
+	v1.NewPrivacyControllerV1(router, services, mws)
+	v1.NewProfileControllerV1(router, services, mws, conf)
+	v1.NewInventoryControllerV1(router, services, mws)
 }
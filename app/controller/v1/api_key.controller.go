@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/middleware"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/service"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+type apiKeyHandler struct {
+	services service.ServiceCollections
+	mws      middleware.MiddlewareCollections
+}
+
+func NewApiKeyControllerV1(router *gin.Engine, services service.ServiceCollections, mws middleware.MiddlewareCollections) {
+	handler := apiKeyHandler{services, mws}
+
+	group := router.Group("api/v1/api-key", mws.PermissionMw.RequirePermission(entity.PERMISSION_API_KEY_MANAGE), mws.MfaMw.Handler())
+	{
+		group.POST("/create", handler.create)
+		group.POST("/list", handler.getApiKeys)
+		group.POST("/revoke", handler.revoke)
+	}
+}
+
+func (h *apiKeyHandler) create(c *gin.Context) {
+	var req model.CreateApiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ApiKeySvc.Create(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.FormatSuccessResponse(res))
+}
+
+func (h *apiKeyHandler) getApiKeys(c *gin.Context) {
+	var req model.GetApiKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.ApiKeySvc.GetApiKeys(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
+
+func (h *apiKeyHandler) revoke(c *gin.Context) {
+	var req model.RevokeApiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.ApiKeySvc.Revoke(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
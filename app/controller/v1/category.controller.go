@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 
+	"sondth-test_soa/app/entity"
 	"sondth-test_soa/app/middleware"
 	"sondth-test_soa/app/model"
 	"sondth-test_soa/app/service"
@@ -25,7 +26,7 @@ func NewCategoryControllerV1(router *gin.Engine, services service.ServiceCollect
 
 	group := router.Group("api/v1/category")
 	{
-		adminGroup := group.Group("/", mws.AdminMw.Handler())
+		adminGroup := group.Group("/", mws.PermissionMw.RequirePermission(entity.PERMISSION_CATEGORY_WRITE), mws.MfaMw.Handler())
 		{
 			adminGroup.POST("/create", handler.create)
 			adminGroup.GET("/summary", handler.getCategoriesSummary)
@@ -39,7 +40,7 @@ func (h *categoryHandler) create(c *gin.Context) {
 	var req model.CreateCategoryRequest
 	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
 		resErr := errors.NewValidatorError(err)
-		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(resErr))
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
 		return
 	}
 
@@ -48,7 +49,7 @@ func (h *categoryHandler) create(c *gin.Context) {
 
 	res, err := h.services.CategorySvc.Create(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(err))
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
 		return
 	}
 
@@ -59,7 +60,7 @@ func (h *categoryHandler) getCategories(c *gin.Context) {
 	var req model.GetCategoriesRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		resErr := errors.NewValidatorError(err)
-		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(resErr))
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
 		return
 	}
 
@@ -68,7 +69,7 @@ func (h *categoryHandler) getCategories(c *gin.Context) {
 
 	resp, err := h.services.CategorySvc.GetCategories(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(err))
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
 		return
 	}
 
@@ -79,7 +80,7 @@ func (h *categoryHandler) getCategoriesSummary(c *gin.Context) {
 	var req model.GetCategoriesSummaryRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		resErr := errors.NewValidatorError(err)
-		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(resErr))
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
 		return
 	}
 
@@ -88,7 +89,7 @@ func (h *categoryHandler) getCategoriesSummary(c *gin.Context) {
 
 	resp, err := h.services.CategorySvc.GetCategoriesSummary(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(err))
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
 		return
 	}
 
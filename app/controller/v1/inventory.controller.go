@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/middleware"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/service"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+type inventoryHandler struct {
+	services service.ServiceCollections
+	mws      middleware.MiddlewareCollections
+}
+
+func NewInventoryControllerV1(router *gin.Engine, services service.ServiceCollections, mws middleware.MiddlewareCollections) {
+	handler := inventoryHandler{services, mws}
+
+	group := router.Group("api/v1/inventory", mws.PermissionMw.RequirePermission(entity.PERMISSION_INVENTORY_MANAGE), mws.MfaMw.Handler())
+	{
+		group.POST("/movement/create", handler.recordMovement)
+		group.POST("/movement/list", handler.getMovements)
+		group.POST("/low-stock", handler.getLowStockReport)
+	}
+}
+
+func (h *inventoryHandler) recordMovement(c *gin.Context) {
+	var req model.RecordStockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.InventorySvc.RecordMovement(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.FormatSuccessResponse(res))
+}
+
+func (h *inventoryHandler) getMovements(c *gin.Context) {
+	var req model.GetStockMovementsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.InventorySvc.GetMovements(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *inventoryHandler) getLowStockReport(c *gin.Context) {
+	var req model.GetLowStockReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.InventorySvc.GetLowStockReport(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/service"
+	"sondth-test_soa/package/errors"
+)
+
+type oAuthHandler struct {
+	services service.ServiceCollections
+}
+
+func NewOAuthControllerV1(router *gin.Engine, services service.ServiceCollections) {
+	handler := oAuthHandler{services}
+
+	router.GET("/.well-known/jwks.json", handler.getJWKS)
+}
+
+func (h *oAuthHandler) getJWKS(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	res, err := h.services.OAuthSvc.GetJWKS(ctx, &model.GetJWKSRequest{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.Localize(c, err))
+		return
+	}
+
+	// Key sets are served as is, verifiers expect the RFC 7517 format
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, res)
+}
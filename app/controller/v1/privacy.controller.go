@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/middleware"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/service"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+type privacyHandler struct {
+	services service.ServiceCollections
+	mws      middleware.MiddlewareCollections
+}
+
+func NewPrivacyControllerV1(router *gin.Engine, services service.ServiceCollections, mws middleware.MiddlewareCollections) {
+	handler := privacyHandler{services, mws}
+
+	// Personal data must only be handed out to or erased by the owner
+	group := router.Group("api/v1/privacy", mws.ImpersonationMw.Handler())
+	{
+		group.POST("/export", handler.requestExport)
+		group.POST("/erase", handler.requestErasure)
+		group.POST("/job", handler.getDataJob)
+		group.POST("/download", handler.downloadDataExport)
+	}
+}
+
+func (h *privacyHandler) requestExport(c *gin.Context) {
+	var req model.RequestDataExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.PrivacySvc.RequestExport(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.FormatSuccessResponse(res))
+}
+
+func (h *privacyHandler) requestErasure(c *gin.Context) {
+	var req model.RequestDataErasureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.PrivacySvc.RequestErasure(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.FormatSuccessResponse(res))
+}
+
+func (h *privacyHandler) getDataJob(c *gin.Context) {
+	var req model.GetDataJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.PrivacySvc.GetDataJob(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *privacyHandler) downloadDataExport(c *gin.Context) {
+	var req model.DownloadDataExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.PrivacySvc.DownloadDataExport(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.FileAttachment(res.FilePath, res.FileName)
+}
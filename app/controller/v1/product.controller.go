@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 
+	"sondth-test_soa/app/entity"
 	"sondth-test_soa/app/middleware"
 	"sondth-test_soa/app/model"
 	"sondth-test_soa/app/service"
@@ -25,14 +26,18 @@ func NewProductControllerV1(router *gin.Engine, services service.ServiceCollecti
 
 	group := router.Group("api/v1/product")
 	{
-		adminGroup := group.Group("/", mws.AdminMw.Handler())
+		adminGroup := group.Group("/", mws.PermissionMw.RequirePermission(entity.PERMISSION_PRODUCT_WRITE), mws.MfaMw.Handler())
 		{
 			adminGroup.POST("/create", handler.create)
 			adminGroup.POST("/update", handler.update)
 			adminGroup.POST("/delete", handler.delete)
+			adminGroup.POST("/variant/create", handler.createVariant)
+			adminGroup.POST("/variant/update", handler.updateVariant)
+			adminGroup.POST("/variant/delete", handler.deleteVariant)
 		}
 
 		group.POST("/list", handler.getProducts)
+		group.POST("/variant/list", handler.getVariants)
 	}
 }
 
@@ -40,7 +45,7 @@ func (h *productHandler) create(c *gin.Context) {
 	var req model.CreateProductRequest
 	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
 		resErr := errors.NewValidatorError(err)
-		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(resErr))
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
 		return
 	}
 
@@ -49,7 +54,7 @@ func (h *productHandler) create(c *gin.Context) {
 
 	res, err := h.services.ProductSvc.Create(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(err))
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
 		return
 	}
 
@@ -60,7 +65,7 @@ func (h *productHandler) update(c *gin.Context) {
 	var req model.UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		resErr := errors.NewValidatorError(err)
-		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(resErr))
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
 		return
 	}
 
@@ -69,7 +74,7 @@ func (h *productHandler) update(c *gin.Context) {
 
 	resp, err := h.services.ProductSvc.Update(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(err))
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
 		return
 	}
 
@@ -80,7 +85,7 @@ func (h *productHandler) delete(c *gin.Context) {
 	var req model.DeleteProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		resErr := errors.NewValidatorError(err)
-		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(resErr))
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
 		return
 	}
 
@@ -89,7 +94,7 @@ func (h *productHandler) delete(c *gin.Context) {
 
 	resp, err := h.services.ProductSvc.Delete(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(err))
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
 		return
 	}
 
@@ -100,7 +105,7 @@ func (h *productHandler) getProducts(c *gin.Context) {
 	var req model.GetProductRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		resErr := errors.NewValidatorError(err)
-		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(resErr))
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
 		return
 	}
 
@@ -109,7 +114,87 @@ func (h *productHandler) getProducts(c *gin.Context) {
 
 	resp, err := h.services.ProductSvc.GetProducts(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(err))
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
+
+func (h *productHandler) createVariant(c *gin.Context) {
+	var req model.CreateProductVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.ProductSvc.CreateVariant(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.FormatSuccessResponse(resp))
+}
+
+func (h *productHandler) updateVariant(c *gin.Context) {
+	var req model.UpdateProductVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.ProductSvc.UpdateVariant(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
+
+func (h *productHandler) deleteVariant(c *gin.Context) {
+	var req model.DeleteProductVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.ProductSvc.DeleteVariant(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
+
+func (h *productHandler) getVariants(c *gin.Context) {
+	var req model.GetProductVariantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.ProductSvc.GetVariants(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
 		return
 	}
 
@@ -0,0 +1,242 @@
+package v1
+
+import (
+	"context"
+	_errors "errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/middleware"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/service"
+	"sondth-test_soa/config"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+// avatarFormOverhead is the room left in avatar uploads for the multipart boundaries and headers
+const avatarFormOverhead = 64 << 10
+
+type profileHandler struct {
+	services      service.ServiceCollections
+	mws           middleware.MiddlewareCollections
+	avatarMaxSize int64
+}
+
+func NewProfileControllerV1(router *gin.Engine, services service.ServiceCollections, mws middleware.MiddlewareCollections, conf config.Configuration) {
+	handler := profileHandler{services, mws, conf.Storage.AvatarMaxSize}
+
+	group := router.Group("api/v1/user/me")
+	{
+		group.GET("/addresses", handler.getAddresses)
+		group.POST("/addresses/create", mws.ImpersonationMw.Handler(), handler.createAddress)
+		group.POST("/addresses/update", mws.ImpersonationMw.Handler(), handler.updateAddress)
+		group.POST("/addresses/delete", mws.ImpersonationMw.Handler(), handler.deleteAddress)
+		group.POST("/addresses/default", mws.ImpersonationMw.Handler(), handler.setDefaultAddress)
+		group.POST("/avatar", mws.ImpersonationMw.Handler(), handler.uploadAvatar)
+		group.POST("/avatar/delete", mws.ImpersonationMw.Handler(), handler.deleteAvatar)
+		group.GET("/preferences", handler.getPreferences)
+		group.POST("/preferences/update", mws.ImpersonationMw.Handler(), handler.updatePreferences)
+	}
+}
+
+func (h *profileHandler) getAddresses(c *gin.Context) {
+	var req model.GetAddressesRequest
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ProfileSvc.GetAddresses(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *profileHandler) createAddress(c *gin.Context) {
+	var req model.CreateAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ProfileSvc.CreateAddress(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *profileHandler) updateAddress(c *gin.Context) {
+	var req model.UpdateAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ProfileSvc.UpdateAddress(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *profileHandler) deleteAddress(c *gin.Context) {
+	var req model.DeleteAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ProfileSvc.DeleteAddress(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *profileHandler) setDefaultAddress(c *gin.Context) {
+	var req model.SetDefaultAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ProfileSvc.SetDefaultAddress(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *profileHandler) uploadAvatar(c *gin.Context) {
+	tooLarge := errors.Newf(errors.ErrCodeAvatarTooLarge, h.avatarMaxSize>>10)
+
+	// The body is cut past the avatar limit, with room for the multipart headers
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.avatarMaxSize+avatarFormOverhead)
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if _errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, tooLarge))
+			return
+		}
+		resErr := errors.Newf(errors.ErrCodeValidatorRequired, "Avatar")
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+	if fileHeader.Size > h.avatarMaxSize {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, tooLarge))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeAvatarInvalid)))
+		return
+	}
+	defer file.Close()
+
+	// One byte over the limit is enough to know the file is too large
+	content, err := io.ReadAll(io.LimitReader(file, h.avatarMaxSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeAvatarInvalid)))
+		return
+	}
+	if int64(len(content)) > h.avatarMaxSize {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, tooLarge))
+		return
+	}
+	req := model.UploadAvatarRequest{
+		Content: content,
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ProfileSvc.UploadAvatar(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *profileHandler) deleteAvatar(c *gin.Context) {
+	var req model.DeleteAvatarRequest
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ProfileSvc.DeleteAvatar(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *profileHandler) getPreferences(c *gin.Context) {
+	var req model.GetPreferencesRequest
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ProfileSvc.GetPreferences(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *profileHandler) updatePreferences(c *gin.Context) {
+	var req model.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.ProfileSvc.UpdatePreferences(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
@@ -36,13 +36,13 @@ func (h *reviewHandler) create(c *gin.Context) {
 
 	var req model.CreateReviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.ReviewSvc.Create(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -55,13 +55,13 @@ func (h *reviewHandler) delete(c *gin.Context) {
 
 	var req model.DeleteReviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.ReviewSvc.Delete(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -74,13 +74,13 @@ func (h *reviewHandler) getReviews(c *gin.Context) {
 
 	var req model.GetReviewsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.ReviewSvc.GetReviews(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -93,13 +93,13 @@ func (h *reviewHandler) getReviewsSummary(c *gin.Context) {
 
 	var req model.GetReviewsSummaryRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.ReviewSvc.GetReviewsSummary(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
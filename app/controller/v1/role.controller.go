@@ -0,0 +1,169 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/middleware"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/service"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+type roleHandler struct {
+	services service.ServiceCollections
+	mws      middleware.MiddlewareCollections
+}
+
+func NewRoleControllerV1(router *gin.Engine, services service.ServiceCollections, mws middleware.MiddlewareCollections) {
+	handler := roleHandler{services, mws}
+
+	group := router.Group("api/v1/role", mws.PermissionMw.RequirePermission(entity.PERMISSION_ROLE_MANAGE), mws.MfaMw.Handler())
+	{
+		group.POST("/create", handler.create)
+		group.POST("/update", handler.update)
+		group.POST("/delete", handler.delete)
+		group.POST("/list", handler.getRoles)
+		group.GET("/permissions", handler.getPermissions)
+		group.POST("/assign", handler.assignRole)
+		group.POST("/unassign", handler.unassignRole)
+	}
+}
+
+func (h *roleHandler) create(c *gin.Context) {
+	var req model.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	res, err := h.services.RoleSvc.Create(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.FormatSuccessResponse(res))
+}
+
+func (h *roleHandler) update(c *gin.Context) {
+	var req model.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.RoleSvc.Update(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
+
+func (h *roleHandler) delete(c *gin.Context) {
+	var req model.DeleteRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.RoleSvc.Delete(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
+
+func (h *roleHandler) getRoles(c *gin.Context) {
+	var req model.GetRolesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.RoleSvc.GetRoles(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
+
+func (h *roleHandler) getPermissions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.RoleSvc.GetPermissions(ctx, &model.GetPermissionsRequest{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
+
+func (h *roleHandler) assignRole(c *gin.Context) {
+	var req model.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.RoleSvc.AssignRole(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
+
+func (h *roleHandler) unassignRole(c *gin.Context) {
+	var req model.UnassignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resErr := errors.NewValidatorError(err)
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, resErr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, 30*time.Second)
+	defer cancel()
+
+	resp, err := h.services.RoleSvc.UnassignRole(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.FormatErrorResponse(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(resp))
+}
@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
+	"sondth-test_soa/app/entity"
 	"sondth-test_soa/app/middleware"
 	"sondth-test_soa/app/model"
 	"sondth-test_soa/app/service"
@@ -26,10 +28,35 @@ func NewUserControllerV1(router *gin.Engine, services service.ServiceCollections
 	{
 		group.POST("/register", handler.register)
 		group.POST("/login", handler.login)
+		group.POST("/login/mfa", handler.loginMfa)
+		group.POST("/oidc/authorize", handler.oidcAuthorize)
+		group.POST("/oidc/callback", handler.oidcCallback)
+		group.POST("/refresh", handler.refreshToken)
+		group.POST("/mfa/enroll", mws.ImpersonationMw.Handler(), handler.enrollMfa)
+		group.POST("/mfa/activate", mws.ImpersonationMw.Handler(), handler.activateMfa)
+		group.POST("/mfa/disable", mws.ImpersonationMw.Handler(), handler.disableMfa)
+		group.POST("/logout", handler.logout)
+		group.POST("/logout-all", mws.ImpersonationMw.Handler(), handler.logoutAll)
 		group.POST("/forget-password", handler.forgetPassword)
-		group.POST("/change-password", handler.changePassword)
-		group.POST("/update/:id", handler.updateUser)
-		group.POST("/list", handler.getUsers, mws.AdminMw.Handler())
+		group.POST("/reset-password", handler.resetPassword)
+		group.POST("/activate", handler.activateUser)
+		group.POST("/change-password", mws.ImpersonationMw.Handler(), handler.changePassword)
+		group.POST("/update/:id", mws.ImpersonationMw.Handler(), handler.updateUser)
+		group.GET("/me", handler.getMe)
+		group.POST("/me/update", mws.ImpersonationMw.Handler(), handler.updateProfile)
+		group.POST("/verify/send", mws.ImpersonationMw.Handler(), handler.sendVerificationCode)
+		group.POST("/verify/confirm", mws.ImpersonationMw.Handler(), handler.confirmVerificationCode)
+		group.GET("/sessions", handler.getSessions)
+		group.POST("/sessions/revoke", mws.ImpersonationMw.Handler(), handler.revokeSession)
+		group.POST("/sessions/revoke-all", mws.PermissionMw.RequirePermission(entity.PERMISSION_USER_WRITE), mws.MfaMw.Handler(), handler.revokeUserSessions)
+		group.POST("/status", mws.ImpersonationMw.Handler(), mws.PermissionMw.RequirePermission(entity.PERMISSION_USER_WRITE), mws.MfaMw.Handler(), handler.updateUserStatus)
+		group.POST("/unlock", mws.PermissionMw.RequirePermission(entity.PERMISSION_USER_WRITE), mws.MfaMw.Handler(), handler.unlockUser)
+		group.POST("/list", mws.PermissionMw.RequirePermission(entity.PERMISSION_USER_READ), mws.MfaMw.Handler(), handler.getUsers)
+		group.POST("/create", mws.ImpersonationMw.Handler(), mws.PermissionMw.RequirePermission(entity.PERMISSION_USER_WRITE), mws.MfaMw.Handler(), handler.createUser)
+		group.POST("/invite", mws.ImpersonationMw.Handler(), mws.PermissionMw.RequirePermission(entity.PERMISSION_USER_WRITE), mws.MfaMw.Handler(), handler.inviteUser)
+		group.POST("/role/bulk", mws.ImpersonationMw.Handler(), mws.PermissionMw.RequirePermission(entity.PERMISSION_USER_WRITE), mws.MfaMw.Handler(), handler.bulkUpdateUserRole)
+		group.POST("/impersonate", mws.ImpersonationMw.Handler(), mws.PermissionMw.RequirePermission(entity.PERMISSION_USER_IMPERSONATE), mws.MfaMw.Handler(), handler.impersonate)
+		group.POST("/impersonation/logs", mws.ImpersonationMw.Handler(), mws.PermissionMw.RequirePermission(entity.PERMISSION_USER_IMPERSONATE), mws.MfaMw.Handler(), handler.getImpersonationLogs)
 	}
 }
 
@@ -39,13 +66,13 @@ func (h *userHandler) register(c *gin.Context) {
 
 	var req model.UserRegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.UserService.Register(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -58,13 +85,170 @@ func (h *userHandler) login(c *gin.Context) {
 
 	var req model.UserLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
+	req.ClientInfo = newClientInfo(c)
 
 	res, err := h.services.UserService.Login(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) loginMfa(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.LoginMfaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+	req.ClientInfo = newClientInfo(c)
+
+	res, err := h.services.UserService.LoginMfa(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) oidcAuthorize(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.OidcAuthorizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.OidcAuthorize(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) oidcCallback(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.OidcCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+	req.ClientInfo = newClientInfo(c)
+
+	res, err := h.services.UserService.OidcCallback(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) refreshToken(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+	req.ClientInfo = newClientInfo(c)
+
+	res, err := h.services.UserService.RefreshToken(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) enrollMfa(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	res, err := h.services.UserService.EnrollMfa(ctx, &model.EnrollMfaRequest{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) activateMfa(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.ActivateMfaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.ActivateMfa(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) disableMfa(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.DisableMfaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.DisableMfa(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) logout(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	res, err := h.services.UserService.Logout(ctx, &model.LogoutRequest{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) logoutAll(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	res, err := h.services.UserService.LogoutAll(ctx, &model.LogoutAllRequest{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -77,13 +261,32 @@ func (h *userHandler) forgetPassword(c *gin.Context) {
 
 	var req model.ForgetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.UserService.ForgetPassword(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) resetPassword(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.ResetPassword(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -96,13 +299,13 @@ func (h *userHandler) changePassword(c *gin.Context) {
 
 	var req model.ChangeUserPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.UserService.ChangePassword(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -113,19 +316,137 @@ func (h *userHandler) updateUser(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c, 10*time.Second)
 	defer cancel()
 
-	var req model.UpdateUserRequest
-	if err := c.ShouldBindUri(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+	// gin can't bind a uuid.UUID from the path
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.Newf(errors.ErrCodeValidatorFormat, "ID")))
 		return
 	}
+
+	var req model.UpdateUserRequest
+	req.ID = id
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.UserService.UpdateUser(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) sendVerificationCode(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.SendVerificationCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.SendVerificationCode(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) confirmVerificationCode(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.ConfirmVerificationCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.ConfirmVerificationCode(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) getSessions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.GetSessionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.GetSessions(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) revokeSession(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.RevokeSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.RevokeSession(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) revokeUserSessions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.RevokeUserSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.RevokeUserSessions(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) unlockUser(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.UnlockUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.UnlockUser(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -138,13 +459,187 @@ func (h *userHandler) getUsers(c *gin.Context) {
 
 	var req model.GetUsersRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.UserService.GetUsers(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) updateUserStatus(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.UpdateUserStatus(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) impersonate(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.ImpersonateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+	req.ClientInfo = newClientInfo(c)
+
+	res, err := h.services.UserService.Impersonate(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) getImpersonationLogs(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.GetImpersonationLogsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.GetImpersonationLogs(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+// -------------------------------------------------------------------------------
+func newClientInfo(c *gin.Context) model.ClientInfo {
+	return model.ClientInfo{
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+func (h *userHandler) createUser(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.CreateUser(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) inviteUser(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.InviteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.InviteUser(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) activateUser(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.ActivateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.ActivateUser(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) bulkUpdateUserRole(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.BulkUpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.BulkUpdateUserRole(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) getMe(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	res, err := h.services.UserService.GetMe(ctx, &model.GetMeRequest{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.FormatSuccessResponse(res))
+}
+
+func (h *userHandler) updateProfile(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c, 10*time.Second)
+	defer cancel()
+
+	var req model.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
+		return
+	}
+
+	res, err := h.services.UserService.UpdateProfile(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -34,13 +34,13 @@ func (h *wishlistHandler) addToWishlist(c *gin.Context) {
 
 	var req model.AddToWishlistRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.WishlistSvc.AddToWishlist(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -53,13 +53,13 @@ func (h *wishlistHandler) deleteFromWishlist(c *gin.Context) {
 
 	var req model.DeleteFromWishlistRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.WishlistSvc.DeleteFromWishlist(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -72,13 +72,13 @@ func (h *wishlistHandler) getWishlists(c *gin.Context) {
 
 	var req model.GetWishlistsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.WishlistSvc.GetWishlists(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
@@ -91,13 +91,13 @@ func (h *wishlistHandler) getWishlistsSummary(c *gin.Context) {
 
 	var req model.GetWishlistsSummaryRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewValidatorError(err))
+		c.JSON(http.StatusBadRequest, errors.Localize(c, errors.NewValidatorError(err)))
 		return
 	}
 
 	res, err := h.services.WishlistSvc.GetWishlistsSummary(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(http.StatusBadRequest, errors.Localize(c, err))
 		return
 	}
 
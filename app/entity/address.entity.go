@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MAX_ADDRESSES_PER_USER is the size of the address book of a user
+const MAX_ADDRESSES_PER_USER = 20
+
+// Address is an entry of the address book of a user, the default one is used for shipping
+type Address struct {
+	ID         uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Label      *string   `json:"label" gorm:"varchar(64)"`
+	Recipient  string    `json:"recipient" gorm:"varchar(255);not null"`
+	Phone      string    `json:"phone" gorm:"column:phone_number;varchar(20);not null"`
+	Line1      string    `json:"line1" gorm:"column:line1;varchar(255);not null"`
+	Line2      *string   `json:"line2" gorm:"column:line2;varchar(255)"`
+	City       string    `json:"city" gorm:"varchar(128);not null"`
+	State      *string   `json:"state" gorm:"varchar(128)"`
+	PostalCode *string   `json:"postal_code" gorm:"varchar(32)"`
+	Country    string    `json:"country" gorm:"varchar(2);not null"`
+	IsDefault  bool      `json:"is_default" gorm:"not null;default:false"`
+	CreatedAt  int64     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  int64     `json:"updated_at" gorm:"autoUpdateTime:milli"`
+}
+
+func NewAddress(userID uuid.UUID) *Address {
+	return &Address{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CreatedAt: time.Now().Unix(),
+		UpdatedAt: time.Now().Unix(),
+	}
+}
+
+func (Address) TableName() string {
+	return "user_addresses"
+}
+
+func (e *Address) BeforeSave(tx *gorm.DB) (err error) {
+	e.UpdatedAt = time.Now().Unix()
+	return
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"sondth-test_soa/utils"
+)
+
+// ApiKey lets internal services call the API, only the hash of the key is stored
+type ApiKey struct {
+	ID          uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Name        string     `json:"name" gorm:"varchar(255);not null"`
+	Prefix      string     `json:"prefix" gorm:"varchar(32);not null"`
+	KeyHash     string     `json:"-" gorm:"varchar(64);not null;unique"`
+	Permissions []string   `json:"permissions" gorm:"serializer:json"`
+	CreatedBy   *uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	ExpiresAt   *int64     `json:"expires_at"`
+	LastUsedAt  *int64     `json:"last_used_at"`
+	RevokedAt   *int64     `json:"revoked_at"`
+	CreatedAt   int64      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   int64      `json:"updated_at" gorm:"autoUpdateTime:milli"`
+}
+
+func NewApiKey() *ApiKey {
+	return &ApiKey{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().Unix(),
+		UpdatedAt: time.Now().Unix(),
+	}
+}
+
+func (ApiKey) TableName() string {
+	return "api_keys"
+}
+
+func (e *ApiKey) BeforeSave(tx *gorm.DB) (err error) {
+	e.UpdatedAt = time.Now().Unix()
+	return
+}
+
+// SetKey stores the hash of key and the prefix shown to admins to tell keys apart
+func (e *ApiKey) SetKey(key string) {
+	e.KeyHash = utils.HashToken(key)
+	e.Prefix = key
+	if len(key) > len(utils.API_KEY_PREFIX)+8 {
+		e.Prefix = key[:len(utils.API_KEY_PREFIX)+8]
+	}
+}
+
+func (e *ApiKey) IsRevoked() bool {
+	return e.RevokedAt != nil
+}
+
+func (e *ApiKey) IsExpired() bool {
+	return e.ExpiresAt != nil && *e.ExpiresAt <= time.Now().Unix()
+}
+
+// Principal returns the service principal a request authenticated by the key acts as
+func (e *ApiKey) Principal() *ServicePrincipal {
+	return &ServicePrincipal{
+		ApiKeyID:    e.ID,
+		Name:        e.Name,
+		Permissions: e.Permissions,
+	}
+}
+
+// ServicePrincipal is the caller of a request authenticated by an API key, in place of a user
+type ServicePrincipal struct {
+	ApiKeyID    uuid.UUID `json:"api_key_id"`
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+}
+
+func (p *ServicePrincipal) HasPermission(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
+}
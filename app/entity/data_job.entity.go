@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DATA_JOB_TYPE_EXPORT  = "export"
+	DATA_JOB_TYPE_ERASURE = "erasure"
+)
+
+const (
+	DATA_JOB_STATUS_PENDING    = "pending"
+	DATA_JOB_STATUS_PROCESSING = "processing"
+	DATA_JOB_STATUS_COMPLETED  = "completed"
+	DATA_JOB_STATUS_FAILED     = "failed"
+)
+
+const (
+	DATA_EXPORT_FORMAT_JSON = "json"
+	DATA_EXPORT_FORMAT_ZIP  = "zip"
+)
+
+// DataJob is a background export or erasure of the personal data of a user
+type DataJob struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Type        string    `json:"type" gorm:"varchar(16);not null"`
+	Status      string    `json:"status" gorm:"varchar(16);not null"`
+	Format      *string   `json:"format" gorm:"varchar(8)"`
+	FilePath    *string   `json:"-" gorm:"varchar(512)"`
+	Error       *string   `json:"error" gorm:"text"`
+	ExpiresAt   *int64    `json:"expires_at"`
+	CompletedAt *int64    `json:"completed_at"`
+	CreatedAt   int64     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   int64     `json:"updated_at" gorm:"autoUpdateTime:milli"`
+}
+
+func NewDataJob(userID uuid.UUID, jobType string) *DataJob {
+	return &DataJob{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      jobType,
+		Status:    DATA_JOB_STATUS_PENDING,
+		CreatedAt: time.Now().Unix(),
+		UpdatedAt: time.Now().Unix(),
+	}
+}
+
+func (DataJob) TableName() string {
+	return "user_data_jobs"
+}
+
+func (e *DataJob) BeforeSave(tx *gorm.DB) (err error) {
+	e.UpdatedAt = time.Now().Unix()
+	return
+}
+
+func (e *DataJob) IsDone() bool {
+	return e.Status == DATA_JOB_STATUS_COMPLETED || e.Status == DATA_JOB_STATUS_FAILED
+}
+
+func (e *DataJob) IsExpired() bool {
+	return e.ExpiresAt != nil && *e.ExpiresAt <= time.Now().Unix()
+}
+
+// Complete marks the job as done, the error message is kept when it failed
+func (e *DataJob) Complete(err error) {
+	now := time.Now().Unix()
+	e.CompletedAt = &now
+	if err != nil {
+		message := err.Error()
+		e.Status = DATA_JOB_STATUS_FAILED
+		e.Error = &message
+		return
+	}
+
+	e.Status = DATA_JOB_STATUS_COMPLETED
+	e.Error = nil
+}
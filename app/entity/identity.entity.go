@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Identity links an account of an external OpenID Connect provider to a user
+type Identity struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Provider    string    `json:"provider" gorm:"varchar(64);not null"`
+	Subject     string    `json:"subject" gorm:"varchar(255);not null"`
+	Email       *string   `json:"email" gorm:"varchar(255)"`
+	LastLoginAt int64     `json:"last_login_at" gorm:"not null"`
+	CreatedAt   int64     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   int64     `json:"updated_at" gorm:"autoUpdateTime:milli"`
+}
+
+func NewIdentity(userID uuid.UUID, provider string, subject string) *Identity {
+	return &Identity{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Provider:    provider,
+		Subject:     subject,
+		LastLoginAt: time.Now().Unix(),
+		CreatedAt:   time.Now().Unix(),
+		UpdatedAt:   time.Now().Unix(),
+	}
+}
+
+func (Identity) TableName() string {
+	return "user_identities"
+}
+
+func (e *Identity) BeforeSave(tx *gorm.DB) (err error) {
+	e.UpdatedAt = time.Now().Unix()
+	return
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	IMPERSONATION_ACTION_START   = "start"
+	IMPERSONATION_ACTION_REQUEST = "request"
+)
+
+// ImpersonationLog records an admin acting as a user, from issuing the token to every request made with it
+type ImpersonationLog struct {
+	ID             uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	ImpersonatorID uuid.UUID `json:"impersonator_id" gorm:"type:uuid;not null"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Action         string    `json:"action" gorm:"varchar(32);not null"`
+	Method         string    `json:"method" gorm:"varchar(16)"`
+	Path           string    `json:"path" gorm:"varchar(255)"`
+	StatusCode     int       `json:"status_code"`
+	ClientIP       string    `json:"client_ip" gorm:"varchar(64)"`
+	UserAgent      string    `json:"user_agent" gorm:"varchar(512)"`
+	CreatedAt      int64     `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func NewImpersonationLog(impersonatorID uuid.UUID, userID uuid.UUID, action string) *ImpersonationLog {
+	return &ImpersonationLog{
+		ID:             uuid.New(),
+		ImpersonatorID: impersonatorID,
+		UserID:         userID,
+		Action:         action,
+		CreatedAt:      time.Now().Unix(),
+	}
+}
+
+func (ImpersonationLog) TableName() string {
+	return "impersonation_logs"
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordHistory is a previous password hash of a user, used to prevent password reuse
+type PasswordHistory struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Password  string    `json:"-" gorm:"varchar(255);not null"`
+	CreatedAt int64     `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func NewPasswordHistory(userID uuid.UUID, passwordHash string) *PasswordHistory {
+	return &PasswordHistory{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Password:  passwordHash,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
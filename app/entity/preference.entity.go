@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_CURRENCY = "VND"
+)
+
+// Preference holds the settings of a user, users who never changed them get NewPreference
+type Preference struct {
+	UserID          uuid.UUID `json:"user_id" gorm:"primaryKey;type:uuid"`
+	Language        string    `json:"language" gorm:"varchar(8);not null"`
+	Currency        string    `json:"currency" gorm:"varchar(3);not null"`
+	NotifyEmail     bool      `json:"notify_email" gorm:"not null;default:true"`
+	NotifySms       bool      `json:"notify_sms" gorm:"not null;default:false"`
+	NotifyMarketing bool      `json:"notify_marketing" gorm:"not null;default:false"`
+	CreatedAt       int64     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       int64     `json:"updated_at" gorm:"autoUpdateTime:milli"`
+}
+
+func NewPreference(userID uuid.UUID, language string) *Preference {
+	return &Preference{
+		UserID:      userID,
+		Language:    language,
+		Currency:    DEFAULT_CURRENCY,
+		NotifyEmail: true,
+		CreatedAt:   time.Now().Unix(),
+		UpdatedAt:   time.Now().Unix(),
+	}
+}
+
+func (Preference) TableName() string {
+	return "user_preferences"
+}
+
+func (e *Preference) BeforeSave(tx *gorm.DB) (err error) {
+	e.UpdatedAt = time.Now().Unix()
+	return
+}
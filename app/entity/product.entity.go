@@ -11,32 +11,40 @@ import (
 var (
 	PRODUCT_STATUS_IN_STOCK     = "in_stock"
 	PRODUCT_STATUS_OUT_OF_STOCK = "out_of_stock"
+	PRODUCT_STATUS_LOW_STOCK    = "low_stock" // still in stock, at or below the low-stock threshold
 )
 
+// DEFAULT_LOW_STOCK_THRESHOLD is the low-stock threshold of products created without one
+const DEFAULT_LOW_STOCK_THRESHOLD = 10
+
 type Product struct {
-	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	Name        string    `json:"name" gorm:"varchar(255);not null"`
-	NameSlug    string    `json:"-" gorm:"varchar(255);not null"`
-	Description *string   `json:"description" gorm:"text"`
-	Image       *string   `json:"image" gorm:"varchar(255)"`
-	Price       float64   `json:"price" gorm:"type:decimal(10,2);not null"`
-	Quantity    uint64    `json:"quantity" gorm:"type:bigint unsigned;not null"`
-	CreatedAt   int64     `json:"created_at,omitempty" gorm:"autoCreateTime"`
-	UpdatedAt   int64     `json:"updated_at,omitempty" gorm:"autoUpdateTime:milli"`
+	ID                uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Name              string    `json:"name" gorm:"varchar(255);not null"`
+	NameSlug          string    `json:"-" gorm:"varchar(255);not null"`
+	Description       *string   `json:"description" gorm:"text"`
+	Image             *string   `json:"image" gorm:"varchar(255)"`
+	Price             float64   `json:"price" gorm:"type:decimal(10,2);not null"`
+	Quantity          uint64    `json:"quantity" gorm:"type:bigint unsigned;not null;->"`     // available, only changed by stock movements
+	Reserved          uint64    `json:"reserved_quantity" gorm:"column:reserved_quantity;->"` // set aside for orders being placed
+	LowStockThreshold uint64    `json:"low_stock_threshold" gorm:"column:low_stock_threshold;not null"`
+	CreatedAt         int64     `json:"created_at,omitempty" gorm:"autoCreateTime"`
+	UpdatedAt         int64     `json:"updated_at,omitempty" gorm:"autoUpdateTime:milli"`
 
 	// Relations
 	CategoryID uuid.UUID `json:"category_id" gorm:"type:uuid;not null"`
 	Category   Category  `json:"category"`
 
 	// Response fields
-	Status string `json:"status" gorm:"-:all"`
+	Status   string                 `json:"status" gorm:"-:all"`
+	Variants *ProductVariantSummary `json:"variants,omitempty" gorm:"-:all"` // empty when the product has no variants
 }
 
 func NewProduct() *Product {
 	return &Product{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().Unix(),
-		UpdatedAt: time.Now().Unix(),
+		ID:                uuid.New(),
+		LowStockThreshold: DEFAULT_LOW_STOCK_THRESHOLD,
+		CreatedAt:         time.Now().Unix(),
+		UpdatedAt:         time.Now().Unix(),
 	}
 }
 
@@ -44,6 +52,23 @@ func (Product) TableName() string {
 	return "products"
 }
 
+// SetStatus sets the stock status of the response, a product with variants is in stock while any variant is
+func (e *Product) SetStatus() {
+	inStock := e.Quantity > 0
+	if e.Variants != nil {
+		inStock = e.Variants.InStock
+	}
+
+	switch {
+	case !inStock:
+		e.Status = PRODUCT_STATUS_OUT_OF_STOCK
+	case e.Quantity > 0 && e.Quantity <= e.LowStockThreshold:
+		e.Status = PRODUCT_STATUS_LOW_STOCK
+	default:
+		e.Status = PRODUCT_STATUS_IN_STOCK
+	}
+}
+
 func (e *Product) BeforeSave(tx *gorm.DB) error {
 	e.UpdatedAt = time.Now().Unix()
 	if e.Name != "" {
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductVariant is a sellable version of a product, e.g: the shirt in size M and color red
+type ProductVariant struct {
+	ID        uuid.UUID         `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	ProductID uuid.UUID         `json:"product_id" gorm:"type:uuid;not null"`
+	Sku       string            `json:"sku" gorm:"varchar(64);not null;unique"`
+	Options   map[string]string `json:"options" gorm:"serializer:json;type:jsonb;not null"`   // e.g: {"size": "M", "color": "red"}
+	Price     *float64          `json:"price" gorm:"type:decimal(10,2)"`                      // the price of the product when empty
+	Quantity  uint64            `json:"quantity" gorm:"type:bigint unsigned;not null;->"`     // available, only changed by stock movements
+	Reserved  uint64            `json:"reserved_quantity" gorm:"column:reserved_quantity;->"` // set aside for orders being placed
+	CreatedAt int64             `json:"created_at,omitempty" gorm:"autoCreateTime"`
+	UpdatedAt int64             `json:"updated_at,omitempty" gorm:"autoUpdateTime:milli"`
+
+	// Response fields
+	Status string `json:"status" gorm:"-:all"`
+}
+
+// ProductVariantSummary sums up the variants of a product for product lists
+type ProductVariantSummary struct {
+	ProductID    uuid.UUID `json:"-"`
+	VariantCount int64     `json:"variant_count"`
+	MinPrice     float64   `json:"min_price"`
+	MaxPrice     float64   `json:"max_price"`
+	InStock      bool      `json:"in_stock"`
+}
+
+func NewProductVariant(productID uuid.UUID) *ProductVariant {
+	return &ProductVariant{
+		ID:        uuid.New(),
+		ProductID: productID,
+		CreatedAt: time.Now().Unix(),
+		UpdatedAt: time.Now().Unix(),
+	}
+}
+
+func (ProductVariant) TableName() string {
+	return "product_variants"
+}
+
+func (e *ProductVariant) BeforeSave(tx *gorm.DB) error {
+	e.UpdatedAt = time.Now().Unix()
+	return nil
+}
+
+// SetStatus sets the stock status of the response
+func (e *ProductVariant) SetStatus() {
+	if e.Quantity > 0 {
+		e.Status = PRODUCT_STATUS_IN_STOCK
+	} else {
+		e.Status = PRODUCT_STATUS_OUT_OF_STOCK
+	}
+}
+
+// HasOptions reports whether the variant is the one of these option values
+func (e *ProductVariant) HasOptions(options map[string]string) bool {
+	return maps.Equal(e.Options, options)
+}
@@ -37,3 +37,13 @@ func (e *Review) BeforeSave(tx *gorm.DB) (err error) {
 	e.UpdatedAt = time.Now().Unix()
 	return
 }
+
+// AnonymizeDeletedAuthor keeps reviews of deleted users without telling who wrote them
+func (e *Review) AnonymizeDeletedAuthor() {
+	if !e.User.IsDeleted() {
+		return
+	}
+
+	e.UserID = uuid.Nil
+	e.User.Anonymize()
+}
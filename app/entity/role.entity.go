@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PERMISSION_PRODUCT_WRITE    = "product:write"
+	PERMISSION_CATEGORY_WRITE   = "category:write"
+	PERMISSION_USER_READ        = "user:read"
+	PERMISSION_USER_WRITE       = "user:write"
+	PERMISSION_REVIEW_MODERATE  = "review:moderate"
+	PERMISSION_ROLE_MANAGE      = "role:manage"
+	PERMISSION_API_KEY_MANAGE   = "api_key:manage"
+	PERMISSION_USER_IMPERSONATE = "user:impersonate"
+	PERMISSION_INVENTORY_MANAGE = "inventory:manage"
+)
+
+type Role struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Name        string    `json:"name" gorm:"varchar(255);not null;unique"`
+	Description *string   `json:"description" gorm:"text"`
+	CreatedAt   int64     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   int64     `json:"updated_at" gorm:"autoUpdateTime:milli"`
+
+	// Relations
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions;"`
+}
+
+func NewRole() *Role {
+	return &Role{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().Unix(),
+		UpdatedAt: time.Now().Unix(),
+	}
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+func (e *Role) BeforeSave(tx *gorm.DB) (err error) {
+	e.UpdatedAt = time.Now().Unix()
+	return
+}
+
+// IsBuiltIn reports whether the role is one of the default roles every user relies on
+func (e *Role) IsBuiltIn() bool {
+	return e.Name == ROLE_ADMIN || e.Name == ROLE_USER
+}
+
+type Permission struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Code        string    `json:"code" gorm:"varchar(255);not null;unique"`
+	Description *string   `json:"description" gorm:"text"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+type UserRole struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"primaryKey;type:uuid"`
+	RoleID    uuid.UUID `json:"role_id" gorm:"primaryKey;type:uuid"`
+	CreatedAt int64     `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func NewUserRole(userID uuid.UUID, roleID uuid.UUID) *UserRole {
+	return &UserRole{
+		UserID:    userID,
+		RoleID:    roleID,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session is one signed in device, its ID is the refresh token family ID
+type Session struct {
+	ID         uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	UserAgent  string    `json:"user_agent" gorm:"text"`
+	IPAddress  string    `json:"ip_address" gorm:"varchar(64)"`
+	CreatedAt  int64     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  int64     `json:"updated_at" gorm:"autoUpdateTime:milli"`
+	LastSeenAt int64     `json:"last_seen_at" gorm:"not null"`
+	RevokedAt  *int64    `json:"revoked_at"`
+}
+
+func NewSession(userID uuid.UUID, userAgent string, ipAddress string) *Session {
+	return &Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  time.Now().Unix(),
+		UpdatedAt:  time.Now().Unix(),
+		LastSeenAt: time.Now().Unix(),
+	}
+}
+
+func (Session) TableName() string {
+	return "user_sessions"
+}
+
+func (e *Session) BeforeSave(tx *gorm.DB) (err error) {
+	e.UpdatedAt = time.Now().Unix()
+	return
+}
+
+func (e *Session) IsRevoked() bool {
+	return e.RevokedAt != nil
+}
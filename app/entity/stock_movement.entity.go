@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stock movement types. Reserved stock is set aside for orders being placed,
+// it's either sold or released back to the available quantity.
+const (
+	STOCK_MOVEMENT_RECEIVE = "receive" // goods arrived, adds to the available quantity
+	STOCK_MOVEMENT_ADJUST  = "adjust"  // stock count correction, the quantity can be negative
+	STOCK_MOVEMENT_RESERVE = "reserve" // moves available quantity to reserved
+	STOCK_MOVEMENT_RELEASE = "release" // moves reserved quantity back to available
+	STOCK_MOVEMENT_SELL    = "sell"    // removes reserved quantity for good
+)
+
+// STOCK_REASON_INITIAL is the reason of the stock received when a product or variant is created
+const STOCK_REASON_INITIAL = "Initial stock"
+
+const (
+	STOCK_ACTOR_USER    = "user"
+	STOCK_ACTOR_API_KEY = "api_key"
+)
+
+// StockMovement is an entry of the stock ledger, balances of products and variants only change through movements
+type StockMovement struct {
+	ID            uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	ProductID     uuid.UUID  `json:"product_id" gorm:"type:uuid;not null"`
+	VariantID     *uuid.UUID `json:"variant_id" gorm:"type:uuid"`
+	Type          string     `json:"type" gorm:"varchar(16);not null"`
+	Quantity      int64      `json:"quantity" gorm:"not null"`
+	BalanceAfter  uint64     `json:"balance_after" gorm:"not null"`  // available quantity of the product or variant after the movement
+	ReservedAfter uint64     `json:"reserved_after" gorm:"not null"` // reserved quantity of the product or variant after the movement
+	Reason        string     `json:"reason" gorm:"text;not null"`
+	ActorType     string     `json:"actor_type" gorm:"varchar(16);not null"`
+	ActorID       uuid.UUID  `json:"actor_id" gorm:"type:uuid;not null"`
+	CreatedAt     int64      `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func NewStockMovement(productID uuid.UUID, movementType string, quantity int64, reason string) *StockMovement {
+	return &StockMovement{
+		ID:        uuid.New(),
+		ProductID: productID,
+		Type:      movementType,
+		Quantity:  quantity,
+		Reason:    reason,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
+
+// Changes returns how much the movement changes the available and the reserved quantity
+func (m *StockMovement) Changes() (available int64, reserved int64) {
+	switch m.Type {
+	case STOCK_MOVEMENT_RECEIVE, STOCK_MOVEMENT_ADJUST:
+		return m.Quantity, 0
+	case STOCK_MOVEMENT_RESERVE:
+		return -m.Quantity, m.Quantity
+	case STOCK_MOVEMENT_RELEASE:
+		return m.Quantity, -m.Quantity
+	case STOCK_MOVEMENT_SELL:
+		return 0, -m.Quantity
+	default:
+		return 0, 0
+	}
+}
@@ -1,7 +1,9 @@
 package entity
 
 import (
+	"sondth-test_soa/package/totp"
 	"sondth-test_soa/utils"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,19 +15,55 @@ const (
 	ROLE_USER  = "user"
 )
 
+const (
+	USER_STATUS_ACTIVE    = "active"
+	USER_STATUS_SUSPENDED = "suspended"
+	USER_STATUS_DELETED   = "deleted"
+	USER_STATUS_INVITED   = "invited" // created by an admin, active once the activation link is used
+)
+
+const (
+	ANONYMIZED_USERNAME = "deleted_user"
+	ANONYMIZED_FULLNAME = "Deleted user"
+)
+
+const (
+	VERIFICATION_CHANNEL_EMAIL = "email"
+	VERIFICATION_CHANNEL_PHONE = "phone"
+)
+
 type User struct {
 	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
 	Username  string    `json:"username" gorm:"varchar(255);not null;unique"`
-	Password  string    `json:"password" gorm:"varchar(255);not null"`
+	Password  string    `json:"-" gorm:"varchar(255);not null"`
 	Fullname  string    `json:"fullname" gorm:"varchar(255);not null"`
 	Role      string    `json:"role" gorm:"varchar(255);not null"`
+	Email     *string   `json:"email" gorm:"varchar(255);unique"`
+	Phone     *string   `json:"phone" gorm:"column:phone_number;varchar(20);unique"`
 	CreatedAt int64     `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt int64     `json:"updated_at" gorm:"autoUpdateTime:milli"`
+
+	EmailVerified bool `json:"email_verified" gorm:"not null;default:false"`
+	PhoneVerified bool `json:"phone_verified" gorm:"not null;default:false"`
+
+	MfaEnabled       bool     `json:"mfa_enabled" gorm:"not null;default:false"`
+	MfaSecret        *string  `json:"-" gorm:"varchar(255)"`
+	MfaRecoveryCodes []string `json:"-" gorm:"serializer:json"`
+	MfaLastStep      *int64   `json:"-"` // time step of the last TOTP code used, no code of it or before is accepted again
+
+	Status         string  `json:"status" gorm:"varchar(16);not null;default:active"`
+	StatusReason   *string `json:"status_reason" gorm:"text"`
+	SuspendedUntil *int64  `json:"suspended_until"`
+	DeletedAt      *int64  `json:"deleted_at"`
+
+	AvatarKey *string `json:"-" gorm:"varchar(512)"`
+	AvatarURL *string `json:"avatar_url" gorm:"varchar(1024)"`
 }
 
 func NewUser() *User {
 	return &User{
 		ID:        uuid.New(),
+		Status:    USER_STATUS_ACTIVE,
 		CreatedAt: time.Now().Unix(),
 		UpdatedAt: time.Now().Unix(),
 	}
@@ -37,20 +75,157 @@ func (User) TableName() string {
 
 func (u *User) BeforeSave(tx *gorm.DB) (err error) {
 	u.UpdatedAt = time.Now().Unix()
-	if u.Password != "" {
-		u.Password, err = utils.HashPassword(u.Password)
-		if err != nil {
-			return err
-		}
+	return
+}
+
+// SetPassword replaces the password with the hash of a plain one, whatever it looks like
+func (u *User) SetPassword(password string) error {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
 	}
 
-	return
+	u.Password = hash
+	return nil
 }
 
 func (u *User) CheckPassword(password string) error {
 	return utils.CheckPasswordHash(password, u.Password)
 }
 
+// PasswordNeedsRehash reports whether the stored hash uses an outdated algorithm or parameters
+func (u *User) PasswordNeedsRehash() bool {
+	return utils.PasswordNeedsRehash(u.Password)
+}
+
+// VerificationTarget returns the address codes are sent to for a channel
+func (u *User) VerificationTarget(channel string) (string, bool) {
+	switch channel {
+	case VERIFICATION_CHANNEL_EMAIL:
+		if u.Email != nil && *u.Email != "" {
+			return *u.Email, true
+		}
+	case VERIFICATION_CHANNEL_PHONE:
+		if u.Phone != nil && *u.Phone != "" {
+			return *u.Phone, true
+		}
+	}
+
+	return "", false
+}
+
+func (u *User) IsVerified(channel string) bool {
+	switch channel {
+	case VERIFICATION_CHANNEL_EMAIL:
+		return u.EmailVerified
+	case VERIFICATION_CHANNEL_PHONE:
+		return u.PhoneVerified
+	default:
+		return false
+	}
+}
+
+func (u *User) SetVerified(channel string) {
+	switch channel {
+	case VERIFICATION_CHANNEL_EMAIL:
+		u.EmailVerified = true
+	case VERIFICATION_CHANNEL_PHONE:
+		u.PhoneVerified = true
+	}
+}
+
 func (u *User) IsAdmin() bool {
 	return u.Role == ROLE_ADMIN
 }
+
+// VerifyMfaCode accepts either a TOTP code or an unused recovery code.
+// A matched recovery code is removed and the step of a TOTP code is kept, so neither can be used twice.
+func (u *User) VerifyMfaCode(code string) bool {
+	if u.MfaSecret == nil {
+		return false
+	}
+	if step, ok := totp.ValidateStep(*u.MfaSecret, code, time.Now()); ok {
+		if u.MfaLastStep != nil && step <= *u.MfaLastStep {
+			return false
+		}
+		u.MfaLastStep = &step
+		return true
+	}
+
+	hashedCode := utils.HashToken(strings.ToLower(strings.TrimSpace(code)))
+	for i, recoveryCode := range u.MfaRecoveryCodes {
+		if recoveryCode == hashedCode {
+			u.MfaRecoveryCodes = append(u.MfaRecoveryCodes[:i], u.MfaRecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSuspended reports whether the user is suspended, a suspension with an end time is lifted once it has passed
+func (u *User) IsSuspended() bool {
+	if u.Status != USER_STATUS_SUSPENDED {
+		return false
+	}
+
+	return u.SuspendedUntil == nil || *u.SuspendedUntil > time.Now().Unix()
+}
+
+func (u *User) IsDeleted() bool {
+	return u.Status == USER_STATUS_DELETED
+}
+
+func (u *User) IsInvited() bool {
+	return u.Status == USER_STATUS_INVITED
+}
+
+// SetStatus changes the status, the reason and end time only apply to the new status
+func (u *User) SetStatus(status string, reason *string, suspendedUntil *int64) {
+	u.Status = status
+	u.StatusReason = reason
+	u.SuspendedUntil = nil
+	u.DeletedAt = nil
+
+	switch status {
+	case USER_STATUS_SUSPENDED:
+		u.SuspendedUntil = suspendedUntil
+	case USER_STATUS_DELETED:
+		now := time.Now().Unix()
+		u.DeletedAt = &now
+	}
+}
+
+// Anonymize hides who the user was, e.g: the author of reviews of a deleted user
+func (u *User) Anonymize() {
+	u.ID = uuid.Nil
+	u.Username = ANONYMIZED_USERNAME
+	u.Fullname = ANONYMIZED_FULLNAME
+	u.Email = nil
+	u.Phone = nil
+	u.AvatarKey = nil
+	u.AvatarURL = nil
+}
+
+// Erase removes the personal data of the user for good, the record stays so reviews keep their author
+func (u *User) Erase(password string) error {
+	if err := u.SetPassword(password); err != nil {
+		return err
+	}
+
+	// Usernames are unique, the ID keeps the anonymized one unique too
+	u.Username = ANONYMIZED_USERNAME + "_" + strings.ReplaceAll(u.ID.String(), "-", "")
+	u.Fullname = ANONYMIZED_FULLNAME
+	u.Email = nil
+	u.Phone = nil
+	u.EmailVerified = false
+	u.PhoneVerified = false
+	u.MfaEnabled = false
+	u.MfaSecret = nil
+	u.MfaRecoveryCodes = nil
+	u.MfaLastStep = nil
+	u.AvatarKey = nil
+	u.AvatarURL = nil
+	u.SetStatus(USER_STATUS_DELETED, nil, nil)
+	return nil
+}
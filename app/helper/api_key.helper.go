@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+	"sondth-test_soa/package/errors"
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/utils"
+)
+
+type apiKeyHelper struct {
+	postgresRepo repository.RepositoryCollections
+}
+
+func NewApiKeyHelper(postgresRepo repository.RepositoryCollections) IApiKeyHelper {
+	return &apiKeyHelper{
+		postgresRepo: postgresRepo,
+	}
+}
+
+// GenerateApiKey returns a new random key, it is only shown once to the admin creating it
+func (h *apiKeyHelper) GenerateApiKey() (string, error) {
+	token, err := utils.GenerateRandomToken(utils.API_KEY_LENGTH)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.API_KEY_PREFIX + token, nil
+}
+
+// Authenticate returns the service principal of an active key and tracks when it was last used
+func (h *apiKeyHelper) Authenticate(ctx context.Context, key string) (*entity.ServicePrincipal, error) {
+	keyHash := utils.HashToken(key)
+	active := true
+	apiKey, err := h.postgresRepo.ApiKeyRepo.FindOneByFilter(ctx, nil, &repository.FindApiKeyByFilter{
+		KeyHash: &keyHash,
+		Active:  &active,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeInvalidApiKey)
+		}
+		return nil, err
+	}
+	if apiKey.IsExpired() {
+		return nil, errors.New(errors.ErrCodeInvalidApiKey)
+	}
+
+	// Written at most once per API_KEY_LAST_USED_IAT, a failure doesn't fail the request
+	now := time.Now().Unix()
+	if apiKey.LastUsedAt == nil || now-*apiKey.LastUsedAt >= utils.API_KEY_LAST_USED_IAT {
+		apiKey.LastUsedAt = &now
+		if err := h.postgresRepo.ApiKeyRepo.UpdateLastUsedAt(ctx, nil, apiKey); err != nil {
+			logger.WithCtx(ctx).Error(
+				"UpdateLastUsedAt",
+				slog.String("api_key_id", apiKey.ID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	return apiKey.Principal(), nil
+}
@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"sondth-test_soa/config"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/package/storage"
+)
+
+var avatarExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/webp": "webp",
+}
+
+type avatarHelper struct {
+	storage storage.IStorage
+	config  config.Configuration
+}
+
+func NewAvatarHelper(storage storage.IStorage, config config.Configuration) IAvatarHelper {
+	return &avatarHelper{
+		storage: storage,
+		config:  config,
+	}
+}
+
+// Upload checks the image and stores it under a new key, so a cached URL never shows an outdated avatar
+func (h *avatarHelper) Upload(ctx context.Context, userID uuid.UUID, content []byte) (string, string, error) {
+	if int64(len(content)) > h.config.Storage.AvatarMaxSize {
+		return "", "", errors.Newf(errors.ErrCodeAvatarTooLarge, h.config.Storage.AvatarMaxSize>>10)
+	}
+
+	// The type is sniffed from the content, the name and header of the upload are up to the client
+	contentType := http.DetectContentType(content)
+	extension, ok := avatarExtensions[contentType]
+	if !ok {
+		return "", "", errors.New(errors.ErrCodeAvatarInvalid)
+	}
+
+	key := fmt.Sprintf("avatars/%s/%d.%s", userID.String(), time.Now().UnixNano(), extension)
+	if err := h.storage.Put(ctx, key, bytes.NewReader(content), contentType); err != nil {
+		return "", "", err
+	}
+
+	return key, h.storage.URL(key), nil
+}
+
+func (h *avatarHelper) Remove(ctx context.Context, key string) error {
+	return h.storage.Delete(ctx, key)
+}
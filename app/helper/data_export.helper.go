@@ -0,0 +1,111 @@
+package helper
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/config"
+)
+
+type dataExportHelper struct {
+	config config.Configuration
+}
+
+func NewDataExportHelper(config config.Configuration) IDataExportHelper {
+	return &dataExportHelper{
+		config: config,
+	}
+}
+
+// WriteExport writes data to privacy.export_dir in the format of the job, the job gets the file path and its expiry
+func (h *dataExportHelper) WriteExport(ctx context.Context, job *entity.DataJob, data *model.UserDataExport) error {
+	format := entity.DATA_EXPORT_FORMAT_JSON
+	if job.Format != nil {
+		format = *job.Format
+	}
+
+	if err := os.MkdirAll(h.config.Privacy.ExportDir, 0o700); err != nil {
+		return err
+	}
+	path := filepath.Join(h.config.Privacy.ExportDir, fmt.Sprintf("%s.%s", job.ID.String(), format))
+
+	var err error
+	switch format {
+	case entity.DATA_EXPORT_FORMAT_ZIP:
+		err = writeZipExport(path, data)
+	default:
+		err = writeJSONFile(path, data)
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+
+	expiresAt := time.Now().Unix() + h.config.Privacy.ExportTTL
+	job.FilePath = &path
+	job.ExpiresAt = &expiresAt
+
+	return nil
+}
+
+// RemoveExport deletes an export file, a file that is already gone is not an error
+func (h *dataExportHelper) RemoveExport(ctx context.Context, path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------------------
+func writeJSONFile(path string, data any) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func writeZipExport(path string, data *model.UserDataExport) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	entries := []struct {
+		name string
+		data any
+	}{
+		{name: "profile.json", data: data.Profile},
+		{name: "reviews.json", data: data.Reviews},
+		{name: "wishlists.json", data: data.Wishlists},
+		{name: "addresses.json", data: data.Addresses},
+		{name: "preference.json", data: data.Preference},
+	}
+	for _, entry := range entries {
+		writer, err := archive.Create(entry.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(entry.data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
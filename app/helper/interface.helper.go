@@ -4,9 +4,12 @@ import (
 	"context"
 	"sondth-test_soa/app/entity"
 	"sondth-test_soa/app/model"
+	"sondth-test_soa/package/jwks"
+	"sondth-test_soa/package/worker"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ICategoryHelper interface {
@@ -18,8 +21,24 @@ type IProductHelper interface {
 }
 
 type IOAuthHelper interface {
-	GenerateAccessToken(user entity.User) (string, error)
-	GenerateRefreshToken(user entity.User) (string, error)
+	GenerateAccessToken(ctx context.Context, user entity.User, session model.TokenSession) (string, error)
+	GenerateRefreshToken(ctx context.Context, user entity.User, session model.TokenSession) (string, error)
+	GenerateImpersonationToken(ctx context.Context, impersonatorID uuid.UUID, user entity.User, session model.TokenSession) (string, error)
+	RotateRefreshToken(ctx context.Context, tokenString string) (*model.UserJWTPayload, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeAccessToken(ctx context.Context, payload *model.UserJWTPayload) error
+	RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error
+	IsAccessTokenRevoked(ctx context.Context, payload *model.UserJWTPayload) (bool, error)
+	GeneratePasswordResetToken(ctx context.Context, userID uuid.UUID) (string, error)
+	ConsumePasswordResetToken(ctx context.Context, token string) (uuid.UUID, error)
+	GenerateInvitationToken(ctx context.Context, userID uuid.UUID) (string, error)
+	ConsumeInvitationToken(ctx context.Context, token string) (uuid.UUID, error)
+	GenerateMfaChallengeToken(ctx context.Context, userID uuid.UUID) (string, error)
+	VerifyMfaChallengeToken(ctx context.Context, token string) (uuid.UUID, error)
+	RevokeMfaChallengeToken(ctx context.Context, token string) error
+	GetMfaProvisioningURI(account string, secret string) string
+	GetJSONWebKeys() []jwks.JSONWebKey
 	VerifyAccessToken(tokenString string) (*model.UserJWTPayload, error)
 	VerifyRefreshToken(tokenString string) (*model.UserJWTPayload, error)
 
@@ -29,5 +48,53 @@ type IOAuthHelper interface {
 
 type IUserHelper interface {
 	IsValidRole(role string) bool
-	IsValidPermission(ctx context.Context, userID uuid.UUID) bool
+	GetPermissions(ctx context.Context, user *entity.User) ([]string, error)
+	HasPermission(ctx context.Context, user *entity.User, permission string) (bool, error)
+	GetStaffPermissions(ctx context.Context, user *entity.User) ([]string, error)
+}
+
+type INotificationHelper interface {
+	SendPasswordResetToken(ctx context.Context, user entity.User, token string) error
+	SendVerificationCode(ctx context.Context, channel string, target string, code string) error
+	SendInvitation(ctx context.Context, user entity.User, token string) error
+}
+
+type IVerificationHelper interface {
+	GenerateVerificationCode(ctx context.Context, userID uuid.UUID, channel string, target string) (string, error)
+	VerifyVerificationCode(ctx context.Context, userID uuid.UUID, channel string, target string, code string) error
+}
+
+type ILoginAttemptHelper interface {
+	CheckLoginLocked(ctx context.Context, username string, ip string) error
+	RecordLoginFailure(ctx context.Context, username string, ip string) error
+	ResetLoginFailures(ctx context.Context, username string) error
+}
+
+type IPasswordHelper interface {
+	CheckPasswordReuse(ctx context.Context, user *entity.User, password string) error
+	RecordPassword(ctx context.Context, tx *gorm.DB, user *entity.User) error
+}
+
+type IApiKeyHelper interface {
+	GenerateApiKey() (string, error)
+	Authenticate(ctx context.Context, key string) (*entity.ServicePrincipal, error)
+}
+
+type IOIDCHelper interface {
+	GetAuthorizationURL(ctx context.Context, provider string) (string, error)
+	ExchangeCode(ctx context.Context, state string, code string) (*model.OidcUserInfo, error)
+}
+
+type IJobHelper interface {
+	Enqueue(ctx context.Context, name string, task worker.Task) error
+}
+
+type IAvatarHelper interface {
+	Upload(ctx context.Context, userID uuid.UUID, content []byte) (key string, url string, err error)
+	Remove(ctx context.Context, key string) error
+}
+
+type IDataExportHelper interface {
+	WriteExport(ctx context.Context, job *entity.DataJob, data *model.UserDataExport) error
+	RemoveExport(ctx context.Context, path string) error
 }
@@ -0,0 +1,30 @@
+package helper
+
+import (
+	"context"
+	"log/slog"
+
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/package/worker"
+)
+
+type jobHelper struct {
+	worker worker.IWorker
+}
+
+func NewJobHelper(jobWorker worker.IWorker) IJobHelper {
+	return &jobHelper{
+		worker: jobWorker,
+	}
+}
+
+// Enqueue runs task in the background, the request context is not passed on so the task outlives the request
+func (h *jobHelper) Enqueue(ctx context.Context, name string, task worker.Task) error {
+	if err := h.worker.Submit(task); err != nil {
+		logger.WithCtx(ctx).Error("jobHelper.Enqueue", slog.String("job", name), slog.String("error", err.Error()))
+		return err
+	}
+
+	logger.WithCtx(ctx).Info("jobHelper.Enqueue", slog.String("job", name))
+	return nil
+}
@@ -0,0 +1,118 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"sondth-test_soa/package/errors"
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/package/redis"
+	"sondth-test_soa/utils"
+)
+
+type loginAttemptHelper struct {
+	redisClient redis.IRedisClient
+}
+
+func NewLoginAttemptHelper(redisClient redis.IRedisClient) ILoginAttemptHelper {
+	return &loginAttemptHelper{
+		redisClient: redisClient,
+	}
+}
+
+func (h *loginAttemptHelper) CheckLoginLocked(ctx context.Context, username string, ip string) error {
+	lockKeys := []string{fmt.Sprintf(utils.REDIS_LOGIN_LOCK_USERNAME_KEY, username)}
+	if ip != "" {
+		lockKeys = append(lockKeys, fmt.Sprintf(utils.REDIS_LOGIN_LOCK_IP_KEY, ip))
+	}
+
+	for _, key := range lockKeys {
+		locked, err := h.redisClient.Exists(ctx, key)
+		if err != nil {
+			return err
+		}
+		if locked {
+			return errors.New(errors.ErrCodeAccountLocked)
+		}
+	}
+
+	return nil
+}
+
+func (h *loginAttemptHelper) RecordLoginFailure(ctx context.Context, username string, ip string) error {
+	if err := h.recordFailure(
+		ctx,
+		fmt.Sprintf(utils.REDIS_LOGIN_FAILED_USERNAME_KEY, username),
+		fmt.Sprintf(utils.REDIS_LOGIN_LOCK_USERNAME_KEY, username),
+		utils.MAX_LOGIN_FAILURES_PER_USERNAME,
+		slog.String("username", username),
+	); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+
+	return h.recordFailure(
+		ctx,
+		fmt.Sprintf(utils.REDIS_LOGIN_FAILED_IP_KEY, ip),
+		fmt.Sprintf(utils.REDIS_LOGIN_LOCK_IP_KEY, ip),
+		utils.MAX_LOGIN_FAILURES_PER_IP,
+		slog.String("ip", ip),
+	)
+}
+
+func (h *loginAttemptHelper) ResetLoginFailures(ctx context.Context, username string) error {
+	if err := h.redisClient.Delete(ctx, fmt.Sprintf(utils.REDIS_LOGIN_FAILED_USERNAME_KEY, username)); err != nil {
+		return err
+	}
+
+	return h.redisClient.Delete(ctx, fmt.Sprintf(utils.REDIS_LOGIN_LOCK_USERNAME_KEY, username))
+}
+
+// -------------------------------------------------------------------------------
+func (h *loginAttemptHelper) recordFailure(
+	ctx context.Context,
+	counterKey string,
+	lockKey string,
+	maxFailures int64,
+	subject slog.Attr,
+) error {
+	failures, err := h.redisClient.Incr(ctx, counterKey)
+	if err != nil {
+		return err
+	}
+	if failures == 1 {
+		if err := h.redisClient.Expire(ctx, counterKey, utils.LOGIN_FAILURE_WINDOW*time.Second); err != nil {
+			return err
+		}
+	}
+	if failures < maxFailures {
+		return nil
+	}
+
+	// Every failure past the limit doubles the lockout, up to the maximum
+	lockout := lockoutDuration(failures - maxFailures)
+	logger.WithCtx(ctx).Warn(
+		"Login: too many failed attempts, locking",
+		subject,
+		slog.Int64("failures", failures),
+		slog.Duration("lockout", lockout),
+	)
+
+	return h.redisClient.Set(ctx, lockKey, failures, lockout)
+}
+
+func lockoutDuration(exceeded int64) time.Duration {
+	lockout := utils.LOGIN_LOCKOUT_BASE * time.Second
+	for i := int64(0); i < exceeded; i++ {
+		lockout *= 2
+		if lockout >= utils.LOGIN_LOCKOUT_MAX*time.Second {
+			return utils.LOGIN_LOCKOUT_MAX * time.Second
+		}
+	}
+
+	return lockout
+}
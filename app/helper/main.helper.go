@@ -3,23 +3,51 @@ package helper
 import (
 	"sondth-test_soa/app/repository"
 	"sondth-test_soa/config"
+	"sondth-test_soa/package/jwks"
+	"sondth-test_soa/package/notifier"
+	"sondth-test_soa/package/redis"
+	"sondth-test_soa/package/storage"
+	"sondth-test_soa/package/worker"
 )
 
 type HelperCollections struct {
-	ProductHelper  IProductHelper
-	CategoryHelper ICategoryHelper
-	OAuthHelper    IOAuthHelper
-	UserHelper     IUserHelper
+	ProductHelper      IProductHelper
+	CategoryHelper     ICategoryHelper
+	OAuthHelper        IOAuthHelper
+	UserHelper         IUserHelper
+	NotificationHelper INotificationHelper
+	LoginAttemptHelper ILoginAttemptHelper
+	VerificationHelper IVerificationHelper
+	PasswordHelper     IPasswordHelper
+	ApiKeyHelper       IApiKeyHelper
+	OIDCHelper         IOIDCHelper
+	JobHelper          IJobHelper
+	DataExportHelper   IDataExportHelper
+	AvatarHelper       IAvatarHelper
 }
 
 func RegisterHelpers(
 	postgresRepo repository.RepositoryCollections,
+	redisClient redis.IRedisClient,
+	notifierClient notifier.INotifier,
+	keySet *jwks.KeySet,
+	jobWorker worker.IWorker,
+	fileStorage storage.IStorage,
 	config config.Configuration,
 ) HelperCollections {
 	return HelperCollections{
-		ProductHelper:  NewProductHelper(postgresRepo),
-		CategoryHelper: NewCategoryHelper(postgresRepo),
-		OAuthHelper:    NewOAuthHelper(config),
-		UserHelper:     NewUserHelper(postgresRepo),
+		ProductHelper:      NewProductHelper(postgresRepo),
+		CategoryHelper:     NewCategoryHelper(postgresRepo),
+		OAuthHelper:        NewOAuthHelper(config, redisClient, keySet),
+		UserHelper:         NewUserHelper(postgresRepo),
+		NotificationHelper: NewNotificationHelper(notifierClient, config),
+		LoginAttemptHelper: NewLoginAttemptHelper(redisClient),
+		VerificationHelper: NewVerificationHelper(redisClient),
+		PasswordHelper:     NewPasswordHelper(postgresRepo, config),
+		ApiKeyHelper:       NewApiKeyHelper(postgresRepo),
+		OIDCHelper:         NewOIDCHelper(config, redisClient),
+		JobHelper:          NewJobHelper(jobWorker),
+		DataExportHelper:   NewDataExportHelper(config),
+		AvatarHelper:       NewAvatarHelper(fileStorage, config),
 	}
 }
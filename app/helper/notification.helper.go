@@ -0,0 +1,75 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/config"
+	"sondth-test_soa/package/notifier"
+	"sondth-test_soa/utils"
+)
+
+type notificationHelper struct {
+	notifier notifier.INotifier
+	config   config.Configuration
+}
+
+func NewNotificationHelper(notifier notifier.INotifier, config config.Configuration) INotificationHelper {
+	return &notificationHelper{
+		notifier: notifier,
+		config:   config,
+	}
+}
+
+func (h *notificationHelper) SendPasswordResetToken(ctx context.Context, user entity.User, token string) error {
+	return h.notifier.Send(ctx, notifier.Message{
+		To:      user.Username,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Use this token to reset your password: %s. It expires in %s and can only be used once.",
+			token,
+			utils.PASSWORD_RESET_TOKEN_IAT*time.Second,
+		),
+	})
+}
+
+func (h *notificationHelper) SendVerificationCode(ctx context.Context, channel string, target string, code string) error {
+	notifierChannel := notifier.CHANNEL_EMAIL
+	if channel == entity.VERIFICATION_CHANNEL_PHONE {
+		notifierChannel = notifier.CHANNEL_SMS
+	}
+
+	return h.notifier.Send(ctx, notifier.Message{
+		Channel: notifierChannel,
+		To:      target,
+		Subject: "Your verification code",
+		Body: fmt.Sprintf(
+			"Your verification code is %s. It expires in %s.",
+			code,
+			utils.VERIFICATION_CODE_IAT*time.Second,
+		),
+	})
+}
+
+// SendInvitation emails the activation link of an account created by an admin
+func (h *notificationHelper) SendInvitation(ctx context.Context, user entity.User, token string) error {
+	link := token
+	if h.config.Invitation.ActivationURL != "" {
+		link = fmt.Sprintf("%s?token=%s", h.config.Invitation.ActivationURL, url.QueryEscape(token))
+	}
+
+	return h.notifier.Send(ctx, notifier.Message{
+		Channel: notifier.CHANNEL_EMAIL,
+		To:      *user.Email,
+		Subject: "You have been invited",
+		Body: fmt.Sprintf(
+			"Hi %s, an account has been created for you. Activate it and choose a password with: %s. It expires in %s and can only be used once.",
+			user.Fullname,
+			link,
+			time.Duration(h.config.Invitation.TTL)*time.Second,
+		),
+	})
+}
@@ -1,34 +1,61 @@
 package helper
 
 import (
+	"context"
 	"encoding/json"
-	"sondth-test_soa/app/entity"
-	"sondth-test_soa/app/model"
-	"sondth-test_soa/config"
+	"fmt"
+	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/config"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/package/jwks"
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/package/redis"
+	"sondth-test_soa/package/totp"
+	"sondth-test_soa/utils"
 )
 
 type oAuthHelper struct {
-	config config.Configuration
+	config      config.Configuration
+	redisClient redis.IRedisClient
+	keySet      *jwks.KeySet
 }
 
 func NewOAuthHelper(
 	config config.Configuration,
+	redisClient redis.IRedisClient,
+	keySet *jwks.KeySet,
 ) IOAuthHelper {
-	return &oAuthHelper{config: config}
+	return &oAuthHelper{config: config, redisClient: redisClient, keySet: keySet}
 }
 
-func (h *oAuthHelper) GenerateAccessToken(user entity.User) (string, error) {
+func (h *oAuthHelper) GenerateAccessToken(ctx context.Context, user entity.User, session model.TokenSession) (string, error) {
+	tokenVersion, err := h.getTokenVersion(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
 	payload := &model.UserJWTPayload{
-		UserID: user.ID,
+		UserID:       user.ID,
+		FamilyID:     session.FamilyID,
+		TokenVersion: tokenVersion,
+		MfaVerified:  session.MfaVerified,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(utils.USER_ACCESS_TOKEN_IAT * time.Second)),
 			Issuer:    h.config.Jwt.Issuer,
 		},
 	}
-	accessToken, err := h.GenerateToken(payload, h.config.Jwt.UserAccessTokenKey)
+	// Access tokens are signed with the key set so other services can verify them
+	accessToken, err := h.keySet.Sign(payload)
 	if err != nil {
 		return "", err
 	}
@@ -36,11 +63,21 @@ func (h *oAuthHelper) GenerateAccessToken(user entity.User) (string, error) {
 	return accessToken, nil
 }
 
-func (h *oAuthHelper) GenerateRefreshToken(user entity.User) (string, error) {
+func (h *oAuthHelper) GenerateRefreshToken(ctx context.Context, user entity.User, session model.TokenSession) (string, error) {
+	tokenVersion, err := h.getTokenVersion(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
 	payload := &model.UserJWTPayload{
-		UserID: user.ID,
+		UserID:       user.ID,
+		FamilyID:     session.FamilyID,
+		TokenVersion: tokenVersion,
+		MfaVerified:  session.MfaVerified,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().AddDate(0, 0, 30)),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(utils.USER_REFRESH_TOKEN_IAT * time.Second)),
 			Issuer:    h.config.Jwt.Issuer,
 		},
 	}
@@ -49,11 +86,304 @@ func (h *oAuthHelper) GenerateRefreshToken(user entity.User) (string, error) {
 		return "", err
 	}
 
+	// Only the latest token of a family is allowed to be exchanged
+	if err := h.redisClient.Set(
+		ctx,
+		fmt.Sprintf(utils.REDIS_REFRESH_TOKEN_FAMILY_KEY, session.FamilyID),
+		payload.ID,
+		utils.USER_REFRESH_TOKEN_IAT*time.Second,
+	); err != nil {
+		return "", err
+	}
+
 	return refreshToken, nil
 }
 
+func (h *oAuthHelper) GenerateImpersonationToken(
+	ctx context.Context,
+	impersonatorID uuid.UUID,
+	user entity.User,
+	session model.TokenSession,
+) (string, error) {
+	tokenVersion, err := h.getTokenVersion(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	// The token belongs to the session of the admin, revoking it ends the impersonation too.
+	// There is no refresh token, the admin has to ask for a new token once it expires.
+	payload := &model.UserJWTPayload{
+		UserID:         user.ID,
+		FamilyID:       session.FamilyID,
+		TokenVersion:   tokenVersion,
+		MfaVerified:    session.MfaVerified,
+		ImpersonatorID: &impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(utils.IMPERSONATION_TOKEN_IAT * time.Second)),
+			Issuer:    h.config.Jwt.Issuer,
+		},
+	}
+
+	return h.keySet.Sign(payload)
+}
+
+func (h *oAuthHelper) RotateRefreshToken(ctx context.Context, tokenString string) (*model.UserJWTPayload, error) {
+	payload, err := h.VerifyRefreshToken(tokenString)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInvalidToken)
+	}
+	if payload.FamilyID == "" || payload.ID == "" {
+		return nil, errors.New(errors.ErrCodeInvalidToken)
+	}
+
+	// Tokens issued before a logout-all or password change are no longer valid
+	tokenVersion, err := h.getTokenVersion(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if payload.TokenVersion != tokenVersion {
+		return nil, errors.New(errors.ErrCodeTokenRevoked)
+	}
+
+	familyKey := fmt.Sprintf(utils.REDIS_REFRESH_TOKEN_FAMILY_KEY, payload.FamilyID)
+	currentTokenID, err := h.redisClient.Get(ctx, familyKey)
+	if err != nil {
+		if err == redis.Nil {
+			// Family was revoked or has expired
+			return nil, errors.New(errors.ErrCodeInvalidToken)
+		}
+		return nil, err
+	}
+	if currentTokenID != payload.ID {
+		return nil, h.handleRefreshTokenReuse(ctx, payload)
+	}
+
+	// Mark the token as used so that concurrent exchanges of the same token are caught
+	ok, err := h.redisClient.SetNX(
+		ctx,
+		fmt.Sprintf(utils.REDIS_REFRESH_TOKEN_USED_KEY, payload.ID),
+		payload.FamilyID,
+		utils.USER_REFRESH_TOKEN_IAT*time.Second,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, h.handleRefreshTokenReuse(ctx, payload)
+	}
+
+	return payload, nil
+}
+
+func (h *oAuthHelper) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	return h.redisClient.Delete(ctx, fmt.Sprintf(utils.REDIS_REFRESH_TOKEN_FAMILY_KEY, familyID))
+}
+
+func (h *oAuthHelper) RevokeSession(ctx context.Context, sessionID string) error {
+	if err := h.RevokeRefreshTokenFamily(ctx, sessionID); err != nil {
+		return err
+	}
+
+	// Access tokens of the session stay valid until they expire, deny them until then
+	return h.redisClient.Set(
+		ctx,
+		fmt.Sprintf(utils.REDIS_REVOKED_SESSION_KEY, sessionID),
+		sessionID,
+		utils.USER_ACCESS_TOKEN_IAT*time.Second,
+	)
+}
+
+func (h *oAuthHelper) RevokeAccessToken(ctx context.Context, payload *model.UserJWTPayload) error {
+	if payload.ID == "" || payload.ExpiresAt == nil {
+		return nil
+	}
+
+	// Keep the token in the denylist until it would have expired anyway
+	ttl := time.Until(payload.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return h.redisClient.Set(ctx, fmt.Sprintf(utils.REDIS_ACCESS_TOKEN_DENYLIST_KEY, payload.ID), payload.UserID.String(), ttl)
+}
+
+func (h *oAuthHelper) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := h.redisClient.Incr(ctx, fmt.Sprintf(utils.REDIS_USER_TOKEN_VERSION_KEY, userID.String()))
+	return err
+}
+
+func (h *oAuthHelper) IsAccessTokenRevoked(ctx context.Context, payload *model.UserJWTPayload) (bool, error) {
+	if payload.ID != "" {
+		denied, err := h.redisClient.Exists(ctx, fmt.Sprintf(utils.REDIS_ACCESS_TOKEN_DENYLIST_KEY, payload.ID))
+		if err != nil {
+			return false, err
+		}
+		if denied {
+			return true, nil
+		}
+	}
+
+	if payload.FamilyID != "" {
+		revoked, err := h.redisClient.Exists(ctx, fmt.Sprintf(utils.REDIS_REVOKED_SESSION_KEY, payload.FamilyID))
+		if err != nil {
+			return false, err
+		}
+		if revoked {
+			return true, nil
+		}
+	}
+
+	tokenVersion, err := h.getTokenVersion(ctx, payload.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return payload.TokenVersion != tokenVersion, nil
+}
+
+func (h *oAuthHelper) GeneratePasswordResetToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	// Only the hash is stored so a leaked Redis dump can't be used to reset passwords
+	if err := h.redisClient.Set(
+		ctx,
+		fmt.Sprintf(utils.REDIS_PASSWORD_RESET_TOKEN_KEY, utils.HashToken(token)),
+		userID.String(),
+		utils.PASSWORD_RESET_TOKEN_IAT*time.Second,
+	); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (h *oAuthHelper) ConsumePasswordResetToken(ctx context.Context, token string) (uuid.UUID, error) {
+	value, err := h.redisClient.GetDel(ctx, fmt.Sprintf(utils.REDIS_PASSWORD_RESET_TOKEN_KEY, utils.HashToken(token)))
+	if err != nil {
+		if err == redis.Nil {
+			return uuid.Nil, errors.New(errors.ErrCodeInvalidToken)
+		}
+		return uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, errors.New(errors.ErrCodeInvalidToken)
+	}
+
+	return userID, nil
+}
+
+func (h *oAuthHelper) GenerateInvitationToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.redisClient.Set(
+		ctx,
+		fmt.Sprintf(utils.REDIS_INVITATION_TOKEN_KEY, utils.HashToken(token)),
+		userID.String(),
+		time.Duration(h.config.Invitation.TTL)*time.Second,
+	); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (h *oAuthHelper) ConsumeInvitationToken(ctx context.Context, token string) (uuid.UUID, error) {
+	value, err := h.redisClient.GetDel(ctx, fmt.Sprintf(utils.REDIS_INVITATION_TOKEN_KEY, utils.HashToken(token)))
+	if err != nil {
+		if err == redis.Nil {
+			return uuid.Nil, errors.New(errors.ErrCodeInvalidToken)
+		}
+		return uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, errors.New(errors.ErrCodeInvalidToken)
+	}
+
+	return userID, nil
+}
+
+func (h *oAuthHelper) GenerateMfaChallengeToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.redisClient.Set(
+		ctx,
+		fmt.Sprintf(utils.REDIS_MFA_CHALLENGE_TOKEN_KEY, utils.HashToken(token)),
+		userID.String(),
+		utils.MFA_CHALLENGE_TOKEN_IAT*time.Second,
+	); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (h *oAuthHelper) VerifyMfaChallengeToken(ctx context.Context, token string) (uuid.UUID, error) {
+	hashedToken := utils.HashToken(token)
+	challengeKey := fmt.Sprintf(utils.REDIS_MFA_CHALLENGE_TOKEN_KEY, hashedToken)
+
+	// Each challenge only allows a few code attempts to prevent brute forcing the code
+	attemptKey := fmt.Sprintf(utils.REDIS_MFA_CHALLENGE_ATTEMPT_KEY, hashedToken)
+	attempts, err := h.redisClient.Incr(ctx, attemptKey)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if attempts == 1 {
+		if err := h.redisClient.Expire(ctx, attemptKey, utils.MFA_CHALLENGE_TOKEN_IAT*time.Second); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	if attempts > utils.MAX_MFA_CHALLENGE_ATTEMPTS {
+		if err := h.redisClient.Delete(ctx, challengeKey); err != nil {
+			return uuid.Nil, err
+		}
+		return uuid.Nil, errors.New(errors.ErrCodeInvalidToken)
+	}
+
+	value, err := h.redisClient.Get(ctx, challengeKey)
+	if err != nil {
+		if err == redis.Nil {
+			return uuid.Nil, errors.New(errors.ErrCodeInvalidToken)
+		}
+		return uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, errors.New(errors.ErrCodeInvalidToken)
+	}
+
+	return userID, nil
+}
+
+func (h *oAuthHelper) RevokeMfaChallengeToken(ctx context.Context, token string) error {
+	return h.redisClient.Delete(ctx, fmt.Sprintf(utils.REDIS_MFA_CHALLENGE_TOKEN_KEY, utils.HashToken(token)))
+}
+
+func (h *oAuthHelper) GetMfaProvisioningURI(account string, secret string) string {
+	return totp.ProvisioningURI(h.config.MFA.Issuer, account, secret)
+}
+
+func (h *oAuthHelper) GetJSONWebKeys() []jwks.JSONWebKey {
+	return h.keySet.Keys()
+}
+
 func (h *oAuthHelper) VerifyAccessToken(tokenString string) (*model.UserJWTPayload, error) {
-	token, err := h.VerifyToken(tokenString, h.config.Jwt.UserAccessTokenKey)
+	token, err := h.keySet.Parse(tokenString)
 	if err != nil {
 		return nil, err
 	}
@@ -117,3 +447,30 @@ func (h *oAuthHelper) VerifyToken(tokenString string, key string) (*jwt.Token, e
 
 	return token, nil
 }
+
+// -------------------------------------------------------------------------------
+func (h *oAuthHelper) getTokenVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	value, err := h.redisClient.Get(ctx, fmt.Sprintf(utils.REDIS_USER_TOKEN_VERSION_KEY, userID.String()))
+	if err != nil {
+		if err == redis.Nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func (h *oAuthHelper) handleRefreshTokenReuse(ctx context.Context, payload *model.UserJWTPayload) error {
+	logger.WithCtx(ctx).Warn(
+		"RotateRefreshToken: refresh token reuse detected, revoking family",
+		slog.String("user_id", payload.UserID.String()),
+		slog.String("family_id", payload.FamilyID),
+		slog.String("token_id", payload.ID),
+	)
+	if err := h.RevokeRefreshTokenFamily(ctx, payload.FamilyID); err != nil {
+		return err
+	}
+
+	return errors.New(errors.ErrCodeTokenReused)
+}
@@ -0,0 +1,120 @@
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/config"
+	"sondth-test_soa/package/errors"
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/package/oidc"
+	"sondth-test_soa/package/redis"
+	"sondth-test_soa/utils"
+)
+
+// oidcState is kept in Redis between the redirect to the provider and the callback
+type oidcState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+}
+
+type oidcHelper struct {
+	redisClient redis.IRedisClient
+	providers   map[string]*oidc.Provider
+}
+
+func NewOIDCHelper(config config.Configuration, redisClient redis.IRedisClient) IOIDCHelper {
+	providers := make(map[string]*oidc.Provider, len(config.OIDC.Providers))
+	for _, providerConf := range config.OIDC.Providers {
+		providers[providerConf.Name] = oidc.NewProvider(providerConf)
+	}
+
+	return &oidcHelper{
+		redisClient: redisClient,
+		providers:   providers,
+	}
+}
+
+// GetAuthorizationURL starts an authorization code + PKCE flow, the state is valid for OIDC_STATE_IAT
+func (h *oidcHelper) GetAuthorizationURL(ctx context.Context, providerName string) (string, error) {
+	provider, ok := h.providers[providerName]
+	if !ok {
+		return "", errors.New(errors.ErrCodeOidcProviderNotFound)
+	}
+
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	value, err := json.Marshal(oidcState{
+		Provider:     providerName,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := h.redisClient.Set(
+		ctx,
+		fmt.Sprintf(utils.REDIS_OIDC_STATE_KEY, utils.HashToken(state)),
+		string(value),
+		utils.OIDC_STATE_IAT*time.Second,
+	); err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallengeS256(codeVerifier))
+}
+
+// ExchangeCode consumes the state and returns the verified account of the provider
+func (h *oidcHelper) ExchangeCode(ctx context.Context, state string, code string) (*model.OidcUserInfo, error) {
+	value, err := h.redisClient.GetDel(ctx, fmt.Sprintf(utils.REDIS_OIDC_STATE_KEY, utils.HashToken(state)))
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.New(errors.ErrCodeOidcInvalidState)
+		}
+		return nil, err
+	}
+
+	var storedState oidcState
+	if err := json.Unmarshal([]byte(value), &storedState); err != nil {
+		return nil, err
+	}
+	provider, ok := h.providers[storedState.Provider]
+	if !ok {
+		return nil, errors.New(errors.ErrCodeOidcProviderNotFound)
+	}
+
+	token, err := provider.Exchange(ctx, code, storedState.CodeVerifier)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("ExchangeCode", slog.String("provider", provider.Name()), slog.String("error", err.Error()))
+		return nil, errors.New(errors.ErrCodeOidcLoginFailed)
+	}
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, storedState.Nonce)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("VerifyIDToken", slog.String("provider", provider.Name()), slog.String("error", err.Error()))
+		return nil, errors.New(errors.ErrCodeOidcLoginFailed)
+	}
+
+	return &model.OidcUserInfo{
+		Provider:      provider.Name(),
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
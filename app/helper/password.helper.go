@@ -0,0 +1,64 @@
+package helper
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+	"sondth-test_soa/config"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+type passwordHelper struct {
+	config       config.Configuration
+	postgresRepo repository.RepositoryCollections
+}
+
+func NewPasswordHelper(postgresRepo repository.RepositoryCollections, config config.Configuration) IPasswordHelper {
+	return &passwordHelper{
+		config:       config,
+		postgresRepo: postgresRepo,
+	}
+}
+
+// CheckPasswordReuse rejects password when it is the current or one of the last password_policy.history_size passwords
+func (h *passwordHelper) CheckPasswordReuse(ctx context.Context, user *entity.User, password string) error {
+	historySize := h.config.PasswordPolicy.HistorySize
+	if historySize <= 0 {
+		return nil
+	}
+
+	if utils.CheckPasswordHash(password, user.Password) == nil {
+		return errors.New(errors.ErrCodePasswordReused)
+	}
+
+	histories, err := h.postgresRepo.PasswordHistoryRepo.FindManyByFilter(ctx, nil, &repository.FindPasswordHistoryByFilter{
+		UserID: &user.ID,
+		Limit:  &historySize,
+		Filter: repository.Filter{
+			Fields: []string{"password"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, history := range histories {
+		if utils.CheckPasswordHash(password, history.Password) == nil {
+			return errors.New(errors.ErrCodePasswordReused)
+		}
+	}
+
+	return nil
+}
+
+// RecordPassword adds the current password hash of a saved user to its history
+func (h *passwordHelper) RecordPassword(ctx context.Context, tx *gorm.DB, user *entity.User) error {
+	if h.config.PasswordPolicy.HistorySize <= 0 {
+		return nil
+	}
+
+	return h.postgresRepo.PasswordHistoryRepo.Create(ctx, tx, entity.NewPasswordHistory(user.ID, user.Password))
+}
@@ -15,7 +15,9 @@ type productHelper struct {
 }
 
 func NewProductHelper(postgresRepo repository.RepositoryCollections) IProductHelper {
-	return &productHelper{}
+	return &productHelper{
+		postgresRepo: postgresRepo,
+	}
 }
 
 func (s *productHelper) ValidateProductID(ctx context.Context, productID uuid.UUID) (*entity.Product, error) {
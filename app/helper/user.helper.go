@@ -2,12 +2,10 @@ package helper
 
 import (
 	"context"
+	"slices"
 
 	"sondth-test_soa/app/entity"
 	"sondth-test_soa/app/repository"
-	"sondth-test_soa/utils"
-
-	"github.com/google/uuid"
 )
 
 type userHelper struct {
@@ -24,10 +22,60 @@ func (s *userHelper) IsValidRole(role string) bool {
 	return role == entity.ROLE_ADMIN || role == entity.ROLE_USER
 }
 
-func (s *userHelper) IsValidPermission(ctx context.Context, userID uuid.UUID) bool {
-	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
-	if !ok {
-		return false
+func (s *userHelper) GetPermissions(ctx context.Context, user *entity.User) ([]string, error) {
+	permissions, err := s.postgresRepo.PermissionRepo.FindManyByFilter(ctx, nil, &repository.FindPermissionByFilter{
+		Filter: repository.Filter{
+			Fields: []string{"permissions.code"},
+		},
+		UserID:   &user.ID,
+		UserRole: &user.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		codes = append(codes, permission.Code)
+	}
+
+	return codes, nil
+}
+
+func (s *userHelper) HasPermission(ctx context.Context, user *entity.User, permission string) (bool, error) {
+	permissions, err := s.postgresRepo.PermissionRepo.FindManyByFilter(ctx, nil, &repository.FindPermissionByFilter{
+		Filter: repository.Filter{
+			Fields: []string{"permissions.id"},
+		},
+		Codes:    []string{permission},
+		UserID:   &user.ID,
+		UserRole: &user.Role,
+	})
+	if err != nil {
+		return false, err
 	}
-	return user.ID == userID && user.IsAdmin()
+
+	return len(permissions) > 0, nil
+}
+
+// GetStaffPermissions returns the permissions of the user that plain customers don't have
+func (s *userHelper) GetStaffPermissions(ctx context.Context, user *entity.User) ([]string, error) {
+	permissions, err := s.GetPermissions(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	// A user with only the default role and no assigned roles has exactly the customer permissions
+	customerPermissions, err := s.GetPermissions(ctx, &entity.User{Role: entity.ROLE_USER})
+	if err != nil {
+		return nil, err
+	}
+
+	staffPermissions := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !slices.Contains(customerPermissions, permission) {
+			staffPermissions = append(staffPermissions, permission)
+		}
+	}
+
+	return staffPermissions, nil
 }
@@ -0,0 +1,104 @@
+package helper
+
+import (
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/package/redis"
+	"sondth-test_soa/utils"
+)
+
+type verificationHelper struct {
+	redisClient redis.IRedisClient
+}
+
+func NewVerificationHelper(redisClient redis.IRedisClient) IVerificationHelper {
+	return &verificationHelper{
+		redisClient: redisClient,
+	}
+}
+
+func (h *verificationHelper) GenerateVerificationCode(ctx context.Context, userID uuid.UUID, channel string, target string) (string, error) {
+	// Only one code can be requested per channel every VERIFICATION_RESEND_IAT
+	ok, err := h.redisClient.SetNX(
+		ctx,
+		fmt.Sprintf(utils.REDIS_VERIFICATION_RESEND_KEY, userID.String(), channel),
+		target,
+		utils.VERIFICATION_RESEND_IAT*time.Second,
+	)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", errors.New(errors.ErrCodeRateLimitExceeded)
+	}
+
+	code, err := utils.GenerateNumericCode(utils.VERIFICATION_CODE_LENGTH)
+	if err != nil {
+		return "", err
+	}
+
+	// The code is bound to the target so it can't verify an address changed in between
+	if err := h.redisClient.Set(
+		ctx,
+		fmt.Sprintf(utils.REDIS_VERIFICATION_CODE_KEY, userID.String(), channel),
+		hashVerificationCode(target, code),
+		utils.VERIFICATION_CODE_IAT*time.Second,
+	); err != nil {
+		return "", err
+	}
+	if err := h.redisClient.Delete(ctx, fmt.Sprintf(utils.REDIS_VERIFICATION_ATTEMPT_KEY, userID.String(), channel)); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func (h *verificationHelper) VerifyVerificationCode(ctx context.Context, userID uuid.UUID, channel string, target string, code string) error {
+	codeKey := fmt.Sprintf(utils.REDIS_VERIFICATION_CODE_KEY, userID.String(), channel)
+	attemptKey := fmt.Sprintf(utils.REDIS_VERIFICATION_ATTEMPT_KEY, userID.String(), channel)
+
+	// A code only allows a few attempts to prevent brute forcing it
+	attempts, err := h.redisClient.Incr(ctx, attemptKey)
+	if err != nil {
+		return err
+	}
+	if attempts == 1 {
+		if err := h.redisClient.Expire(ctx, attemptKey, utils.VERIFICATION_CODE_IAT*time.Second); err != nil {
+			return err
+		}
+	}
+	if attempts > utils.MAX_VERIFICATION_CODE_ATTEMPTS {
+		if err := h.redisClient.Delete(ctx, codeKey); err != nil {
+			return err
+		}
+		return errors.New(errors.ErrCodeInvalidVerificationCode)
+	}
+
+	hashedCode, err := h.redisClient.Get(ctx, codeKey)
+	if err != nil {
+		if err == redis.Nil {
+			return errors.New(errors.ErrCodeInvalidVerificationCode)
+		}
+		return err
+	}
+	if !hmac.Equal([]byte(hashedCode), []byte(hashVerificationCode(target, code))) {
+		return errors.New(errors.ErrCodeInvalidVerificationCode)
+	}
+
+	if err := h.redisClient.Delete(ctx, codeKey); err != nil {
+		return err
+	}
+
+	return h.redisClient.Delete(ctx, attemptKey)
+}
+
+// -------------------------------------------------------------------------------
+func hashVerificationCode(target string, code string) string {
+	return utils.HashToken(target + ":" + code)
+}
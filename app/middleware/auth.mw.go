@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"context"
 	_errors "errors"
 	"net/http"
 	"slices"
 	"strings"
 
+	"sondth-test_soa/app/entity"
 	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
 	"sondth-test_soa/app/repository"
 	"sondth-test_soa/package/errors"
 	logger "sondth-test_soa/package/log"
@@ -14,12 +17,21 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 var (
 	WHITE_LIST_API = []string{
 		"/api/v1/user/login",
+		"/api/v1/user/login/mfa",
+		"/api/v1/user/oidc/authorize",
+		"/api/v1/user/oidc/callback",
 		"/api/v1/user/register",
+		"/api/v1/user/refresh",
+		"/api/v1/user/forget-password",
+		"/api/v1/user/reset-password",
+		"/api/v1/user/activate",
+		"/.well-known/jwks.json",
 	}
 )
 
@@ -39,15 +51,36 @@ func (m *authMiddleware) Handler() gin.HandlerFunc {
 			return
 		}
 
+		// Internal services authenticate with an API key and act as a service principal
+		if apiKey := c.GetHeader(utils.API_KEY_HEADER); apiKey != "" {
+			principal, err := m.helpers.ApiKeyHelper.Authenticate(c, apiKey)
+			if err != nil {
+				if _, ok := err.(*errors.CustomError); ok {
+					c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, err))
+					c.Abort()
+					return
+				}
+
+				logger.WithCtx(c).Error("Authenticate", err)
+				c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeInternalServerError)))
+				c.Abort()
+				return
+			}
+
+			c.Set(string(utils.PRINCIPAL_CONTEXT_KEY), principal)
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(errors.New(errors.ErrCodeUnauthorized)))
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
 			c.Abort()
 			return
 		}
 		authArr := strings.Split(authHeader, " ")
 		if len(authArr) != 2 || authArr[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(errors.New(errors.ErrCodeUnauthorized)))
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
 			c.Abort()
 			return
 		}
@@ -55,13 +88,26 @@ func (m *authMiddleware) Handler() gin.HandlerFunc {
 		payload, err := m.helpers.OAuthHelper.VerifyAccessToken(authArr[1])
 		if err != nil {
 			if _errors.Is(err, jwt.ErrTokenExpired) {
-				c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(errors.New(errors.ErrCodeTokenExpired)))
+				c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeTokenExpired)))
 				c.Abort()
 				return
 			}
 
 			logger.WithCtx(c).Error("VerifyAccessToken", err)
-			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(errors.New(errors.ErrCodeUnauthorized)))
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
+			c.Abort()
+			return
+		}
+
+		revoked, err := m.helpers.OAuthHelper.IsAccessTokenRevoked(c, payload)
+		if err != nil {
+			logger.WithCtx(c).Error("IsAccessTokenRevoked", err)
+			c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeInternalServerError)))
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeTokenRevoked)))
 			c.Abort()
 			return
 		}
@@ -70,12 +116,64 @@ func (m *authMiddleware) Handler() gin.HandlerFunc {
 			ID: &payload.UserID,
 		})
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(errors.New(errors.ErrCodeUnauthorized)))
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
+			c.Abort()
+			return
+		}
+		if user.IsDeleted() {
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUserDeleted)))
+			c.Abort()
+			return
+		}
+		if user.IsSuspended() {
+			c.JSON(http.StatusForbidden, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUserSuspended)))
 			c.Abort()
 			return
 		}
 
 		c.Set(string(utils.USER_CONTEXT_KEY), user)
+		c.Set(string(utils.TOKEN_CONTEXT_KEY), payload)
+		m.applyPreferredLanguage(c, user)
 		c.Next()
+
+		// Every request made while impersonating is kept in the audit trail
+		if payload.IsImpersonated() {
+			m.recordImpersonatedRequest(c, payload)
+		}
+	}
+}
+
+// -------------------------------------------------------------------------------
+// applyPreferredLanguage answers in the language of the preferences of the user, unless the request asks for another one
+func (m *authMiddleware) applyPreferredLanguage(c *gin.Context, user *entity.User) {
+	if requestedLanguage(c) != "" {
+		return
+	}
+
+	preference, err := m.postgresRepo.PreferenceRepo.FindOneByFilter(c, nil, &repository.FindPreferenceByFilter{
+		UserID: &user.ID,
+	})
+	if err != nil {
+		if !_errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.WithCtx(c).Error("applyPreferredLanguage", err)
+		}
+		return
+	}
+	if errors.IsSupportedLanguage(preference.Language) {
+		c.Set(string(utils.LANGUAGE_CONTEXT_KEY), preference.Language)
+	}
+}
+
+func (m *authMiddleware) recordImpersonatedRequest(c *gin.Context, payload *model.UserJWTPayload) {
+	log := entity.NewImpersonationLog(*payload.ImpersonatorID, payload.UserID, entity.IMPERSONATION_ACTION_REQUEST)
+	log.Method = c.Request.Method
+	log.Path = c.Request.URL.Path
+	log.StatusCode = c.Writer.Status()
+	log.ClientIP = c.ClientIP()
+	log.UserAgent = c.Request.UserAgent()
+
+	// The request is done, don't let a cancelled request context drop the record
+	if err := m.postgresRepo.ImpersonationLogRepo.Create(context.WithoutCancel(c), nil, log); err != nil {
+		logger.WithCtx(c).Error("recordImpersonatedRequest", err)
 	}
 }
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+type impersonationMiddleware struct{}
+
+func NewImpersonationMiddleware() ICustomMiddleware {
+	return &impersonationMiddleware{}
+}
+
+// Handler rejects sensitive actions, e.g: changing the password, when the token is an impersonation token
+func (m *impersonationMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(string(utils.TOKEN_CONTEXT_KEY))
+		if !ok {
+			c.Next()
+			return
+		}
+		payload, ok := value.(*model.UserJWTPayload)
+		if ok && payload.IsImpersonated() {
+			c.JSON(http.StatusForbidden, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeImpersonationForbidden)))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -7,3 +7,11 @@ import (
 type ICustomMiddleware interface {
 	Handler() gin.HandlerFunc
 }
+
+type IPermissionMiddleware interface {
+	RequirePermission(permission string) gin.HandlerFunc
+}
+
+type IVerificationMiddleware interface {
+	RequireVerified(channel string) gin.HandlerFunc
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+type languageMiddleware struct{}
+
+func NewLanguageMiddleware() ICustomMiddleware {
+	return &languageMiddleware{}
+}
+
+// Handler picks the language of the request from the Accept-Language header.
+// Without it, the default language is used until the user is known, see authMiddleware.
+func (m *languageMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := requestedLanguage(c)
+		if lang == "" {
+			lang = errors.GetDefaultLanguage()
+		}
+
+		c.Set(string(utils.LANGUAGE_CONTEXT_KEY), lang)
+		c.Next()
+	}
+}
+
+// requestedLanguage is the supported language the client asked for, empty when it didn't ask for one
+func requestedLanguage(c *gin.Context) string {
+	return errors.MatchLanguage(c.GetHeader("Accept-Language"))
+}
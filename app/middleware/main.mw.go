@@ -3,23 +3,33 @@ package middleware
 import (
 	"sondth-test_soa/app/helper"
 	"sondth-test_soa/app/repository"
+	"sondth-test_soa/config"
 	"sondth-test_soa/package/redis"
 )
 
 type MiddlewareCollections struct {
-	RateLimitMw ICustomMiddleware
-	AuthMw      ICustomMiddleware
-	AdminMw     ICustomMiddleware
+	LanguageMw      ICustomMiddleware
+	RateLimitMw     ICustomMiddleware
+	AuthMw          ICustomMiddleware
+	PermissionMw    IPermissionMiddleware
+	MfaMw           ICustomMiddleware
+	VerifiedMw      IVerificationMiddleware
+	ImpersonationMw ICustomMiddleware
 }
 
 func RegisterMiddleware(
 	redisClient redis.IRedisClient,
 	postgresRepo repository.RepositoryCollections,
 	helpers helper.HelperCollections,
+	conf config.Configuration,
 ) MiddlewareCollections {
 	return MiddlewareCollections{
-		RateLimitMw: NewRateLimitMiddleware(redisClient),
-		AuthMw:      NewAuthMiddleware(postgresRepo, helpers),
-		AdminMw:     NewAdminMiddleware(),
+		LanguageMw:      NewLanguageMiddleware(),
+		RateLimitMw:     NewRateLimitMiddleware(redisClient),
+		AuthMw:          NewAuthMiddleware(postgresRepo, helpers),
+		PermissionMw:    NewPermissionMiddleware(helpers),
+		MfaMw:           NewMfaMiddleware(conf),
+		VerifiedMw:      NewVerificationMiddleware(),
+		ImpersonationMw: NewImpersonationMiddleware(),
 	}
 }
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/config"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+type mfaMiddleware struct {
+	config config.Configuration
+}
+
+func NewMfaMiddleware(config config.Configuration) ICustomMiddleware {
+	return &mfaMiddleware{config: config}
+}
+
+// Handler only lets through tokens issued after a two-factor login when enforcement is on
+func (m *mfaMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.config.MFA.EnforceOnAdminRoutes {
+			c.Next()
+			return
+		}
+
+		// API keys aren't interactive logins, their permissions are checked instead
+		if _, ok := c.Get(string(utils.PRINCIPAL_CONTEXT_KEY)); ok {
+			c.Next()
+			return
+		}
+
+		value, ok := c.Get(string(utils.TOKEN_CONTEXT_KEY))
+		if !ok {
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
+			c.Abort()
+			return
+		}
+		payload, ok := value.(*model.UserJWTPayload)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
+			c.Abort()
+			return
+		}
+		if !payload.MfaVerified {
+			c.JSON(http.StatusForbidden, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeMfaRequired)))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/package/errors"
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/utils"
+)
+
+type permissionMiddleware struct {
+	helpers helper.HelperCollections
+}
+
+func NewPermissionMiddleware(helpers helper.HelperCollections) IPermissionMiddleware {
+	return &permissionMiddleware{helpers: helpers}
+}
+
+func (m *permissionMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Service principals only have the permissions of their API key
+		if principal, ok := c.Get(string(utils.PRINCIPAL_CONTEXT_KEY)); ok {
+			servicePrincipal, ok := principal.(*entity.ServicePrincipal)
+			if !ok || !servicePrincipal.HasPermission(permission) {
+				c.JSON(http.StatusForbidden, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeForbidden)))
+				c.Abort()
+				return
+			}
+
+			c.Next()
+			return
+		}
+
+		user, ok := c.Get(string(utils.USER_CONTEXT_KEY))
+		if !ok {
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
+			c.Abort()
+			return
+		}
+		userEntity, ok := user.(*entity.User)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
+			c.Abort()
+			return
+		}
+
+		allowed, err := m.helpers.UserHelper.HasPermission(c, userEntity, permission)
+		if err != nil {
+			logger.WithCtx(c).Error("HasPermission", err)
+			c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeInternalServerError)))
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeForbidden)))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -38,7 +38,7 @@ func (mw *rateLimitMiddleware) Handler() gin.HandlerFunc {
 			clientIP = c.GetHeader("X-Forwarded-For")
 		}
 		if clientIP == "" {
-			c.JSON(http.StatusTooManyRequests, errors.FormatErrorResponse(errors.New(errors.ErrCodeRateLimitExceeded)))
+			c.JSON(http.StatusTooManyRequests, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeRateLimitExceeded)))
 			c.Abort()
 			return
 		}
@@ -46,12 +46,12 @@ func (mw *rateLimitMiddleware) Handler() gin.HandlerFunc {
 		key := fmt.Sprintf("ip:%s", clientIP)
 		value, err := mw.redisClient.Incr(context.Background(), key)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(errors.New(errors.ErrCodeInternalServerError)))
+			c.JSON(http.StatusInternalServerError, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeInternalServerError)))
 			c.Abort()
 			return
 		}
 		if value > MAX_REQUESTS {
-			c.JSON(http.StatusTooManyRequests, errors.FormatErrorResponse(errors.New(errors.ErrCodeRateLimitExceeded)))
+			c.JSON(http.StatusTooManyRequests, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeRateLimitExceeded)))
 			c.Abort()
 			return
 		}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+)
+
+type verificationMiddleware struct{}
+
+func NewVerificationMiddleware() IVerificationMiddleware {
+	return &verificationMiddleware{}
+}
+
+// RequireVerified rejects users whose email or phone number (channel) isn't verified
+func (m *verificationMiddleware) RequireVerified(channel string) gin.HandlerFunc {
+	notVerifiedCode := errors.ErrCodeEmailNotVerified
+	if channel == entity.VERIFICATION_CHANNEL_PHONE {
+		notVerifiedCode = errors.ErrCodePhoneNotVerified
+	}
+
+	return func(c *gin.Context) {
+		user, ok := c.Get(string(utils.USER_CONTEXT_KEY))
+		if !ok {
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
+			c.Abort()
+			return
+		}
+		userEntity, ok := user.(*entity.User)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, errors.FormatErrorResponse(c, errors.New(errors.ErrCodeUnauthorized)))
+			c.Abort()
+			return
+		}
+
+		if !userEntity.IsVerified(channel) {
+			c.JSON(http.StatusForbidden, errors.FormatErrorResponse(c, errors.New(notVerifiedCode)))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"sondth-test_soa/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// CreateApiKeyRequest struct
+type CreateApiKeyRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Permissions []string `json:"permissions" validate:"required,min=1"`
+	ExpiresAt   *int64   `json:"expires_at"`
+}
+type CreateApiKeyResponse struct {
+	ApiKey entity.ApiKey `json:"api_key"`
+	Key    string        `json:"key"`
+}
+
+// GetApiKeysRequest struct
+type GetApiKeysRequest struct {
+	Active *bool `json:"active"`
+	Page   *int  `json:"page"`
+	Limit  *int  `json:"limit"`
+}
+type GetApiKeysResponse struct {
+	ApiKeys []entity.ApiKey `json:"api_keys"`
+	Count   int64           `json:"count"`
+}
+
+// RevokeApiKeyRequest struct
+type RevokeApiKeyRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+type RevokeApiKeyResponse struct{}
@@ -1,6 +1,9 @@
 package model
 
-import "sondth-test_soa/app/entity"
+import (
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
 
 // CreateCategoryRequest struct
 type CreateCategoryRequest struct {
@@ -10,12 +13,15 @@ type CreateCategoryResponse struct{}
 
 // GetCategoriesRequest struct
 type GetCategoriesRequest struct {
-	Page  *int `json:"page"`
-	Limit *int `json:"limit"`
+	Page  *int                 `json:"page"`
+	Limit *int                 `json:"limit"`
+	Sort  []repository.OrderBy `json:"sort" form:"sort"` // keys of repository.CategorySortFields
+	CursorRequest
 }
 type GetCategoriesResponse struct {
-	Count  int64             `json:"count"`
+	Count  *int64            `json:"count,omitempty"`
 	Result []entity.Category `json:"result"`
+	CursorResponse
 }
 
 // GetCategoriesSummaryRequest struct
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"sondth-test_soa/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// RecordStockMovementRequest struct, the quantity of an adjustment is negative to remove stock
+type RecordStockMovementRequest struct {
+	ProductID uuid.UUID  `json:"product_id" validate:"required"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	Type      string     `json:"type" validate:"required,oneof=receive adjust reserve release sell"`
+	Quantity  int64      `json:"quantity" validate:"required"`
+	Reason    string     `json:"reason" validate:"required,max=255"`
+}
+type RecordStockMovementResponse struct {
+	Movement entity.StockMovement `json:"movement"`
+}
+
+// GetStockMovementsRequest struct
+type GetStockMovementsRequest struct {
+	ProductID uuid.UUID  `json:"product_id" validate:"required"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	Types     []string   `json:"types" validate:"omitempty,dive,oneof=receive adjust reserve release sell"`
+	Page      *int       `json:"page"`
+	Limit     *int       `json:"limit"`
+}
+type GetStockMovementsResponse struct {
+	Count  int64                  `json:"count"`
+	Result []entity.StockMovement `json:"result"`
+}
+
+// GetLowStockReportRequest struct
+type GetLowStockReportRequest struct {
+	CategoryIDs []uuid.UUID `json:"category_ids"`
+	Page        *int        `json:"page"`
+	Limit       *int        `json:"limit"`
+}
+type GetLowStockReportResponse struct {
+	Count  int64            `json:"count"`
+	Result []entity.Product `json:"result"`
+}
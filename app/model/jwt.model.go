@@ -6,6 +6,28 @@ import (
 )
 
 type UserJWTPayload struct {
-	UserID uuid.UUID `json:"user_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	FamilyID     string    `json:"family_id,omitempty"`
+	TokenVersion int64     `json:"token_version"`
+	MfaVerified  bool      `json:"mfa_verified,omitempty"`
+	// ImpersonatorID is the admin acting as UserID, only set on impersonation tokens
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
+
+// TokenSession holds the claims shared by every token issued for one login
+type TokenSession struct {
+	FamilyID    string
+	MfaVerified bool
+}
+
+func (p *UserJWTPayload) Session() TokenSession {
+	return TokenSession{
+		FamilyID:    p.FamilyID,
+		MfaVerified: p.MfaVerified,
+	}
+}
+
+func (p *UserJWTPayload) IsImpersonated() bool {
+	return p.ImpersonatorID != nil
+}
@@ -0,0 +1,33 @@
+package model
+
+import "sondth-test_soa/package/jwks"
+
+// GetJWKSRequest struct
+type GetJWKSRequest struct{}
+type GetJWKSResponse struct {
+	Keys []jwks.JSONWebKey `json:"keys"`
+}
+
+// OidcUserInfo is the verified account of an identity provider
+type OidcUserInfo struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OidcAuthorizeRequest struct
+type OidcAuthorizeRequest struct {
+	Provider string `json:"provider" validate:"required"`
+}
+type OidcAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// OidcCallbackRequest struct
+type OidcCallbackRequest struct {
+	State string `json:"state" validate:"required"`
+	Code  string `json:"code" validate:"required"`
+	ClientInfo
+}
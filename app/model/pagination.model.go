@@ -0,0 +1,14 @@
+package model
+
+// CursorRequest continues a list from the cursor of a previous response, in place of a page number.
+// The cursor keeps the sort of the list, the sort of the request is ignored with it.
+type CursorRequest struct {
+	Cursor    *string `json:"cursor" form:"cursor"`
+	SkipCount bool    `json:"skip_count" form:"skip_count"` // leaves out the total count, saving a query
+}
+
+// CursorResponse holds the cursors of the pages around a list page, empty when there's no such page
+type CursorResponse struct {
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"sondth-test_soa/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// UserDataExport is everything stored about a user, as handed out by a data export
+type UserDataExport struct {
+	ExportedAt int64              `json:"exported_at"`
+	Profile    UserDataProfile    `json:"profile"`
+	Reviews    []UserDataReview   `json:"reviews"`
+	Wishlists  []UserDataWishlist `json:"wishlists"`
+	Addresses  []entity.Address   `json:"addresses"`
+	Preference *entity.Preference `json:"preference"` // null while the user never changed the defaults
+}
+
+type UserDataProfile struct {
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	Fullname      string    `json:"fullname"`
+	Role          string    `json:"role"`
+	Email         *string   `json:"email"`
+	Phone         *string   `json:"phone"`
+	EmailVerified bool      `json:"email_verified"`
+	PhoneVerified bool      `json:"phone_verified"`
+	MfaEnabled    bool      `json:"mfa_enabled"`
+	Status        string    `json:"status"`
+	AvatarURL     *string   `json:"avatar_url"`
+	CreatedAt     int64     `json:"created_at"`
+	UpdatedAt     int64     `json:"updated_at"`
+}
+
+type UserDataReview struct {
+	ID          uuid.UUID `json:"id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Rating      float64   `json:"rating"`
+	Comment     string    `json:"comment"`
+	CreatedAt   int64     `json:"created_at"`
+	UpdatedAt   int64     `json:"updated_at"`
+}
+
+type UserDataWishlist struct {
+	ID          uuid.UUID `json:"id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	CreatedAt   int64     `json:"created_at"`
+}
+
+// RequestDataExportRequest struct
+type RequestDataExportRequest struct {
+	Format string `json:"format" validate:"omitempty,oneof=json zip"`
+}
+type RequestDataExportResponse struct {
+	Job entity.DataJob `json:"job"`
+}
+
+// RequestDataErasureRequest struct
+type RequestDataErasureRequest struct {
+	Password string `json:"password" validate:"required"`
+	MfaCode  string `json:"mfa_code"` // authentication or recovery code, needed when 2FA is enabled
+}
+type RequestDataErasureResponse struct {
+	Job entity.DataJob `json:"job"`
+}
+
+// GetDataJobRequest struct
+type GetDataJobRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+type GetDataJobResponse struct {
+	Job entity.DataJob `json:"job"`
+}
+
+// DownloadDataExportRequest struct
+type DownloadDataExportRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+type DownloadDataExportResponse struct {
+	FilePath string
+	FileName string
+}
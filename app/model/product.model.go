@@ -7,34 +7,41 @@ import (
 	"github.com/google/uuid"
 )
 
-// CreateProductRequest struct
+// CreateProductRequest struct, the quantity is received into the stock ledger as the initial stock
 type CreateProductRequest struct {
-	Name        string    `json:"name" validate:"required"`
-	Description *string   `json:"description"`
-	Price       float64   `json:"price" validate:"required"`
-	Quantity    uint64    `json:"quantity" validate:"required"`
-	CategoryID  uuid.UUID `json:"category_id" validate:"required"`
+	Name              string    `json:"name" validate:"required"`
+	Description       *string   `json:"description"`
+	Price             float64   `json:"price" validate:"required"`
+	Quantity          uint64    `json:"quantity"`
+	LowStockThreshold *uint64   `json:"low_stock_threshold"`
+	CategoryID        uuid.UUID `json:"category_id" validate:"required"`
 }
 type CreateProductResponse struct{}
 
 // GetProductRequest struct
 type GetProductRequest struct {
-	Name        *string            `json:"name"`
-	CategoryIDs []uuid.UUID        `json:"category_ids"`
-	Page        *int               `json:"page"`
-	Limit       *int               `json:"limit"`
-	Status      *string            `json:"status"`
-	Order       repository.OrderBy `json:"order"`
+	Name        *string              `json:"name"`
+	CategoryIDs []uuid.UUID          `json:"category_ids"`
+	Page        *int                 `json:"page"`
+	Limit       *int                 `json:"limit"`
+	Status      *string              `json:"status" validate:"omitempty,oneof=in_stock out_of_stock low_stock"`
+	Sort        []repository.OrderBy `json:"sort" form:"sort"` // keys of repository.ProductSortFields
+	CursorRequest
 }
 type GetProductResponse struct {
-	Count  int64            `json:"count"`
+	Count  *int64           `json:"count,omitempty"`
 	Result []entity.Product `json:"result"`
+	CursorResponse
 }
 
-// UpdateProductRequest struct
+// UpdateProductRequest struct, the quantity only changes through stock movements
 type UpdateProductRequest struct {
-	ID uuid.UUID `json:"id" validate:"required"`
-	CreateProductRequest
+	ID                uuid.UUID `json:"id" validate:"required"`
+	Name              string    `json:"name" validate:"required"`
+	Description       *string   `json:"description"`
+	Price             float64   `json:"price" validate:"required"`
+	LowStockThreshold *uint64   `json:"low_stock_threshold"`
+	CategoryID        uuid.UUID `json:"category_id" validate:"required"`
 }
 type UpdateProductResponse struct {
 	Product entity.Product `json:"product"`
@@ -45,3 +52,40 @@ type DeleteProductRequest struct {
 	ID uuid.UUID `json:"id" validate:"required"`
 }
 type DeleteProductResponse struct{}
+
+// CreateProductVariantRequest struct, the quantity is received into the stock ledger as the initial stock
+type CreateProductVariantRequest struct {
+	ProductID uuid.UUID         `json:"product_id" validate:"required"`
+	Sku       string            `json:"sku" validate:"required,max=64"`
+	Options   map[string]string `json:"options" validate:"required,min=1,dive,keys,required,max=32,endkeys,required,max=64"`
+	Price     *float64          `json:"price" validate:"omitempty,gt=0"`
+	Quantity  uint64            `json:"quantity"`
+}
+type CreateProductVariantResponse struct {
+	Variant entity.ProductVariant `json:"variant"`
+}
+
+// UpdateProductVariantRequest struct, an empty price makes the variant cost the price of the product
+type UpdateProductVariantRequest struct {
+	ID      uuid.UUID         `json:"id" validate:"required"`
+	Sku     string            `json:"sku" validate:"required,max=64"`
+	Options map[string]string `json:"options" validate:"required,min=1,dive,keys,required,max=32,endkeys,required,max=64"`
+	Price   *float64          `json:"price" validate:"omitempty,gt=0"`
+}
+type UpdateProductVariantResponse struct {
+	Variant entity.ProductVariant `json:"variant"`
+}
+
+// DeleteProductVariantRequest struct
+type DeleteProductVariantRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+type DeleteProductVariantResponse struct{}
+
+// GetProductVariantsRequest struct
+type GetProductVariantsRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+}
+type GetProductVariantsResponse struct {
+	Variants []entity.ProductVariant `json:"variants"`
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"sondth-test_soa/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// GetAddressesRequest struct
+type GetAddressesRequest struct{}
+type GetAddressesResponse struct {
+	Addresses []entity.Address `json:"addresses"`
+}
+
+// CreateAddressRequest struct
+type CreateAddressRequest struct {
+	Label      *string `json:"label" validate:"omitempty,max=64"`
+	Recipient  string  `json:"recipient" validate:"required,max=255"`
+	Phone      string  `json:"phone" validate:"required,phone_number"`
+	Line1      string  `json:"line1" validate:"required,max=255"`
+	Line2      *string `json:"line2" validate:"omitempty,max=255"`
+	City       string  `json:"city" validate:"required,max=128"`
+	State      *string `json:"state" validate:"omitempty,max=128"`
+	PostalCode *string `json:"postal_code" validate:"omitempty,max=32"`
+	Country    string  `json:"country" validate:"required,iso3166_1_alpha2"`
+	IsDefault  bool    `json:"is_default"`
+}
+type CreateAddressResponse struct {
+	Address entity.Address `json:"address"`
+}
+
+// UpdateAddressRequest struct
+type UpdateAddressRequest struct {
+	ID         uuid.UUID `json:"id" validate:"required"`
+	Label      *string   `json:"label" validate:"omitempty,max=64"`
+	Recipient  *string   `json:"recipient" validate:"omitempty,min=1,max=255"`
+	Phone      *string   `json:"phone" validate:"omitempty,phone_number"`
+	Line1      *string   `json:"line1" validate:"omitempty,min=1,max=255"`
+	Line2      *string   `json:"line2" validate:"omitempty,max=255"`
+	City       *string   `json:"city" validate:"omitempty,min=1,max=128"`
+	State      *string   `json:"state" validate:"omitempty,max=128"`
+	PostalCode *string   `json:"postal_code" validate:"omitempty,max=32"`
+	Country    *string   `json:"country" validate:"omitempty,iso3166_1_alpha2"`
+}
+type UpdateAddressResponse struct {
+	Address entity.Address `json:"address"`
+}
+
+// DeleteAddressRequest struct
+type DeleteAddressRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+type DeleteAddressResponse struct{}
+
+// SetDefaultAddressRequest struct
+type SetDefaultAddressRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+type SetDefaultAddressResponse struct {
+	Address entity.Address `json:"address"`
+}
+
+// UploadAvatarRequest struct, Content is read from the multipart file "avatar"
+type UploadAvatarRequest struct {
+	Content []byte `json:"-"`
+}
+type UploadAvatarResponse struct {
+	AvatarURL string `json:"avatar_url"`
+}
+
+// DeleteAvatarRequest struct
+type DeleteAvatarRequest struct{}
+type DeleteAvatarResponse struct{}
+
+// GetPreferencesRequest struct
+type GetPreferencesRequest struct{}
+type GetPreferencesResponse struct {
+	Preference entity.Preference `json:"preference"`
+}
+
+// UpdatePreferencesRequest struct
+type UpdatePreferencesRequest struct {
+	Language        *string `json:"language" validate:"omitempty,min=2,max=8"`
+	Currency        *string `json:"currency" validate:"omitempty,iso4217"`
+	NotifyEmail     *bool   `json:"notify_email"`
+	NotifySms       *bool   `json:"notify_sms"`
+	NotifyMarketing *bool   `json:"notify_marketing"`
+}
+type UpdatePreferencesResponse struct {
+	Preference entity.Preference `json:"preference"`
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
 
 	"github.com/google/uuid"
 )
@@ -23,13 +24,16 @@ type DeleteReviewResponse struct{}
 
 // GetReviewsRequest struct
 type GetReviewsRequest struct {
-	ProductName *string `json:"product_name"`
-	Page        *int    `json:"page"`
-	Limit       *int    `json:"limit"`
+	ProductName *string              `json:"product_name"`
+	Page        *int                 `json:"page"`
+	Limit       *int                 `json:"limit"`
+	Sort        []repository.OrderBy `json:"sort" form:"sort"` // keys of repository.ReviewSortFields
+	CursorRequest
 }
 type GetReviewsResponse struct {
 	Reviews []entity.Review `json:"reviews"`
-	Count   int64           `json:"count"`
+	Count   *int64          `json:"count,omitempty"`
+	CursorResponse
 }
 
 // GetReviewsSummaryRequest struct
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"sondth-test_soa/app/entity"
+
+	"github.com/google/uuid"
+)
+
+// CreateRoleRequest struct
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+type CreateRoleResponse struct {
+	Role entity.Role `json:"role"`
+}
+
+// UpdateRoleRequest struct
+type UpdateRoleRequest struct {
+	ID          uuid.UUID `json:"id" validate:"required"`
+	Description *string   `json:"description"`
+	Permissions []string  `json:"permissions"`
+}
+type UpdateRoleResponse struct {
+	Role entity.Role `json:"role"`
+}
+
+// DeleteRoleRequest struct
+type DeleteRoleRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+type DeleteRoleResponse struct{}
+
+// GetRolesRequest struct
+type GetRolesRequest struct {
+	Page  *int `json:"page"`
+	Limit *int `json:"limit"`
+}
+type GetRolesResponse struct {
+	Roles []entity.Role `json:"roles"`
+	Count int64         `json:"count"`
+}
+
+// GetPermissionsRequest struct
+type GetPermissionsRequest struct{}
+type GetPermissionsResponse struct {
+	Permissions []entity.Permission `json:"permissions"`
+}
+
+// AssignRoleRequest struct
+type AssignRoleRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+	RoleID uuid.UUID `json:"role_id" validate:"required"`
+}
+type AssignRoleResponse struct{}
+
+// UnassignRoleRequest struct
+type UnassignRoleRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+	RoleID uuid.UUID `json:"role_id" validate:"required"`
+}
+type UnassignRoleResponse struct{}
@@ -2,65 +2,273 @@ package model
 
 import (
 	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
 
 	"github.com/google/uuid"
 )
 
+// ClientInfo is filled from the HTTP request by the controller, never from the body
+type ClientInfo struct {
+	ClientIP  string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
 // UserLoginRequest struct
 type UserLoginRequest struct {
+	ClientInfo
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
 type UserLoginResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	MfaRequired  bool   `json:"mfa_required"`
+	MfaToken     string `json:"mfa_token,omitempty"`
+}
+
+// LoginMfaRequest struct
+type LoginMfaRequest struct {
+	ClientInfo
+	MfaToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+type LoginMfaResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// EnrollMfaRequest struct
+type EnrollMfaRequest struct{}
+type EnrollMfaResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// ActivateMfaRequest struct
+type ActivateMfaRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+type ActivateMfaResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// DisableMfaRequest struct
+type DisableMfaRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+type DisableMfaResponse struct{}
+
+// RefreshTokenRequest struct
+type RefreshTokenRequest struct {
+	ClientInfo
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+type RefreshTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
+// LogoutRequest struct
+type LogoutRequest struct{}
+type LogoutResponse struct{}
+
+// LogoutAllRequest struct
+type LogoutAllRequest struct{}
+type LogoutAllResponse struct{}
+
 // UserRegisterRequest struct
 type UserRegisterRequest struct {
-	Username        string `json:"username" validate:"required"`
-	Password        string `json:"password" validate:"required"`
-	Fullname        string `json:"fullname" validate:"required"`
-	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=Password"`
+	Username        string  `json:"username" validate:"required"`
+	Password        string  `json:"password" validate:"required,password"`
+	Fullname        string  `json:"fullname" validate:"required"`
+	ConfirmPassword string  `json:"confirm_password" validate:"required,eqfield=Password"`
+	Email           *string `json:"email" validate:"omitempty,email"`
+	Phone           *string `json:"phone" validate:"omitempty,phone_number"`
 }
 type UserRegisterResponse struct{}
 
 // UserForgotPasswordRequest struct
 type ChangeUserPasswordRequest struct {
 	ID              uuid.UUID `json:"id" validate:"required"`
-	OldPassword     string    `json:"old_password" validate:"required"`
-	NewPassword     string    `json:"new_password" validate:"required"`
+	OldPassword     string    `json:"old_password"`
+	NewPassword     string    `json:"new_password" validate:"required,password"`
 	ConfirmPassword string    `json:"confirm_password" validate:"required,eqfield=NewPassword"`
 }
 type ChangeUserPasswordResponse struct{}
 
 // UpdateUserRequest struct
 type UpdateUserRequest struct {
-	ID       uuid.UUID `param:"id" validate:"required"`
+	ID       uuid.UUID `json:"-" validate:"required"` // from the path
 	Username *string   `json:"username"`
 	Fullname *string   `json:"fullname"`
 	Role     *string   `json:"role"`
+	Email    *string   `json:"email" validate:"omitempty,email"`
+	Phone    *string   `json:"phone" validate:"omitempty,phone_number"`
 }
 type UpdateUserResponse struct {
 	User entity.User `json:"user"`
 }
 
+// GetMeRequest struct
+type GetMeRequest struct{}
+type GetMeResponse struct {
+	User        entity.User `json:"user"`
+	Permissions []string    `json:"permissions"`
+}
+
+// UpdateProfileRequest struct
+type UpdateProfileRequest struct {
+	Fullname *string `json:"fullname" validate:"omitempty,min=1,max=255"`
+	Email    *string `json:"email" validate:"omitempty,email"`
+	Phone    *string `json:"phone" validate:"omitempty,phone_number"`
+}
+type UpdateProfileResponse struct {
+	User entity.User `json:"user"`
+}
+
 // ForgetPasswordRequest struct
 type ForgetPasswordRequest struct {
-	Username        string `json:"username" validate:"required"`
-	NewPassword     string `json:"new_password" validate:"required"`
-	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
+	Username string `json:"username" validate:"required"`
 }
 type ForgetPasswordResponse struct{}
 
+// ResetPasswordRequest struct
+type ResetPasswordRequest struct {
+	Token           string `json:"token" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,password"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
+}
+type ResetPasswordResponse struct{}
+
+// SendVerificationCodeRequest struct
+type SendVerificationCodeRequest struct {
+	Channel string `json:"channel" validate:"required,oneof=email phone"`
+}
+type SendVerificationCodeResponse struct{}
+
+// ConfirmVerificationCodeRequest struct
+type ConfirmVerificationCodeRequest struct {
+	Channel string `json:"channel" validate:"required,oneof=email phone"`
+	Code    string `json:"code" validate:"required"`
+}
+type ConfirmVerificationCodeResponse struct {
+	User entity.User `json:"user"`
+}
+
+// UnlockUserRequest struct
+type UnlockUserRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+type UnlockUserResponse struct{}
+
+// GetSessionsRequest struct
+type GetSessionsRequest struct {
+	Page  *int `json:"page" form:"page"`
+	Limit *int `json:"limit" form:"limit"`
+}
+type GetSessionsResponse struct {
+	Sessions         []entity.Session `json:"sessions"`
+	CurrentSessionID string           `json:"current_session_id"`
+}
+
+// RevokeSessionRequest struct
+type RevokeSessionRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+type RevokeSessionResponse struct{}
+
+// UpdateUserStatusRequest struct
+type UpdateUserStatusRequest struct {
+	UserID         uuid.UUID `json:"user_id" validate:"required"`
+	Status         string    `json:"status" validate:"required,oneof=active suspended deleted"`
+	Reason         *string   `json:"reason" validate:"omitempty,max=1000"`
+	SuspendedUntil *int64    `json:"suspended_until"`
+}
+type UpdateUserStatusResponse struct{}
+
+// RevokeUserSessionsRequest struct
+type RevokeUserSessionsRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+}
+type RevokeUserSessionsResponse struct{}
+
 // GetUserRequest struct
 type GetUsersRequest struct {
-	Name  *string `json:"name"`
-	Role  *string `json:"role"`
-	Page  *int    `json:"page"`
-	Limit *int    `json:"limit"`
+	Name        *string              `json:"name"`
+	Role        *string              `json:"role"`
+	Status      *string              `json:"status"`
+	CreatedFrom *int64               `json:"created_from"`
+	CreatedTo   *int64               `json:"created_to"`
+	Sort        []repository.OrderBy `json:"sort"` // keys of repository.UserSortFields
+	Page        *int                 `json:"page"`
+	Limit       *int                 `json:"limit"`
+	CursorRequest
 }
 type GetUsersResponse struct {
 	Users []entity.User `json:"users"`
-	Count int64         `json:"count"`
+	Count *int64        `json:"count,omitempty"`
+	CursorResponse
+}
+
+// ImpersonateUserRequest struct
+type ImpersonateUserRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+	ClientInfo
+}
+type ImpersonateUserResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// GetImpersonationLogsRequest struct
+type GetImpersonationLogsRequest struct {
+	ImpersonatorID *uuid.UUID `json:"impersonator_id"`
+	UserID         *uuid.UUID `json:"user_id"`
+	Page           *int       `json:"page"`
+	Limit          *int       `json:"limit"`
+}
+type GetImpersonationLogsResponse struct {
+	Logs  []entity.ImpersonationLog `json:"logs"`
+	Count int64                     `json:"count"`
+}
+
+// CreateUserRequest struct
+type CreateUserRequest struct {
+	Username string  `json:"username" validate:"required"`
+	Password string  `json:"password" validate:"required,password"`
+	Fullname string  `json:"fullname" validate:"required"`
+	Role     string  `json:"role" validate:"required"`
+	Email    *string `json:"email" validate:"omitempty,email"`
+	Phone    *string `json:"phone" validate:"omitempty,phone_number"`
+}
+type CreateUserResponse struct {
+	User entity.User `json:"user"`
+}
+
+// InviteUserRequest struct
+type InviteUserRequest struct {
+	Username string `json:"username" validate:"required"`
+	Fullname string `json:"fullname" validate:"required"`
+	Role     string `json:"role" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+}
+type InviteUserResponse struct {
+	User entity.User `json:"user"`
+}
+
+// ActivateUserRequest struct
+type ActivateUserRequest struct {
+	Token           string `json:"token" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,password"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
+}
+type ActivateUserResponse struct{}
+
+// BulkUpdateUserRoleRequest struct
+type BulkUpdateUserRoleRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" validate:"required,min=1,max=100,unique"`
+	Role    string      `json:"role" validate:"required"`
+}
+type BulkUpdateUserRoleResponse struct {
+	Updated int `json:"updated"`
 }
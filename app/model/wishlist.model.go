@@ -2,6 +2,7 @@ package model
 
 import (
 	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
 
 	"github.com/google/uuid"
 )
@@ -22,12 +23,15 @@ type DeleteFromWishlistResponse struct {
 
 // GetWishlistRequest struct
 type GetWishlistsRequest struct {
-	Page  int `json:"page"`
-	Limit int `json:"limit"`
+	Page  int                  `json:"page"`
+	Limit int                  `json:"limit"`
+	Sort  []repository.OrderBy `json:"sort" form:"sort"` // keys of repository.WishlistSortFields
+	CursorRequest
 }
 type GetWishlistsResponse struct {
 	Wishlists []entity.Wishlist `json:"wishlists"`
-	Count     int64             `json:"count"`
+	Count     *int64            `json:"count,omitempty"`
+	CursorResponse
 }
 
 // GetWishlistsSummaryRequest struct
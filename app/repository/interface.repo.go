@@ -2,17 +2,34 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"sondth-test_soa/app/entity"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// ErrInsufficientStock is returned when a stock movement would make a quantity negative
+var ErrInsufficientStock = errors.New("insufficient stock")
+
 type RepositoryCollections struct {
-	ProductRepo  IProductRepository
-	CategoryRepo ICategoryRepository
-	ReviewRepo   IReviewRepository
-	WishlistRepo IWishlistRepository
-	UserRepo     IUserRepository
+	ProductRepo          IProductRepository
+	ProductVariantRepo   IProductVariantRepository
+	StockMovementRepo    IStockMovementRepository
+	CategoryRepo         ICategoryRepository
+	ReviewRepo           IReviewRepository
+	WishlistRepo         IWishlistRepository
+	UserRepo             IUserRepository
+	RoleRepo             IRoleRepository
+	PermissionRepo       IPermissionRepository
+	SessionRepo          ISessionRepository
+	PasswordHistoryRepo  IPasswordHistoryRepository
+	ApiKeyRepo           IApiKeyRepository
+	IdentityRepo         IIdentityRepository
+	ImpersonationLogRepo IImpersonationLogRepository
+	DataJobRepo          IDataJobRepository
+	AddressRepo          IAddressRepository
+	PreferenceRepo       IPreferenceRepository
 }
 
 type IProductRepository interface {
@@ -22,12 +39,29 @@ type IProductRepository interface {
 	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindProductByFilter) (*entity.Product, error)
 	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindProductByFilter) ([]entity.Product, error)
 	CountByFilter(ctx context.Context, tx *gorm.DB, filter *FindProductByFilter) (int64, error)
+	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+}
+
+type IProductVariantRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, data *entity.ProductVariant) error
+	Update(ctx context.Context, tx *gorm.DB, data *entity.ProductVariant) error
+	Delete(ctx context.Context, tx *gorm.DB, data *entity.ProductVariant) error
+	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindProductVariantByFilter) (*entity.ProductVariant, error)
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindProductVariantByFilter) ([]entity.ProductVariant, error)
+	GetVariantSummaries(ctx context.Context, tx *gorm.DB, productIDs []uuid.UUID) ([]entity.ProductVariantSummary, error)
+}
+
+type IStockMovementRepository interface {
+	Record(ctx context.Context, tx *gorm.DB, data *entity.StockMovement) error
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindStockMovementByFilter) ([]entity.StockMovement, error)
+	CountByFilter(ctx context.Context, tx *gorm.DB, filter *FindStockMovementByFilter) (int64, error)
 }
 
 type ICategoryRepository interface {
 	Create(ctx context.Context, tx *gorm.DB, data *entity.Category) error
 	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindCategoryByFilter) ([]entity.Category, error)
 	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindCategoryByFilter) (*entity.Category, error)
+	CountByFilter(ctx context.Context, tx *gorm.DB, filter *FindCategoryByFilter) (int64, error)
 	GetCategorySummary(ctx context.Context, tx *gorm.DB) ([]entity.Category, error)
 }
 
@@ -37,6 +71,8 @@ type IUserRepository interface {
 	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindUserByFilter) ([]entity.User, error)
 	CountByFilter(ctx context.Context, tx *gorm.DB, filter *FindUserByFilter) (int64, error)
 	Update(ctx context.Context, tx *gorm.DB, data *entity.User) error
+	UpdatePassword(ctx context.Context, tx *gorm.DB, data *entity.User) error
+	UpdateRoleByFilter(ctx context.Context, tx *gorm.DB, filter *FindUserByFilter, role string) error
 }
 
 type IReviewRepository interface {
@@ -53,4 +89,82 @@ type IWishlistRepository interface {
 	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindWishlistByFilter) ([]entity.Wishlist, error)
 	CountByFilter(ctx context.Context, tx *gorm.DB, filter *FindWishlistByFilter) (int64, error)
 	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindWishlistByFilter) (*entity.Wishlist, error)
+	DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindWishlistByFilter) error
+}
+
+type IRoleRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, data *entity.Role) error
+	Update(ctx context.Context, tx *gorm.DB, data *entity.Role) error
+	Delete(ctx context.Context, tx *gorm.DB, data *entity.Role) error
+	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindRoleByFilter) (*entity.Role, error)
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindRoleByFilter) ([]entity.Role, error)
+	CountByFilter(ctx context.Context, tx *gorm.DB, filter *FindRoleByFilter) (int64, error)
+	ReplacePermissions(ctx context.Context, tx *gorm.DB, data *entity.Role, permissions []entity.Permission) error
+	AssignToUser(ctx context.Context, tx *gorm.DB, data *entity.UserRole) error
+	UnassignFromUser(ctx context.Context, tx *gorm.DB, data *entity.UserRole) error
+	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+}
+
+type IPermissionRepository interface {
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindPermissionByFilter) ([]entity.Permission, error)
+}
+
+type ISessionRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, data *entity.Session) error
+	Update(ctx context.Context, tx *gorm.DB, data *entity.Session) error
+	RevokeManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindSessionByFilter) error
+	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindSessionByFilter) (*entity.Session, error)
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindSessionByFilter) ([]entity.Session, error)
+}
+
+type IPasswordHistoryRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, data *entity.PasswordHistory) error
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindPasswordHistoryByFilter) ([]entity.PasswordHistory, error)
+	DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindPasswordHistoryByFilter) error
+}
+
+type IApiKeyRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, data *entity.ApiKey) error
+	Update(ctx context.Context, tx *gorm.DB, data *entity.ApiKey) error
+	UpdateLastUsedAt(ctx context.Context, tx *gorm.DB, data *entity.ApiKey) error
+	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindApiKeyByFilter) (*entity.ApiKey, error)
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindApiKeyByFilter) ([]entity.ApiKey, error)
+	CountByFilter(ctx context.Context, tx *gorm.DB, filter *FindApiKeyByFilter) (int64, error)
+}
+
+type IIdentityRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, data *entity.Identity) error
+	Update(ctx context.Context, tx *gorm.DB, data *entity.Identity) error
+	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindIdentityByFilter) (*entity.Identity, error)
+	DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindIdentityByFilter) error
+}
+
+type IImpersonationLogRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, data *entity.ImpersonationLog) error
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindImpersonationLogByFilter) ([]entity.ImpersonationLog, error)
+	CountByFilter(ctx context.Context, tx *gorm.DB, filter *FindImpersonationLogByFilter) (int64, error)
+}
+
+type IDataJobRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, data *entity.DataJob) error
+	Update(ctx context.Context, tx *gorm.DB, data *entity.DataJob) error
+	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindDataJobByFilter) (*entity.DataJob, error)
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindDataJobByFilter) ([]entity.DataJob, error)
+}
+
+type IAddressRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, data *entity.Address) error
+	Update(ctx context.Context, tx *gorm.DB, data *entity.Address) error
+	Delete(ctx context.Context, tx *gorm.DB, data *entity.Address) error
+	UnsetDefaultByFilter(ctx context.Context, tx *gorm.DB, filter *FindAddressByFilter) error
+	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindAddressByFilter) (*entity.Address, error)
+	FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindAddressByFilter) ([]entity.Address, error)
+	CountByFilter(ctx context.Context, tx *gorm.DB, filter *FindAddressByFilter) (int64, error)
+	DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindAddressByFilter) error
+}
+
+type IPreferenceRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, data *entity.Preference) error
+	FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *FindPreferenceByFilter) (*entity.Preference, error)
+	DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *FindPreferenceByFilter) error
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/google/uuid"
 )
 
+// OrderBy is one key of a sort, keys are applied in the order they're given
 type OrderBy struct {
 	Field string `json:"field"`
 	Order string `json:"order"`
@@ -20,29 +21,61 @@ type FindProductByFilter struct {
 	CategoryIDs []uuid.UUID
 	Page        *int
 	Limit       *int
-	Status      *string
-	Order       OrderBy
+	Peek        bool    // read one row past Limit, telling if there's a next page
+	Status      *string // stock status, derived from the quantity like entity.Product.SetStatus
+	BelowLimit  bool    // at or below the low-stock threshold, out of stock included
+	Sort        []OrderBy
+	Keyset      *Keyset
 
 	// Relationship
 	CategoryFields []string
 }
 
+type FindProductVariantByFilter struct {
+	Filter
+	ID        *uuid.UUID
+	ProductID *uuid.UUID
+	Sku       *string
+}
+
+type FindStockMovementByFilter struct {
+	Filter
+	ProductID *uuid.UUID
+	VariantID *uuid.UUID
+	Types     []string
+	Page      *int
+	Limit     *int
+}
+
 type FindCategoryByFilter struct {
 	Filter
-	ID    *uuid.UUID
-	Name  *string
-	Page  *int
-	Limit *int
+	ID     *uuid.UUID
+	Name   *string
+	Page   *int
+	Limit  *int
+	Peek   bool
+	Sort   []OrderBy
+	Keyset *Keyset
 }
 
 type FindUserByFilter struct {
 	Filter
-	ID       *uuid.UUID
-	Page     *int
-	Limit    *int
-	Role     *string
-	Name     *string
-	Username *string
+	ID          *uuid.UUID
+	IDs         []uuid.UUID
+	RoleID      *uuid.UUID // users holding the role, as their main role or an assigned one
+	Page        *int
+	Limit       *int
+	Peek        bool
+	Role        *string
+	Name        *string
+	Username    *string
+	Email       *string
+	Phone       *string
+	Status      *string
+	CreatedFrom *int64
+	CreatedTo   *int64
+	Sort        []OrderBy
+	Keyset      *Keyset
 }
 
 type FindReviewByFilter struct {
@@ -53,6 +86,9 @@ type FindReviewByFilter struct {
 	UserID      *uuid.UUID
 	Page        *int
 	Limit       *int
+	Peek        bool
+	Sort        []OrderBy
+	Keyset      *Keyset
 
 	// Relationship
 	ProductFields []string
@@ -65,9 +101,93 @@ type FindWishlistByFilter struct {
 	UserID    *uuid.UUID
 	Page      *int
 	Limit     *int
+	Peek      bool
 	ProductID *uuid.UUID
+	Sort      []OrderBy
+	Keyset    *Keyset
 
 	// Relationship
 	ProductFields []string
 	UserFields    []string
 }
+
+type FindRoleByFilter struct {
+	Filter
+	ID     *uuid.UUID
+	Name   *string
+	UserID *uuid.UUID
+	Page   *int
+	Limit  *int
+
+	// Relationship
+	PermissionFields []string
+}
+
+type FindPermissionByFilter struct {
+	Filter
+	Codes []string
+
+	// Permissions granted to a user, through its role and the roles assigned to it
+	UserID   *uuid.UUID
+	UserRole *string
+}
+
+type FindSessionByFilter struct {
+	Filter
+	ID     *uuid.UUID
+	UserID *uuid.UUID
+	Active *bool
+	Page   *int
+	Limit  *int
+}
+
+type FindPasswordHistoryByFilter struct {
+	Filter
+	UserID *uuid.UUID
+	Limit  *int
+}
+
+type FindApiKeyByFilter struct {
+	Filter
+	ID      *uuid.UUID
+	KeyHash *string
+	Active  *bool
+	Page    *int
+	Limit   *int
+}
+
+type FindIdentityByFilter struct {
+	Filter
+	ID       *uuid.UUID
+	UserID   *uuid.UUID
+	Provider *string
+	Subject  *string
+}
+
+type FindImpersonationLogByFilter struct {
+	Filter
+	ImpersonatorID *uuid.UUID
+	UserID         *uuid.UUID
+	Page           *int
+	Limit          *int
+}
+
+type FindDataJobByFilter struct {
+	Filter
+	ID       *uuid.UUID
+	UserID   *uuid.UUID
+	Type     *string
+	Statuses []string
+}
+
+type FindAddressByFilter struct {
+	Filter
+	ID        *uuid.UUID
+	UserID    *uuid.UUID
+	IsDefault *bool
+}
+
+type FindPreferenceByFilter struct {
+	Filter
+	UserID *uuid.UUID
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Sort directions, the sort is ascending when the direction is empty
+const (
+	SORT_ASC  = "asc"
+	SORT_DESC = "desc"
+)
+
+// MAX_SORT_KEYS is how many keys a list can be sorted by at once
+const MAX_SORT_KEYS = 3
+
+// ErrKeysetNotFound is returned when the row a keyset continues from doesn't exist anymore
+var ErrKeysetNotFound = errors.New("keyset row not found")
+
+// Keyset continues a list from a row in the order of its sort, in place of skipping rows with an offset.
+// Pages don't get slower the deeper they are, and rows added or removed meanwhile don't shift them.
+type Keyset struct {
+	ID       uuid.UUID // the last row of the previous page
+	Backward bool      // the rows before it, closest first
+}
+
+// SortFields is the sort whitelist of a list
+type SortFields struct {
+	Table   string
+	Columns map[string]string // sort keys accepted from requests, to the column or expression they sort on
+	Default []OrderBy         // sort of the list when the request has none
+}
+
+var (
+	ProductSortFields = SortFields{
+		Table: "products",
+		Columns: map[string]string{
+			"price":      "products.price",
+			"name":       "products.name",
+			"created_at": "products.created_at",
+			"quantity":   "products.quantity",
+			"rating":     "(SELECT COALESCE(AVG(reviews.rating), 0) FROM reviews WHERE reviews.product_id = products.id)",
+			"popularity": "(SELECT COUNT(*) FROM wishlists WHERE wishlists.product_id = products.id)", // how many users wish for the product
+		},
+		Default: []OrderBy{{Field: "created_at", Order: SORT_DESC}},
+	}
+	UserSortFields = SortFields{
+		Table: "users",
+		Columns: map[string]string{
+			"created_at": "users.created_at",
+			"updated_at": "users.updated_at",
+			"username":   "users.username",
+			"fullname":   "users.fullname",
+		},
+		Default: []OrderBy{{Field: "created_at", Order: SORT_DESC}},
+	}
+	ReviewSortFields = SortFields{
+		Table: "reviews",
+		Columns: map[string]string{
+			"created_at": "reviews.created_at",
+			"rating":     "reviews.rating",
+		},
+		Default: []OrderBy{{Field: "created_at", Order: SORT_DESC}},
+	}
+	WishlistSortFields = SortFields{
+		Table: "wishlists",
+		Columns: map[string]string{
+			"created_at": "wishlists.created_at",
+		},
+		Default: []OrderBy{{Field: "created_at", Order: SORT_DESC}},
+	}
+	CategorySortFields = SortFields{
+		Table: "categories",
+		Columns: map[string]string{
+			"name":       "categories.name",
+			"created_at": "categories.created_at",
+		},
+		Default: []OrderBy{{Field: "name", Order: SORT_ASC}},
+	}
+)
+
+type sortColumn struct {
+	column     string
+	descending bool
+}
+
+// Keys returns the sort keys of the whitelist in alphabetical order
+func (f SortFields) Keys() []string {
+	keys := make([]string, 0, len(f.Columns))
+	for key := range f.Columns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Has tells if the list can be sorted by key
+func (f SortFields) Has(key string) bool {
+	_, ok := f.Columns[key]
+	return ok
+}
+
+// Paginate sorts the query by keys and moves it to a page, either after the row of keyset or at page from an offset.
+// Keys outside of the whitelist are skipped so the request never reaches the SQL. With peek, one row past the limit
+// is read to tell if there's a next page.
+func (f SortFields) Paginate(keys []OrderBy, keyset *Keyset, page *int, limit *int, peek bool) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		columns := f.columns(keys)
+		backward := keyset != nil && keyset.Backward
+
+		orders := make([]string, 0, len(columns))
+		for _, column := range columns {
+			if column.descending != backward {
+				orders = append(orders, column.column+" DESC")
+			} else {
+				orders = append(orders, column.column+" ASC")
+			}
+		}
+		query = query.Order(strings.Join(orders, ", "))
+
+		if keyset != nil {
+			where, args := f.keysetCondition(columns, keyset)
+			query = query.Where(where, args...)
+		} else if page != nil && limit != nil {
+			query = query.Offset((*page - 1) * *limit)
+		}
+		if limit != nil && peek {
+			query = query.Limit(*limit + 1)
+		} else if limit != nil {
+			query = query.Limit(*limit)
+		}
+
+		return query
+	}
+}
+
+// CheckKeyset makes sure the row of keyset still exists, as no row would match the keyset condition without it
+func (f SortFields) CheckKeyset(query *gorm.DB, keyset *Keyset) error {
+	if keyset == nil {
+		return nil
+	}
+
+	var count int64
+	err := query.Session(&gorm.Session{NewDB: true}).
+		Table(f.Table).
+		Where(f.Table+".id = ?", keyset.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrKeysetNotFound
+	}
+
+	return nil
+}
+
+// columns resolves the sort keys, ending with the ID so rows never tie and keyset pages are stable
+func (f SortFields) columns(keys []OrderBy) []sortColumn {
+	columns := make([]sortColumn, 0, len(keys)+1)
+	for _, key := range keys {
+		if column, ok := f.Columns[key.Field]; ok {
+			columns = append(columns, sortColumn{column, strings.EqualFold(key.Order, SORT_DESC)})
+		}
+	}
+	if len(columns) == 0 && len(f.Default) > 0 {
+		return f.columns(f.Default)
+	}
+
+	return append(columns, sortColumn{f.Table + ".id", false})
+}
+
+// keysetCondition matches the rows after the row of keyset in the order of columns. The values of the row are read by
+// subqueries, so sorting by expressions works the same as by columns: (a > x) OR (a = x AND b > y) OR ...
+func (f SortFields) keysetCondition(columns []sortColumn, keyset *Keyset) (string, []any) {
+	value := func(column string) string {
+		return "(SELECT " + column + " FROM " + f.Table + " WHERE " + f.Table + ".id = ?)"
+	}
+
+	conditions := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns)*(len(columns)+1)/2)
+	for i, column := range columns {
+		parts := make([]string, 0, i+1)
+		for _, previous := range columns[:i] {
+			parts = append(parts, previous.column+" = "+value(previous.column))
+			args = append(args, keyset.ID)
+		}
+
+		operator := " > "
+		if column.descending != keyset.Backward {
+			operator = " < "
+		}
+		parts = append(parts, column.column+operator+value(column.column))
+		args = append(args, keyset.ID)
+
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
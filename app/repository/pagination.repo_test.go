@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type testRow struct {
+	ID    uuid.UUID
+	Price float64
+}
+
+func (testRow) TableName() string {
+	return "products"
+}
+
+// dryRunDB builds queries without a database, to check the SQL they would run
+func dryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestSortFields_Paginate(t *testing.T) {
+	fields := SortFields{
+		Table: "products",
+		Columns: map[string]string{
+			"price": "products.price",
+			"name":  "products.name",
+		},
+		Default: []OrderBy{{Field: "name", Order: SORT_ASC}},
+	}
+	page, limit := 3, 10
+	id := uuid.New()
+
+	tests := []struct {
+		name   string
+		keys   []OrderBy
+		keyset *Keyset
+		peek   bool
+		want   []string
+		vars   []any
+	}{
+		{
+			name: "default sort",
+			want: []string{"ORDER BY products.name ASC, products.id ASC LIMIT $1 OFFSET $2"},
+		},
+		{
+			// The offset stays at the page size, only the limit grows
+			name: "peek",
+			peek: true,
+			want: []string{"LIMIT $1 OFFSET $2"},
+			vars: []any{limit + 1, (page - 1) * limit},
+		},
+		{
+			name: "several keys",
+			keys: []OrderBy{{Field: "price", Order: "desc"}, {Field: "name"}},
+			want: []string{"ORDER BY products.price DESC, products.name ASC, products.id ASC"},
+		},
+		{
+			name: "unknown field and direction never pasted",
+			keys: []OrderBy{{Field: "id; DROP TABLE products"}, {Field: "price", Order: "desc, (SELECT 1)"}},
+			want: []string{"ORDER BY products.price ASC, products.id ASC"},
+		},
+		{
+			name:   "keyset",
+			keys:   []OrderBy{{Field: "price", Order: "desc"}},
+			keyset: &Keyset{ID: id},
+			want: []string{
+				"WHERE ((products.price < (SELECT products.price FROM products WHERE products.id = $1)) OR " +
+					"(products.price = (SELECT products.price FROM products WHERE products.id = $2) AND products.id > (SELECT products.id FROM products WHERE products.id = $3)))",
+				"ORDER BY products.price DESC, products.id ASC LIMIT $4",
+			},
+		},
+		{
+			name:   "keyset backward",
+			keys:   []OrderBy{{Field: "price", Order: "desc"}},
+			keyset: &Keyset{ID: id, Backward: true},
+			want: []string{
+				"WHERE ((products.price > (SELECT products.price FROM products WHERE products.id = $1)) OR " +
+					"(products.price = (SELECT products.price FROM products WHERE products.id = $2) AND products.id < (SELECT products.id FROM products WHERE products.id = $3)))",
+				"ORDER BY products.price ASC, products.id DESC LIMIT $4",
+			},
+		},
+	}
+
+	db := dryRunDB(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := db.Scopes(fields.Paginate(tt.keys, tt.keyset, &page, &limit, tt.peek)).Find(&[]testRow{}).Statement
+			sql := stmt.SQL.String()
+			for _, want := range tt.want {
+				if !strings.Contains(sql, want) {
+					t.Errorf("SortFields.Paginate() SQL = %v, want it to contain %v", sql, want)
+				}
+			}
+			if tt.keyset != nil && strings.Contains(sql, "OFFSET") {
+				t.Errorf("SortFields.Paginate() SQL = %v, want no offset with a keyset", sql)
+			}
+			if tt.vars != nil && !reflect.DeepEqual(stmt.Vars, tt.vars) {
+				t.Errorf("SortFields.Paginate() vars = %v, want %v", stmt.Vars, tt.vars)
+			}
+		})
+	}
+}
+
+func TestProductSortFields_Paginate(t *testing.T) {
+	page, limit := 1, 10
+	// The low-stock report lists the lowest quantity first
+	keys := []OrderBy{{Field: "quantity", Order: SORT_ASC}}
+	want := "ORDER BY products.quantity ASC, products.id ASC LIMIT $1"
+
+	stmt := dryRunDB(t).Scopes(ProductSortFields.Paginate(keys, nil, &page, &limit, false)).Find(&[]testRow{}).Statement
+	if sql := stmt.SQL.String(); !strings.Contains(sql, want) {
+		t.Errorf("ProductSortFields.Paginate() SQL = %v, want it to contain %v", sql, want)
+	}
+}
+
+func TestSortFields_CheckKeyset(t *testing.T) {
+	fields := SortFields{Table: "products"}
+
+	if err := fields.CheckKeyset(dryRunDB(t), nil); err != nil {
+		t.Errorf("SortFields.CheckKeyset() error = %v, want nil without a keyset", err)
+	}
+	// A dry run finds no rows, like a cursor whose row was deleted
+	if err := fields.CheckKeyset(dryRunDB(t), &Keyset{ID: uuid.New()}); !errors.Is(err, ErrKeysetNotFound) {
+		t.Errorf("SortFields.CheckKeyset() error = %v, want %v", err, ErrKeysetNotFound)
+	}
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresAddressRepository(db *gorm.DB) repository.IAddressRepository {
+	return &addressRepository{
+		db,
+	}
+}
+
+func (r *addressRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Address,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+func (r *addressRepository) Update(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Address,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Save(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Save(&data).Error
+}
+
+func (r *addressRepository) Delete(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Address,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Delete(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Delete(&data).Error
+}
+
+// UnsetDefaultByFilter clears the default flag of every matched address
+func (r *addressRepository) UnsetDefaultByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindAddressByFilter,
+) error {
+	return r.buildFilter(ctx, tx, filter).
+		Model(&entity.Address{}).
+		Where("is_default = ?", true).
+		Updates(map[string]interface{}{
+			"is_default": false,
+			"updated_at": time.Now().Unix(),
+		}).Error
+}
+
+func (r *addressRepository) FindOneByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindAddressByFilter,
+) (*entity.Address, error) {
+	var address entity.Address
+	err := r.buildFilter(ctx, tx, filter).First(&address).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *addressRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindAddressByFilter,
+) ([]entity.Address, error) {
+	var addresses []entity.Address
+	err := r.buildFilter(ctx, tx, filter).
+		Order("is_default DESC").
+		Order("created_at DESC").
+		Find(&addresses).Error
+	return addresses, err
+}
+
+func (r *addressRepository) CountByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindAddressByFilter,
+) (int64, error) {
+	var count int64
+	err := r.buildFilter(ctx, tx, filter).Model(&entity.Address{}).Count(&count).Error
+	return count, err
+}
+
+func (r *addressRepository) DeleteManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindAddressByFilter,
+) error {
+	return r.buildFilter(ctx, tx, filter).Delete(&entity.Address{}).Error
+}
+
+// -------------------------------------------------------------------------------
+func (r *addressRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindAddressByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.ID != nil {
+		query = query.Where("id = ?", filter.ID)
+	}
+
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if filter.IsDefault != nil {
+		query = query.Where("is_default = ?", filter.IsDefault)
+	}
+
+	return query
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresApiKeyRepository(db *gorm.DB) repository.IApiKeyRepository {
+	return &apiKeyRepository{
+		db,
+	}
+}
+
+func (r *apiKeyRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.ApiKey,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+func (r *apiKeyRepository) Update(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.ApiKey,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Save(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Save(&data).Error
+}
+
+// UpdateLastUsedAt only writes last_used_at, it runs on every request authenticated by the key
+func (r *apiKeyRepository) UpdateLastUsedAt(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.ApiKey,
+) error {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	return query.Model(&entity.ApiKey{}).
+		Where("api_keys.id = ?", data.ID).
+		UpdateColumn("last_used_at", data.LastUsedAt).Error
+}
+
+func (r *apiKeyRepository) FindOneByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindApiKeyByFilter,
+) (*entity.ApiKey, error) {
+	var apiKey entity.ApiKey
+	err := r.buildFilter(ctx, tx, filter).First(&apiKey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func (r *apiKeyRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindApiKeyByFilter,
+) ([]entity.ApiKey, error) {
+	var apiKeys []entity.ApiKey
+
+	query := r.buildFilter(ctx, tx, filter)
+	if filter.Page != nil && filter.Limit != nil {
+		offset := (*filter.Page - 1) * *filter.Limit
+		query = query.Offset(offset).Limit(*filter.Limit)
+	}
+
+	err := query.Order("api_keys.created_at DESC").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+func (r *apiKeyRepository) CountByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindApiKeyByFilter,
+) (int64, error) {
+	var count int64
+	err := r.buildFilter(ctx, tx, filter).Model(&entity.ApiKey{}).Count(&count).Error
+	return count, err
+}
+
+// -------------------------------------------------------------------------------
+func (r *apiKeyRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindApiKeyByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.ID != nil {
+		query = query.Where("api_keys.id = ?", filter.ID)
+	}
+
+	if filter.KeyHash != nil {
+		query = query.Where("api_keys.key_hash = ?", *filter.KeyHash)
+	}
+
+	if filter.Active != nil {
+		if *filter.Active {
+			query = query.Where("api_keys.revoked_at IS NULL")
+		} else {
+			query = query.Where("api_keys.revoked_at IS NOT NULL")
+		}
+	}
+
+	return query
+}
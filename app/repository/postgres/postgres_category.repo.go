@@ -50,10 +50,28 @@ func (r *categoryRepository) FindManyByFilter(
 	filter *repository.FindCategoryByFilter,
 ) ([]entity.Category, error) {
 	var category []entity.Category
-	err := r.buildFilter(ctx, tx, filter).Find(&category).Error
+
+	query := r.buildFilter(ctx, tx, filter)
+	if err := repository.CategorySortFields.CheckKeyset(query, filter.Keyset); err != nil {
+		return nil, err
+	}
+
+	err := query.
+		Scopes(repository.CategorySortFields.Paginate(filter.Sort, filter.Keyset, filter.Page, filter.Limit, filter.Peek)).
+		Find(&category).Error
 	return category, err
 }
 
+func (r *categoryRepository) CountByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindCategoryByFilter,
+) (int64, error) {
+	var count int64
+	err := r.buildFilter(ctx, tx, filter).Model(&entity.Category{}).Count(&count).Error
+	return count, err
+}
+
 func (r *categoryRepository) GetCategorySummary(
 	ctx context.Context,
 	tx *gorm.DB,
@@ -100,11 +118,6 @@ func (r *categoryRepository) buildFilter(
 		query = query.Select(filter.Fields)
 	}
 
-	if filter.Page != nil && filter.Limit != nil {
-		offset := (*filter.Page - 1) * *filter.Limit
-		query = query.Offset(offset).Limit(*filter.Limit)
-	}
-
 	if filter.Name != nil {
 		query = query.Where("name = ?", *filter.Name)
 	}
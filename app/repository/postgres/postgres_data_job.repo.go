@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type dataJobRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresDataJobRepository(db *gorm.DB) repository.IDataJobRepository {
+	return &dataJobRepository{
+		db,
+	}
+}
+
+func (r *dataJobRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.DataJob,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+func (r *dataJobRepository) Update(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.DataJob,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Save(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Save(&data).Error
+}
+
+func (r *dataJobRepository) FindOneByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindDataJobByFilter,
+) (*entity.DataJob, error) {
+	var job entity.DataJob
+	err := r.buildFilter(ctx, tx, filter).First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindManyByFilter returns the oldest jobs first, the order they were requested in
+func (r *dataJobRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindDataJobByFilter,
+) ([]entity.DataJob, error) {
+	var jobs []entity.DataJob
+	err := r.buildFilter(ctx, tx, filter).Order("user_data_jobs.created_at ASC").Find(&jobs).Error
+	return jobs, err
+}
+
+// -------------------------------------------------------------------------------
+func (r *dataJobRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindDataJobByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.ID != nil {
+		query = query.Where("user_data_jobs.id = ?", filter.ID)
+	}
+
+	if filter.UserID != nil {
+		query = query.Where("user_data_jobs.user_id = ?", filter.UserID)
+	}
+
+	if filter.Type != nil {
+		query = query.Where("user_data_jobs.type = ?", *filter.Type)
+	}
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("user_data_jobs.status IN ?", filter.Statuses)
+	}
+
+	return query
+}
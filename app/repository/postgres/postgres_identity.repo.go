@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type identityRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresIdentityRepository(db *gorm.DB) repository.IIdentityRepository {
+	return &identityRepository{
+		db,
+	}
+}
+
+func (r *identityRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Identity,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+func (r *identityRepository) Update(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Identity,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Save(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Save(&data).Error
+}
+
+func (r *identityRepository) FindOneByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindIdentityByFilter,
+) (*entity.Identity, error) {
+	var identity entity.Identity
+	err := r.buildFilter(ctx, tx, filter).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *identityRepository) DeleteManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindIdentityByFilter,
+) error {
+	return r.buildFilter(ctx, tx, filter).Delete(&entity.Identity{}).Error
+}
+
+// -------------------------------------------------------------------------------
+func (r *identityRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindIdentityByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.ID != nil {
+		query = query.Where("user_identities.id = ?", filter.ID)
+	}
+
+	if filter.UserID != nil {
+		query = query.Where("user_identities.user_id = ?", filter.UserID)
+	}
+
+	if filter.Provider != nil {
+		query = query.Where("user_identities.provider = ?", *filter.Provider)
+	}
+
+	if filter.Subject != nil {
+		query = query.Where("user_identities.subject = ?", *filter.Subject)
+	}
+
+	return query
+}
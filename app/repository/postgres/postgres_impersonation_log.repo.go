@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type impersonationLogRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresImpersonationLogRepository(db *gorm.DB) repository.IImpersonationLogRepository {
+	return &impersonationLogRepository{
+		db,
+	}
+}
+
+func (r *impersonationLogRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.ImpersonationLog,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+func (r *impersonationLogRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindImpersonationLogByFilter,
+) ([]entity.ImpersonationLog, error) {
+	var logs []entity.ImpersonationLog
+
+	query := r.buildFilter(ctx, tx, filter)
+	if filter.Page != nil && filter.Limit != nil {
+		offset := (*filter.Page - 1) * *filter.Limit
+		query = query.Offset(offset).Limit(*filter.Limit)
+	}
+
+	err := query.Order("impersonation_logs.created_at DESC").Find(&logs).Error
+	return logs, err
+}
+
+func (r *impersonationLogRepository) CountByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindImpersonationLogByFilter,
+) (int64, error) {
+	var count int64
+	err := r.buildFilter(ctx, tx, filter).Model(&entity.ImpersonationLog{}).Count(&count).Error
+	return count, err
+}
+
+// -------------------------------------------------------------------------------
+func (r *impersonationLogRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindImpersonationLogByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.ImpersonatorID != nil {
+		query = query.Where("impersonation_logs.impersonator_id = ?", filter.ImpersonatorID)
+	}
+
+	if filter.UserID != nil {
+		query = query.Where("impersonation_logs.user_id = ?", filter.UserID)
+	}
+
+	return query
+}
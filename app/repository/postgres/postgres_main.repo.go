@@ -8,10 +8,22 @@ import (
 
 func RegisterPostgresRepositories(db *gorm.DB) repository.RepositoryCollections {
 	return repository.RepositoryCollections{
-		ProductRepo:  NewPostgresProductRepository(db),
-		CategoryRepo: NewPostgresCategoryRepository(db),
-		UserRepo:     NewPostgresUserRepository(db),
-		ReviewRepo:   NewPostgresReviewRepository(db),
-		WishlistRepo: NewPostgresWishlistRepository(db),
+		ProductRepo:          NewPostgresProductRepository(db),
+		ProductVariantRepo:   NewPostgresProductVariantRepository(db),
+		StockMovementRepo:    NewPostgresStockMovementRepository(db),
+		CategoryRepo:         NewPostgresCategoryRepository(db),
+		UserRepo:             NewPostgresUserRepository(db),
+		ReviewRepo:           NewPostgresReviewRepository(db),
+		WishlistRepo:         NewPostgresWishlistRepository(db),
+		RoleRepo:             NewPostgresRoleRepository(db),
+		PermissionRepo:       NewPostgresPermissionRepository(db),
+		SessionRepo:          NewPostgresSessionRepository(db),
+		PasswordHistoryRepo:  NewPostgresPasswordHistoryRepository(db),
+		ApiKeyRepo:           NewPostgresApiKeyRepository(db),
+		IdentityRepo:         NewPostgresIdentityRepository(db),
+		ImpersonationLogRepo: NewPostgresImpersonationLogRepository(db),
+		DataJobRepo:          NewPostgresDataJobRepository(db),
+		AddressRepo:          NewPostgresAddressRepository(db),
+		PreferenceRepo:       NewPostgresPreferenceRepository(db),
 	}
 }
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresPasswordHistoryRepository(db *gorm.DB) repository.IPasswordHistoryRepository {
+	return &passwordHistoryRepository{
+		db,
+	}
+}
+
+func (r *passwordHistoryRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.PasswordHistory,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+// FindManyByFilter returns the newest passwords first
+func (r *passwordHistoryRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindPasswordHistoryByFilter,
+) ([]entity.PasswordHistory, error) {
+	var histories []entity.PasswordHistory
+
+	query := r.buildFilter(ctx, tx, filter)
+	if filter.Limit != nil {
+		query = query.Limit(*filter.Limit)
+	}
+
+	err := query.Order("password_histories.created_at DESC").Find(&histories).Error
+	return histories, err
+}
+
+func (r *passwordHistoryRepository) DeleteManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindPasswordHistoryByFilter,
+) error {
+	return r.buildFilter(ctx, tx, filter).Delete(&entity.PasswordHistory{}).Error
+}
+
+// -------------------------------------------------------------------------------
+func (r *passwordHistoryRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindPasswordHistoryByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.UserID != nil {
+		query = query.Where("password_histories.user_id = ?", filter.UserID)
+	}
+
+	return query
+}
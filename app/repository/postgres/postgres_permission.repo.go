@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type permissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresPermissionRepository(db *gorm.DB) repository.IPermissionRepository {
+	return &permissionRepository{
+		db,
+	}
+}
+
+func (r *permissionRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindPermissionByFilter,
+) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	err := r.buildFilter(ctx, tx, filter).Find(&permissions).Error
+	return permissions, err
+}
+
+// -------------------------------------------------------------------------------
+func (r *permissionRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindPermissionByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if len(filter.Codes) > 0 {
+		query = query.Where("permissions.code IN ?", filter.Codes)
+	}
+
+	if filter.UserID != nil || filter.UserRole != nil {
+		userID := uuid.Nil
+		if filter.UserID != nil {
+			userID = *filter.UserID
+		}
+		userRole := ""
+		if filter.UserRole != nil {
+			userRole = *filter.UserRole
+		}
+
+		query = query.Where(
+			`permissions.id IN (
+				SELECT role_permissions.permission_id FROM role_permissions
+				JOIN roles ON roles.id = role_permissions.role_id
+				WHERE roles.name = ? OR roles.id IN (SELECT user_roles.role_id FROM user_roles WHERE user_roles.user_id = ?)
+			)`,
+			userRole,
+			userID,
+		)
+	}
+
+	query = query.Order("permissions.code ASC")
+
+	return query
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type preferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresPreferenceRepository(db *gorm.DB) repository.IPreferenceRepository {
+	return &preferenceRepository{
+		db,
+	}
+}
+
+// Save inserts the preferences of the user the first time and updates them afterwards
+func (r *preferenceRepository) Save(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Preference,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Save(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Save(&data).Error
+}
+
+func (r *preferenceRepository) FindOneByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindPreferenceByFilter,
+) (*entity.Preference, error) {
+	var preference entity.Preference
+	err := r.buildFilter(ctx, tx, filter).First(&preference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func (r *preferenceRepository) DeleteManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindPreferenceByFilter,
+) error {
+	return r.buildFilter(ctx, tx, filter).Delete(&entity.Preference{}).Error
+}
+
+// -------------------------------------------------------------------------------
+func (r *preferenceRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindPreferenceByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	return query
+}
@@ -76,12 +76,13 @@ func (r *productRepository) FindManyByFilter(
 	var products []entity.Product
 
 	query := r.buildFilter(ctx, tx, filter)
-	if filter.Page != nil && filter.Limit != nil {
-		offset := (*filter.Page - 1) * *filter.Limit
-		query = query.Offset(offset).Limit(*filter.Limit)
+	if err := repository.ProductSortFields.CheckKeyset(query, filter.Keyset); err != nil {
+		return nil, err
 	}
 
-	err := query.Find(&products).Error
+	err := query.
+		Scopes(repository.ProductSortFields.Paginate(filter.Sort, filter.Keyset, filter.Page, filter.Limit, filter.Peek)).
+		Find(&products).Error
 	return products, err
 }
 
@@ -95,6 +96,13 @@ func (r *productRepository) CountByFilter(
 	return count, err
 }
 
+func (r *productRepository) Transaction(
+	ctx context.Context,
+	fn func(tx *gorm.DB) error,
+) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
 // -------------------------------------------------------------------------------
 func (r *productRepository) buildFilter(
 	ctx context.Context,
@@ -124,12 +132,20 @@ func (r *productRepository) buildFilter(
 		query = query.Where("category_id IN ?", filter.CategoryIDs)
 	}
 
+	// The status isn't stored, it's derived from the quantity the same way as entity.Product.SetStatus
 	if filter.Status != nil {
-		query = query.Where("status = ?", filter.Status)
+		switch *filter.Status {
+		case entity.PRODUCT_STATUS_IN_STOCK:
+			query = query.Where("quantity > low_stock_threshold")
+		case entity.PRODUCT_STATUS_LOW_STOCK:
+			query = query.Where("quantity > 0 AND quantity <= low_stock_threshold")
+		case entity.PRODUCT_STATUS_OUT_OF_STOCK:
+			query = query.Where("quantity = 0")
+		}
 	}
 
-	if filter.Order.Field != "" {
-		query = query.Order(filter.Order.Field + " " + filter.Order.Order)
+	if filter.BelowLimit {
+		query = query.Where("quantity <= low_stock_threshold")
 	}
 
 	if len(filter.CategoryFields) > 0 {
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type productVariantRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresProductVariantRepository(db *gorm.DB) repository.IProductVariantRepository {
+	return &productVariantRepository{
+		db,
+	}
+}
+
+func (r *productVariantRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.ProductVariant,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+func (r *productVariantRepository) Update(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.ProductVariant,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Save(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Save(&data).Error
+}
+
+func (r *productVariantRepository) Delete(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.ProductVariant,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Delete(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Delete(&data).Error
+}
+
+func (r *productVariantRepository) FindOneByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindProductVariantByFilter,
+) (*entity.ProductVariant, error) {
+	var variant entity.ProductVariant
+	err := r.buildFilter(ctx, tx, filter).First(&variant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (r *productVariantRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindProductVariantByFilter,
+) ([]entity.ProductVariant, error) {
+	var variants []entity.ProductVariant
+	err := r.buildFilter(ctx, tx, filter).Order("created_at ASC").Find(&variants).Error
+	return variants, err
+}
+
+// GetVariantSummaries sums up the variants of each product, products without variants are left out.
+// A variant without a price of its own costs the price of the product.
+func (r *productVariantRepository) GetVariantSummaries(
+	ctx context.Context,
+	tx *gorm.DB,
+	productIDs []uuid.UUID,
+) ([]entity.ProductVariantSummary, error) {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+	query = query.Model(&entity.ProductVariant{}).
+		Select("product_variants.product_id, COUNT(product_variants.id), "+
+			"MIN(COALESCE(product_variants.price, products.price)), MAX(COALESCE(product_variants.price, products.price)), "+
+			"BOOL_OR(product_variants.quantity > 0)").
+		Joins("JOIN products ON products.id = product_variants.product_id").
+		Where("product_variants.product_id IN ?", productIDs).
+		Group("product_variants.product_id")
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := make([]entity.ProductVariantSummary, 0)
+	for rows.Next() {
+		var summary entity.ProductVariantSummary
+		err := rows.Scan(&summary.ProductID, &summary.VariantCount, &summary.MinPrice, &summary.MaxPrice, &summary.InStock)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// -------------------------------------------------------------------------------
+func (r *productVariantRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindProductVariantByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.ID != nil {
+		query = query.Where("id = ?", filter.ID)
+	}
+
+	if filter.ProductID != nil {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+
+	if filter.Sku != nil {
+		query = query.Where("sku = ?", filter.Sku)
+	}
+
+	return query
+}
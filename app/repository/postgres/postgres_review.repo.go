@@ -51,12 +51,13 @@ func (r *reviewRepository) FindManyByFilter(
 	var reviews []entity.Review
 
 	query := r.buildFilter(ctx, tx, filter)
-	if filter.Page != nil && filter.Limit != nil {
-		offset := (*filter.Page - 1) * *filter.Limit
-		query = query.Offset(offset).Limit(*filter.Limit)
+	if err := repository.ReviewSortFields.CheckKeyset(query, filter.Keyset); err != nil {
+		return nil, err
 	}
 
-	err := query.Find(&reviews).Error
+	err := query.
+		Scopes(repository.ReviewSortFields.Paginate(filter.Sort, filter.Keyset, filter.Page, filter.Limit, filter.Peek)).
+		Find(&reviews).Error
 	return reviews, err
 }
 
@@ -116,7 +117,5 @@ func (r *reviewRepository) buildFilter(
 		query = query.Joins("Product").Where("products.name ILIKE ?", *filter.ProductName)
 	}
 
-	query = query.Order("reviews.created_at DESC")
-
 	return query
 }
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresRoleRepository(db *gorm.DB) repository.IRoleRepository {
+	return &roleRepository{
+		db,
+	}
+}
+
+func (r *roleRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Role,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+func (r *roleRepository) Update(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Role,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Omit("Permissions").Save(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Omit("Permissions").Save(&data).Error
+}
+
+func (r *roleRepository) Delete(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Role,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Select("Permissions").Delete(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Select("Permissions").Delete(&data).Error
+}
+
+func (r *roleRepository) FindOneByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindRoleByFilter,
+) (*entity.Role, error) {
+	var role entity.Role
+	err := r.buildFilter(ctx, tx, filter).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindRoleByFilter,
+) ([]entity.Role, error) {
+	var roles []entity.Role
+
+	query := r.buildFilter(ctx, tx, filter)
+	if filter.Page != nil && filter.Limit != nil {
+		offset := (*filter.Page - 1) * *filter.Limit
+		query = query.Offset(offset).Limit(*filter.Limit)
+	}
+
+	err := query.Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) CountByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindRoleByFilter,
+) (int64, error) {
+	var count int64
+	err := r.buildFilter(ctx, tx, filter).Model(&entity.Role{}).Count(&count).Error
+	return count, err
+}
+
+func (r *roleRepository) ReplacePermissions(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Role,
+	permissions []entity.Permission,
+) error {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	return query.Model(data).Association("Permissions").Replace(permissions)
+}
+
+func (r *roleRepository) AssignToUser(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.UserRole,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+func (r *roleRepository) UnassignFromUser(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.UserRole,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Delete(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Delete(&data).Error
+}
+
+// -------------------------------------------------------------------------------
+// Transaction runs fn in a database transaction, rolled back when fn returns an error
+func (r *roleRepository) Transaction(
+	ctx context.Context,
+	fn func(tx *gorm.DB) error,
+) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *roleRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindRoleByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.ID != nil {
+		query = query.Where("roles.id = ?", filter.ID)
+	}
+
+	if filter.Name != nil {
+		query = query.Where("roles.name = ?", *filter.Name)
+	}
+
+	if filter.UserID != nil {
+		query = query.Where("roles.id IN (SELECT user_roles.role_id FROM user_roles WHERE user_roles.user_id = ?)", filter.UserID)
+	}
+
+	if len(filter.PermissionFields) > 0 {
+		query = query.Model(&entity.Role{}).Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+			return db.Select(filter.PermissionFields)
+		})
+	}
+
+	query = query.Order("roles.name ASC")
+
+	return query
+}
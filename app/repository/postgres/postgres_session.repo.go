@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresSessionRepository(db *gorm.DB) repository.ISessionRepository {
+	return &sessionRepository{
+		db,
+	}
+}
+
+func (r *sessionRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Session,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Create(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Create(&data).Error
+}
+
+func (r *sessionRepository) Update(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.Session,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Save(&data).Error
+	}
+
+	return r.db.WithContext(ctx).Save(&data).Error
+}
+
+func (r *sessionRepository) RevokeManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindSessionByFilter,
+) error {
+	return r.buildFilter(ctx, tx, filter).
+		Model(&entity.Session{}).
+		Where("user_sessions.revoked_at IS NULL").
+		Updates(map[string]interface{}{
+			"revoked_at": time.Now().Unix(),
+			"updated_at": time.Now().Unix(),
+		}).Error
+}
+
+func (r *sessionRepository) FindOneByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindSessionByFilter,
+) (*entity.Session, error) {
+	var session entity.Session
+	err := r.buildFilter(ctx, tx, filter).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindSessionByFilter,
+) ([]entity.Session, error) {
+	var sessions []entity.Session
+
+	query := r.buildFilter(ctx, tx, filter)
+	if filter.Page != nil && filter.Limit != nil {
+		offset := (*filter.Page - 1) * *filter.Limit
+		query = query.Offset(offset).Limit(*filter.Limit)
+	}
+
+	err := query.Order("user_sessions.last_seen_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// -------------------------------------------------------------------------------
+func (r *sessionRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindSessionByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx.WithContext(ctx)
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.ID != nil {
+		query = query.Where("user_sessions.id = ?", filter.ID)
+	}
+
+	if filter.UserID != nil {
+		query = query.Where("user_sessions.user_id = ?", filter.UserID)
+	}
+
+	if filter.Active != nil {
+		if *filter.Active {
+			query = query.Where("user_sessions.revoked_at IS NULL")
+		} else {
+			query = query.Where("user_sessions.revoked_at IS NOT NULL")
+		}
+	}
+
+	return query
+}
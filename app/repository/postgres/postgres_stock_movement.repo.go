@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/repository"
+)
+
+type stockMovementRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresStockMovementRepository(db *gorm.DB) repository.IStockMovementRepository {
+	return &stockMovementRepository{
+		db,
+	}
+}
+
+type stockBalance struct {
+	Quantity         uint64
+	ReservedQuantity uint64
+}
+
+// Record applies the movement to the balance of the product, and of the variant if any, then adds it to the ledger.
+// Both happen in one transaction and balances are changed in place, so concurrent movements never lose an update.
+// repository.ErrInsufficientStock is returned when a quantity would become negative.
+func (r *stockMovementRepository) Record(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.StockMovement,
+) error {
+	record := func(tx *gorm.DB) error {
+		available, reserved := data.Changes()
+
+		balance, err := r.applyChanges(tx, "products", "id = ?", []any{data.ProductID}, available, reserved)
+		if err != nil {
+			return err
+		}
+		if data.VariantID != nil {
+			balance, err = r.applyChanges(tx, "product_variants", "id = ? AND product_id = ?", []any{data.VariantID, data.ProductID}, available, reserved)
+			if err != nil {
+				return err
+			}
+		}
+
+		data.BalanceAfter = balance.Quantity
+		data.ReservedAfter = balance.ReservedQuantity
+		return tx.Create(&data).Error
+	}
+
+	if tx != nil {
+		return record(tx.WithContext(ctx))
+	}
+
+	return r.db.WithContext(ctx).Transaction(record)
+}
+
+func (r *stockMovementRepository) FindManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindStockMovementByFilter,
+) ([]entity.StockMovement, error) {
+	var movements []entity.StockMovement
+
+	query := r.buildFilter(ctx, tx, filter).Order("created_at DESC")
+	if filter.Page != nil && filter.Limit != nil {
+		offset := (*filter.Page - 1) * *filter.Limit
+		query = query.Offset(offset).Limit(*filter.Limit)
+	}
+
+	err := query.Find(&movements).Error
+	return movements, err
+}
+
+func (r *stockMovementRepository) CountByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindStockMovementByFilter,
+) (int64, error) {
+	var count int64
+	err := r.buildFilter(ctx, tx, filter).Model(&entity.StockMovement{}).Count(&count).Error
+	return count, err
+}
+
+// -------------------------------------------------------------------------------
+// applyChanges moves the quantities of one row of table, the row is left as is when a quantity would become negative
+func (r *stockMovementRepository) applyChanges(
+	tx *gorm.DB,
+	table string,
+	where string,
+	args []any,
+	available int64,
+	reserved int64,
+) (*stockBalance, error) {
+	var balance stockBalance
+	values := append([]any{available, reserved, time.Now().Unix()}, args...)
+	values = append(values, available, reserved)
+
+	result := tx.Raw(
+		"UPDATE "+table+" SET quantity = quantity + ?, reserved_quantity = reserved_quantity + ?, updated_at = ? "+
+			"WHERE "+where+" AND quantity + ? >= 0 AND reserved_quantity + ? >= 0 "+
+			"RETURNING quantity, reserved_quantity",
+		values...,
+	).Scan(&balance)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, repository.ErrInsufficientStock
+	}
+
+	return &balance, nil
+}
+
+func (r *stockMovementRepository) buildFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindStockMovementByFilter,
+) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if tx != nil {
+		query = tx
+	}
+
+	if len(filter.OmitFields) > 0 {
+		query = query.Omit(filter.OmitFields...)
+	} else {
+		query = query.Select(filter.Fields)
+	}
+
+	if filter.ProductID != nil {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+
+	if filter.VariantID != nil {
+		query = query.Where("variant_id = ?", filter.VariantID)
+	}
+
+	if len(filter.Types) > 0 {
+		query = query.Where("type IN ?", filter.Types)
+	}
+
+	return query
+}
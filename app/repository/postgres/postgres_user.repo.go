@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -43,6 +44,34 @@ func (r *userRepository) Update(
 	return r.db.WithContext(ctx).Save(&data).Error
 }
 
+// UpdatePassword only writes the password, so users loaded with a subset of fields can be updated
+func (r *userRepository) UpdatePassword(
+	ctx context.Context,
+	tx *gorm.DB,
+	data *entity.User,
+) error {
+	if tx != nil {
+		return tx.WithContext(ctx).Model(data).Select("password", "updated_at").Updates(data).Error
+	}
+
+	return r.db.WithContext(ctx).Model(data).Select("password", "updated_at").Updates(data).Error
+}
+
+// UpdateRoleByFilter sets the role of every matched user at once
+func (r *userRepository) UpdateRoleByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindUserByFilter,
+	role string,
+) error {
+	return r.buildFilter(ctx, tx, filter).
+		Model(&entity.User{}).
+		Updates(map[string]interface{}{
+			"role":       role,
+			"updated_at": time.Now().Unix(),
+		}).Error
+}
+
 func (r *userRepository) FindOneByFilter(
 	ctx context.Context,
 	tx *gorm.DB,
@@ -64,12 +93,13 @@ func (r *userRepository) FindManyByFilter(
 	var users []entity.User
 
 	query := r.buildFilter(ctx, tx, filter)
-	if filter.Page != nil && filter.Limit != nil {
-		offset := (*filter.Page - 1) * *filter.Limit
-		query = query.Offset(offset).Limit(*filter.Limit)
+	if err := repository.UserSortFields.CheckKeyset(query, filter.Keyset); err != nil {
+		return nil, err
 	}
 
-	err := query.Find(&users).Error
+	err := query.
+		Scopes(repository.UserSortFields.Paginate(filter.Sort, filter.Keyset, filter.Page, filter.Limit, filter.Peek)).
+		Find(&users).Error
 	return users, err
 }
 
@@ -104,6 +134,10 @@ func (r *userRepository) buildFilter(
 		query = query.Where("id = ?", filter.ID)
 	}
 
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+
 	if filter.Name != nil {
 		query = query.Where("username ILIKE ? OR email ILIKE ?", "%"+*filter.Name+"%", "%"+*filter.Name+"%")
 	}
@@ -112,9 +146,36 @@ func (r *userRepository) buildFilter(
 		query = query.Where("role = ?", filter.Role)
 	}
 
+	if filter.RoleID != nil {
+		query = query.Where(
+			"role = (SELECT roles.name FROM roles WHERE roles.id = ?) OR id IN (SELECT user_roles.user_id FROM user_roles WHERE user_roles.role_id = ?)",
+			*filter.RoleID, *filter.RoleID,
+		)
+	}
+
 	if filter.Username != nil {
 		query = query.Where("username = ?", *filter.Username)
 	}
 
+	if filter.Email != nil {
+		query = query.Where("email = ?", *filter.Email)
+	}
+
+	if filter.Phone != nil {
+		query = query.Where("phone_number = ?", *filter.Phone)
+	}
+
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at <= ?", *filter.CreatedTo)
+	}
+
 	return query
 }
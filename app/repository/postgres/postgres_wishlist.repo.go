@@ -51,12 +51,13 @@ func (r *wishlistRepository) FindManyByFilter(
 	var wishlists []entity.Wishlist
 
 	query := r.buildFilter(ctx, tx, filter)
-	if filter.Page != nil && filter.Limit != nil {
-		offset := (*filter.Page - 1) * *filter.Limit
-		query = query.Offset(offset).Limit(*filter.Limit)
+	if err := repository.WishlistSortFields.CheckKeyset(query, filter.Keyset); err != nil {
+		return nil, err
 	}
 
-	err := query.Find(&wishlists).Error
+	err := query.
+		Scopes(repository.WishlistSortFields.Paginate(filter.Sort, filter.Keyset, filter.Page, filter.Limit, filter.Peek)).
+		Find(&wishlists).Error
 	return wishlists, err
 }
 
@@ -80,6 +81,14 @@ func (r *wishlistRepository) FindOneByFilter(
 	return &wishlist, err
 }
 
+func (r *wishlistRepository) DeleteManyByFilter(
+	ctx context.Context,
+	tx *gorm.DB,
+	filter *repository.FindWishlistByFilter,
+) error {
+	return r.buildFilter(ctx, tx, filter).Delete(&entity.Wishlist{}).Error
+}
+
 // -------------------------------------------------------------------------------
 func (r *wishlistRepository) buildFilter(
 	ctx context.Context,
@@ -117,7 +126,5 @@ func (r *wishlistRepository) buildFilter(
 		query = query.Where("product_id = ?", filter.ProductID)
 	}
 
-	query = query.Order("created_at DESC")
-
 	return query
 }
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type apiKeyService struct {
+	postgresRepo repository.RepositoryCollections
+	helper       helper.HelperCollections
+}
+
+func NewApiKeyService(
+	postgresRepo repository.RepositoryCollections,
+	helper helper.HelperCollections,
+) IApiKeyService {
+	return &apiKeyService{
+		postgresRepo: postgresRepo,
+		helper:       helper,
+	}
+}
+
+func (s *apiKeyService) Create(
+	ctx context.Context,
+	req *model.CreateApiKeyRequest,
+) (*model.CreateApiKeyResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	if req.ExpiresAt != nil && *req.ExpiresAt <= time.Now().Unix() {
+		return nil, errors.New(errors.ErrCodeApiKeyExpiryInvalid)
+	}
+
+	// Check permissions
+	codes := utils.Unique(req.Permissions)
+	permissions, err := s.postgresRepo.PermissionRepo.FindManyByFilter(ctx, nil, &repository.FindPermissionByFilter{
+		Codes: codes,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if len(permissions) != len(codes) {
+		return nil, errors.New(errors.ErrCodePermissionNotFound)
+	}
+
+	// A key never gets more than its creator can do
+	granted, err := s.helper.UserHelper.GetPermissions(ctx, user)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	for _, code := range codes {
+		if !slices.Contains(granted, code) {
+			return nil, errors.Newf(errors.ErrCodeApiKeyPermissionNotHeld, code)
+		}
+	}
+
+	key, err := s.helper.ApiKeyHelper.GenerateApiKey()
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	apiKey := entity.NewApiKey()
+	apiKey.Name = req.Name
+	apiKey.Permissions = make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		apiKey.Permissions = append(apiKey.Permissions, permission.Code)
+	}
+	apiKey.ExpiresAt = req.ExpiresAt
+	apiKey.CreatedBy = &user.ID
+	apiKey.SetKey(key)
+	if err := s.postgresRepo.ApiKeyRepo.Create(ctx, nil, apiKey); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.CreateApiKeyResponse{
+		ApiKey: *apiKey,
+		Key:    key,
+	}, nil
+}
+
+func (s *apiKeyService) GetApiKeys(
+	ctx context.Context,
+	req *model.GetApiKeysRequest,
+) (*model.GetApiKeysResponse, error) {
+	filter := &repository.FindApiKeyByFilter{
+		Active: req.Active,
+		Page:   req.Page,
+		Limit:  req.Limit,
+	}
+
+	errGroup, errCtx := errgroup.WithContext(ctx)
+
+	var apiKeys []entity.ApiKey
+	errGroup.Go(func() error {
+		var err error
+		apiKeys, err = s.postgresRepo.ApiKeyRepo.FindManyByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	var count int64
+	errGroup.Go(func() error {
+		var err error
+		count, err = s.postgresRepo.ApiKeyRepo.CountByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if err := errGroup.Wait(); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.GetApiKeysResponse{
+		ApiKeys: apiKeys,
+		Count:   count,
+	}, nil
+}
+
+func (s *apiKeyService) Revoke(
+	ctx context.Context,
+	req *model.RevokeApiKeyRequest,
+) (*model.RevokeApiKeyResponse, error) {
+	apiKey, err := s.postgresRepo.ApiKeyRepo.FindOneByFilter(ctx, nil, &repository.FindApiKeyByFilter{
+		ID: &req.ID,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeApiKeyNotFound)
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if apiKey.IsRevoked() {
+		return &model.RevokeApiKeyResponse{}, nil
+	}
+
+	now := time.Now().Unix()
+	apiKey.RevokedAt = &now
+	if err := s.postgresRepo.ApiKeyRepo.Update(ctx, nil, apiKey); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.RevokeApiKeyResponse{}, nil
+}
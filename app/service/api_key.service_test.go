@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	helper_mocks "sondth-test_soa/mocks/helper"
+	repo_mocks "sondth-test_soa/mocks/repository"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var (
+	testApiKeyID = uuid.New()
+	testApiKey   = "sk_0123456789abcdefghijklmnopqrstuvwxyzABCDE"
+)
+
+func Test_apiKeyService_Create(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.CreateApiKeyRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(apiKeyRepo *repo_mocks.IApiKeyRepository, permissionRepo *repo_mocks.IPermissionRepository, apiKeyHelper *helper_mocks.IApiKeyHelper, userHelper *helper_mocks.IUserHelper)
+	}
+
+	adminID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: adminID,
+	})
+	expiredAt := time.Now().Add(-time.Hour).Unix()
+	permissions := []entity.Permission{
+		{ID: uuid.New(), Code: entity.PERMISSION_PRODUCT_WRITE},
+	}
+
+	tests := []testCase{
+		{
+			name: "Create Api Key Success",
+			args: args{
+				ctx: ctx,
+				req: &model.CreateApiKeyRequest{
+					Name:        "import-job",
+					Permissions: []string{entity.PERMISSION_PRODUCT_WRITE},
+				},
+			},
+			wantErr: false,
+			mock: func(apiKeyRepo *repo_mocks.IApiKeyRepository, permissionRepo *repo_mocks.IPermissionRepository, apiKeyHelper *helper_mocks.IApiKeyHelper, userHelper *helper_mocks.IUserHelper) {
+				// Mock permission lookup
+				permissionRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindPermissionByFilter) bool {
+					return len(filter.Codes) == 1 && filter.Codes[0] == entity.PERMISSION_PRODUCT_WRITE
+				})).Return(permissions, nil).Once()
+
+				// Mock permissions of the creator
+				userHelper.On("GetPermissions", mock.Anything, mock.Anything).Return([]string{entity.PERMISSION_PRODUCT_WRITE, entity.PERMISSION_API_KEY_MANAGE}, nil).Once()
+
+				// Mock key generation
+				apiKeyHelper.On("GenerateApiKey").Return(testApiKey, nil).Once()
+
+				// Only the hash of the key is stored
+				apiKeyRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(apiKey *entity.ApiKey) bool {
+					return apiKey.KeyHash == utils.HashToken(testApiKey) &&
+						apiKey.Prefix == "sk_01234567" &&
+						*apiKey.CreatedBy == adminID &&
+						len(apiKey.Permissions) == 1
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Create Api Key Failed - Expiry In The Past",
+			args: args{
+				ctx: ctx,
+				req: &model.CreateApiKeyRequest{
+					Name:        "import-job",
+					Permissions: []string{entity.PERMISSION_PRODUCT_WRITE},
+					ExpiresAt:   &expiredAt,
+				},
+			},
+			wantErr: true,
+			mock: func(apiKeyRepo *repo_mocks.IApiKeyRepository, permissionRepo *repo_mocks.IPermissionRepository, apiKeyHelper *helper_mocks.IApiKeyHelper, userHelper *helper_mocks.IUserHelper) {
+			},
+		},
+		{
+			name: "Create Api Key Failed - Unknown Permission",
+			args: args{
+				ctx: ctx,
+				req: &model.CreateApiKeyRequest{
+					Name:        "import-job",
+					Permissions: []string{"unknown:write"},
+				},
+			},
+			wantErr: true,
+			mock: func(apiKeyRepo *repo_mocks.IApiKeyRepository, permissionRepo *repo_mocks.IPermissionRepository, apiKeyHelper *helper_mocks.IApiKeyHelper, userHelper *helper_mocks.IUserHelper) {
+				// Mock permission not found
+				permissionRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Permission{}, nil).Once()
+			},
+		},
+		{
+			name: "Create Api Key Failed - Permission Not Held",
+			args: args{
+				ctx: ctx,
+				req: &model.CreateApiKeyRequest{
+					Name:        "import-job",
+					Permissions: []string{entity.PERMISSION_PRODUCT_WRITE, entity.PERMISSION_ROLE_MANAGE},
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeApiKeyPermissionNotHeld,
+			mock: func(apiKeyRepo *repo_mocks.IApiKeyRepository, permissionRepo *repo_mocks.IPermissionRepository, apiKeyHelper *helper_mocks.IApiKeyHelper, userHelper *helper_mocks.IUserHelper) {
+				permissionRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Permission{
+					{ID: uuid.New(), Code: entity.PERMISSION_PRODUCT_WRITE},
+					{ID: uuid.New(), Code: entity.PERMISSION_ROLE_MANAGE},
+				}, nil).Once()
+
+				// The creator manages API keys and products, not roles
+				userHelper.On("GetPermissions", mock.Anything, mock.Anything).Return([]string{entity.PERMISSION_PRODUCT_WRITE, entity.PERMISSION_API_KEY_MANAGE}, nil).Once()
+			},
+		},
+		{
+			name: "Create Api Key Failed - Unauthorized",
+			args: args{
+				ctx: context.Background(),
+				req: &model.CreateApiKeyRequest{
+					Name:        "import-job",
+					Permissions: []string{entity.PERMISSION_PRODUCT_WRITE},
+				},
+			},
+			wantErr: true,
+			mock: func(apiKeyRepo *repo_mocks.IApiKeyRepository, permissionRepo *repo_mocks.IPermissionRepository, apiKeyHelper *helper_mocks.IApiKeyHelper, userHelper *helper_mocks.IUserHelper) {
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			apiKeyRepo := repo_mocks.NewIApiKeyRepository(t)
+			permissionRepo := repo_mocks.NewIPermissionRepository(t)
+			apiKeyHelper := helper_mocks.NewIApiKeyHelper(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+
+			// Setup mocks
+			tt.mock(apiKeyRepo, permissionRepo, apiKeyHelper, userHelper)
+
+			s := &apiKeyService{
+				postgresRepo: repository.RepositoryCollections{
+					ApiKeyRepo:     apiKeyRepo,
+					PermissionRepo: permissionRepo,
+				},
+				helper: helper.HelperCollections{
+					ApiKeyHelper: apiKeyHelper,
+					UserHelper:   userHelper,
+				},
+			}
+
+			got, err := s.Create(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("apiKeyService.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errCode != 0 && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("apiKeyService.Create() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+			if err == nil && got.Key != testApiKey {
+				t.Errorf("apiKeyService.Create() key = %v, want %v", got.Key, testApiKey)
+			}
+		})
+	}
+}
+
+func Test_apiKeyService_Revoke(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.RevokeApiKeyRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		mock    func(apiKeyRepo *repo_mocks.IApiKeyRepository)
+	}
+
+	ctx := context.Background()
+
+	tests := []testCase{
+		{
+			name: "Revoke Api Key Success",
+			args: args{
+				ctx: ctx,
+				req: &model.RevokeApiKeyRequest{ID: testApiKeyID},
+			},
+			wantErr: false,
+			mock: func(apiKeyRepo *repo_mocks.IApiKeyRepository) {
+				apiKeyRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindApiKeyByFilter) bool {
+					return *filter.ID == testApiKeyID
+				})).Return(&entity.ApiKey{ID: testApiKeyID}, nil).Once()
+				apiKeyRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(apiKey *entity.ApiKey) bool {
+					return apiKey.IsRevoked()
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Revoke Api Key Failed - Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.RevokeApiKeyRequest{ID: testApiKeyID},
+			},
+			wantErr: true,
+			mock: func(apiKeyRepo *repo_mocks.IApiKeyRepository) {
+				apiKeyRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			apiKeyRepo := repo_mocks.NewIApiKeyRepository(t)
+
+			// Setup mocks
+			tt.mock(apiKeyRepo)
+
+			s := &apiKeyService{
+				postgresRepo: repository.RepositoryCollections{
+					ApiKeyRepo: apiKeyRepo,
+				},
+			}
+
+			got, err := s.Revoke(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("apiKeyService.Revoke() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got == nil {
+				t.Error("apiKeyService.Revoke() got nil response, want non-nil")
+			}
+		})
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"sondth-test_soa/app/model"
 	"sondth-test_soa/app/repository"
 	"sondth-test_soa/package/errors"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
 )
 
 type categoryService struct {
@@ -57,29 +60,58 @@ func (s *categoryService) GetCategories(
 	var (
 		defaultPage  = 1
 		defaultLimit = 10
-		filter       = &repository.FindCategoryByFilter{
-			Filter: repository.Filter{
-				Fields: []string{"id", "name", "description"},
-			},
-		}
 	)
-
-	if req.Page != nil && req.Limit != nil {
-		filter.Page = req.Page
-		filter.Limit = req.Limit
-	} else {
-		filter.Page = &defaultPage
-		filter.Limit = &defaultLimit
+	if req.Page == nil || req.Limit == nil {
+		req.Page = &defaultPage
+		req.Limit = &defaultLimit
 	}
 
-	categories, err := s.postgresRepo.CategoryRepo.FindManyByFilter(ctx, nil, filter)
+	page, err := newListPage(req.CursorRequest, req.Sort, repository.CategorySortFields, req.Page, req.Limit)
 	if err != nil {
 		return nil, err
 	}
 
+	filter := &repository.FindCategoryByFilter{
+		Filter: repository.Filter{
+			Fields: []string{"id", "name", "description"},
+		},
+		Page:   page.Page,
+		Limit:  page.Limit,
+		Peek:   page.Peek,
+		Sort:   page.Sort,
+		Keyset: page.Keyset,
+	}
+
+	var (
+		count      *int64
+		categories []entity.Category
+	)
+	errGroup, errCtx := errgroup.WithContext(ctx)
+	if !req.SkipCount {
+		errGroup.Go(func() error {
+			total, err := s.postgresRepo.CategoryRepo.CountByFilter(errCtx, nil, filter)
+			if err != nil {
+				return err
+			}
+			count = &total
+			return nil
+		})
+	}
+	errGroup.Go(func() error {
+		var err error
+		categories, err = s.postgresRepo.CategoryRepo.FindManyByFilter(errCtx, nil, filter)
+		return keysetError(err)
+	})
+	if err := errGroup.Wait(); err != nil {
+		return nil, err
+	}
+
+	categories, cursors := cutPage(page, categories, func(category entity.Category) uuid.UUID { return category.ID })
+
 	return &model.GetCategoriesResponse{
-		Count:  int64(len(categories)),
-		Result: categories,
+		Count:          count,
+		Result:         categories,
+		CursorResponse: cursors,
 	}, nil
 }
 
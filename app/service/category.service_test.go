@@ -172,6 +172,7 @@ func Test_categoryService_GetCategories(t *testing.T) {
 	}
 
 	ctx := context.Background()
+	count := int64(1)
 	tests := []testCase{
 		{
 			name: "Get Categories Success",
@@ -189,7 +190,7 @@ func Test_categoryService_GetCategories(t *testing.T) {
 				},
 			},
 			want: &model.GetCategoriesResponse{
-				Count: 1,
+				Count: &count,
 				Result: []entity.Category{
 					{
 						Name:         "Category 1",
@@ -200,8 +201,10 @@ func Test_categoryService_GetCategories(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func(repo *repo_mocks.ICategoryRepository) {
-				repo.On("FindManyByFilter", ctx, mock.Anything, mock.MatchedBy(func(filter *repository.FindCategoryByFilter) bool {
-					return filter.Page != nil && *filter.Page == testPage && filter.Limit != nil && *filter.Limit == testLimit
+				// The first page is read without offset, one more category tells whether there's a next page
+				repo.On("CountByFilter", mock.Anything, mock.Anything, mock.Anything).Return(count, nil)
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindCategoryByFilter) bool {
+					return filter.Page == nil && filter.Limit != nil && *filter.Limit == testLimit && filter.Peek
 				})).Return([]entity.Category{
 					{
 						Name:         "Category 1",
@@ -224,7 +227,7 @@ func Test_categoryService_GetCategories(t *testing.T) {
 				req: &model.GetCategoriesRequest{},
 			},
 			want: &model.GetCategoriesResponse{
-				Count: 1,
+				Count: &count,
 				Result: []entity.Category{
 					{
 						Name:         "Category 1",
@@ -235,6 +238,7 @@ func Test_categoryService_GetCategories(t *testing.T) {
 			},
 			wantErr: false,
 			mock: func(repo *repo_mocks.ICategoryRepository) {
+				repo.On("CountByFilter", mock.Anything, mock.Anything, mock.Anything).Return(count, nil)
 				repo.On("FindManyByFilter",
 					mock.Anything,
 					mock.Anything,
@@ -262,7 +266,8 @@ func Test_categoryService_GetCategories(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 			mock: func(repo *repo_mocks.ICategoryRepository) {
-				repo.On("FindManyByFilter", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+				repo.On("CountByFilter", mock.Anything, mock.Anything, mock.Anything).Return(count, nil).Maybe()
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
 			},
 		},
 	}
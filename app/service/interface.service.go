@@ -11,6 +11,16 @@ type IProductService interface {
 	Update(ctx context.Context, req *model.UpdateProductRequest) (*model.UpdateProductResponse, error)
 	Delete(ctx context.Context, req *model.DeleteProductRequest) (*model.DeleteProductResponse, error)
 	GetProducts(ctx context.Context, req *model.GetProductRequest) (*model.GetProductResponse, error)
+	CreateVariant(ctx context.Context, req *model.CreateProductVariantRequest) (*model.CreateProductVariantResponse, error)
+	UpdateVariant(ctx context.Context, req *model.UpdateProductVariantRequest) (*model.UpdateProductVariantResponse, error)
+	DeleteVariant(ctx context.Context, req *model.DeleteProductVariantRequest) (*model.DeleteProductVariantResponse, error)
+	GetVariants(ctx context.Context, req *model.GetProductVariantsRequest) (*model.GetProductVariantsResponse, error)
+}
+
+type IInventoryService interface {
+	RecordMovement(ctx context.Context, req *model.RecordStockMovementRequest) (*model.RecordStockMovementResponse, error)
+	GetMovements(ctx context.Context, req *model.GetStockMovementsRequest) (*model.GetStockMovementsResponse, error)
+	GetLowStockReport(ctx context.Context, req *model.GetLowStockReportRequest) (*model.GetLowStockReportResponse, error)
 }
 
 type ICategoryService interface {
@@ -22,10 +32,35 @@ type ICategoryService interface {
 type IUserService interface {
 	Register(ctx context.Context, req *model.UserRegisterRequest) (*model.UserRegisterResponse, error)
 	Login(ctx context.Context, req *model.UserLoginRequest) (*model.UserLoginResponse, error)
+	LoginMfa(ctx context.Context, req *model.LoginMfaRequest) (*model.LoginMfaResponse, error)
+	OidcAuthorize(ctx context.Context, req *model.OidcAuthorizeRequest) (*model.OidcAuthorizeResponse, error)
+	OidcCallback(ctx context.Context, req *model.OidcCallbackRequest) (*model.UserLoginResponse, error)
+	EnrollMfa(ctx context.Context, req *model.EnrollMfaRequest) (*model.EnrollMfaResponse, error)
+	ActivateMfa(ctx context.Context, req *model.ActivateMfaRequest) (*model.ActivateMfaResponse, error)
+	DisableMfa(ctx context.Context, req *model.DisableMfaRequest) (*model.DisableMfaResponse, error)
+	RefreshToken(ctx context.Context, req *model.RefreshTokenRequest) (*model.RefreshTokenResponse, error)
+	Logout(ctx context.Context, req *model.LogoutRequest) (*model.LogoutResponse, error)
+	LogoutAll(ctx context.Context, req *model.LogoutAllRequest) (*model.LogoutAllResponse, error)
 	ForgetPassword(ctx context.Context, req *model.ForgetPasswordRequest) (*model.ForgetPasswordResponse, error)
+	ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) (*model.ResetPasswordResponse, error)
 	ChangePassword(ctx context.Context, req *model.ChangeUserPasswordRequest) (*model.ChangeUserPasswordResponse, error)
 	UpdateUser(ctx context.Context, req *model.UpdateUserRequest) (*model.UpdateUserResponse, error)
+	GetMe(ctx context.Context, req *model.GetMeRequest) (*model.GetMeResponse, error)
+	UpdateProfile(ctx context.Context, req *model.UpdateProfileRequest) (*model.UpdateProfileResponse, error)
+	SendVerificationCode(ctx context.Context, req *model.SendVerificationCodeRequest) (*model.SendVerificationCodeResponse, error)
+	ConfirmVerificationCode(ctx context.Context, req *model.ConfirmVerificationCodeRequest) (*model.ConfirmVerificationCodeResponse, error)
+	GetSessions(ctx context.Context, req *model.GetSessionsRequest) (*model.GetSessionsResponse, error)
+	RevokeSession(ctx context.Context, req *model.RevokeSessionRequest) (*model.RevokeSessionResponse, error)
+	UpdateUserStatus(ctx context.Context, req *model.UpdateUserStatusRequest) (*model.UpdateUserStatusResponse, error)
+	RevokeUserSessions(ctx context.Context, req *model.RevokeUserSessionsRequest) (*model.RevokeUserSessionsResponse, error)
+	UnlockUser(ctx context.Context, req *model.UnlockUserRequest) (*model.UnlockUserResponse, error)
 	GetUsers(ctx context.Context, req *model.GetUsersRequest) (*model.GetUsersResponse, error)
+	CreateUser(ctx context.Context, req *model.CreateUserRequest) (*model.CreateUserResponse, error)
+	InviteUser(ctx context.Context, req *model.InviteUserRequest) (*model.InviteUserResponse, error)
+	ActivateUser(ctx context.Context, req *model.ActivateUserRequest) (*model.ActivateUserResponse, error)
+	BulkUpdateUserRole(ctx context.Context, req *model.BulkUpdateUserRoleRequest) (*model.BulkUpdateUserRoleResponse, error)
+	Impersonate(ctx context.Context, req *model.ImpersonateUserRequest) (*model.ImpersonateUserResponse, error)
+	GetImpersonationLogs(ctx context.Context, req *model.GetImpersonationLogsRequest) (*model.GetImpersonationLogsResponse, error)
 }
 
 type IWishlistService interface {
@@ -41,3 +76,43 @@ type IReviewService interface {
 	Delete(ctx context.Context, req *model.DeleteReviewRequest) (*model.DeleteReviewResponse, error)
 	GetReviewsSummary(ctx context.Context, req *model.GetReviewsSummaryRequest) (*model.GetReviewsSummaryResponse, error)
 }
+
+type IRoleService interface {
+	Create(ctx context.Context, req *model.CreateRoleRequest) (*model.CreateRoleResponse, error)
+	Update(ctx context.Context, req *model.UpdateRoleRequest) (*model.UpdateRoleResponse, error)
+	Delete(ctx context.Context, req *model.DeleteRoleRequest) (*model.DeleteRoleResponse, error)
+	GetRoles(ctx context.Context, req *model.GetRolesRequest) (*model.GetRolesResponse, error)
+	GetPermissions(ctx context.Context, req *model.GetPermissionsRequest) (*model.GetPermissionsResponse, error)
+	AssignRole(ctx context.Context, req *model.AssignRoleRequest) (*model.AssignRoleResponse, error)
+	UnassignRole(ctx context.Context, req *model.UnassignRoleRequest) (*model.UnassignRoleResponse, error)
+}
+
+type IOAuthService interface {
+	GetJWKS(ctx context.Context, req *model.GetJWKSRequest) (*model.GetJWKSResponse, error)
+}
+
+type IApiKeyService interface {
+	Create(ctx context.Context, req *model.CreateApiKeyRequest) (*model.CreateApiKeyResponse, error)
+	GetApiKeys(ctx context.Context, req *model.GetApiKeysRequest) (*model.GetApiKeysResponse, error)
+	Revoke(ctx context.Context, req *model.RevokeApiKeyRequest) (*model.RevokeApiKeyResponse, error)
+}
+
+type IPrivacyService interface {
+	RequestExport(ctx context.Context, req *model.RequestDataExportRequest) (*model.RequestDataExportResponse, error)
+	RequestErasure(ctx context.Context, req *model.RequestDataErasureRequest) (*model.RequestDataErasureResponse, error)
+	GetDataJob(ctx context.Context, req *model.GetDataJobRequest) (*model.GetDataJobResponse, error)
+	DownloadDataExport(ctx context.Context, req *model.DownloadDataExportRequest) (*model.DownloadDataExportResponse, error)
+	ResumeDataJobs(ctx context.Context) error
+}
+
+type IProfileService interface {
+	GetAddresses(ctx context.Context, req *model.GetAddressesRequest) (*model.GetAddressesResponse, error)
+	CreateAddress(ctx context.Context, req *model.CreateAddressRequest) (*model.CreateAddressResponse, error)
+	UpdateAddress(ctx context.Context, req *model.UpdateAddressRequest) (*model.UpdateAddressResponse, error)
+	DeleteAddress(ctx context.Context, req *model.DeleteAddressRequest) (*model.DeleteAddressResponse, error)
+	SetDefaultAddress(ctx context.Context, req *model.SetDefaultAddressRequest) (*model.SetDefaultAddressResponse, error)
+	UploadAvatar(ctx context.Context, req *model.UploadAvatarRequest) (*model.UploadAvatarResponse, error)
+	DeleteAvatar(ctx context.Context, req *model.DeleteAvatarRequest) (*model.DeleteAvatarResponse, error)
+	GetPreferences(ctx context.Context, req *model.GetPreferencesRequest) (*model.GetPreferencesResponse, error)
+	UpdatePreferences(ctx context.Context, req *model.UpdatePreferencesRequest) (*model.UpdatePreferencesResponse, error)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	"sondth-test_soa/package/errors"
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/utils"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type inventoryService struct {
+	postgresRepo repository.RepositoryCollections
+	helper       helper.HelperCollections
+}
+
+func NewInventoryService(
+	postgresRepo repository.RepositoryCollections,
+	helper helper.HelperCollections,
+) IInventoryService {
+	return &inventoryService{
+		postgresRepo: postgresRepo,
+		helper:       helper,
+	}
+}
+
+func (s *inventoryService) RecordMovement(
+	ctx context.Context,
+	req *model.RecordStockMovementRequest,
+) (*model.RecordStockMovementResponse, error) {
+	// Only adjustments can remove stock, the other movements tell the direction by their type
+	if req.Quantity == 0 || (req.Quantity < 0 && req.Type != entity.STOCK_MOVEMENT_ADJUST) {
+		return nil, errors.Newf(errors.ErrCodeStockQuantityInvalid, req.Type)
+	}
+
+	// Check if product exists
+	if _, err := s.helper.ProductHelper.ValidateProductID(ctx, req.ProductID); err != nil {
+		return nil, err
+	}
+
+	// Check if variant belongs to the product
+	if req.VariantID != nil {
+		if _, err := s.postgresRepo.ProductVariantRepo.FindOneByFilter(ctx, nil, &repository.FindProductVariantByFilter{
+			ID:        req.VariantID,
+			ProductID: &req.ProductID,
+		}); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.New(errors.ErrCodeVariantNotFound)
+			}
+			return nil, err
+		}
+	} else {
+		// The stock of a product with variants is the sum of the variants, so it only moves with one of them
+		_, err := s.postgresRepo.ProductVariantRepo.FindOneByFilter(ctx, nil, &repository.FindProductVariantByFilter{
+			Filter: repository.Filter{
+				Fields: []string{"id"},
+			},
+			ProductID: &req.ProductID,
+		})
+		if err == nil {
+			return nil, errors.New(errors.ErrCodeVariantRequired)
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+
+	movement := entity.NewStockMovement(req.ProductID, req.Type, req.Quantity, req.Reason)
+	movement.VariantID = req.VariantID
+	if err := recordStockMovement(ctx, nil, s.postgresRepo, movement); err != nil {
+		return nil, err
+	}
+
+	return &model.RecordStockMovementResponse{
+		Movement: *movement,
+	}, nil
+}
+
+func (s *inventoryService) GetMovements(
+	ctx context.Context,
+	req *model.GetStockMovementsRequest,
+) (*model.GetStockMovementsResponse, error) {
+	// Check if product exists
+	if _, err := s.helper.ProductHelper.ValidateProductID(ctx, req.ProductID); err != nil {
+		return nil, err
+	}
+
+	results := &model.GetStockMovementsResponse{}
+	filter := &repository.FindStockMovementByFilter{
+		ProductID: &req.ProductID,
+		VariantID: req.VariantID,
+		Types:     req.Types,
+		Page:      req.Page,
+		Limit:     req.Limit,
+	}
+
+	errGroup, errCtx := errgroup.WithContext(ctx)
+	errGroup.Go(func() error {
+		count, err := s.postgresRepo.StockMovementRepo.CountByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+
+		results.Count = count
+		return nil
+	})
+	errGroup.Go(func() error {
+		movements, err := s.postgresRepo.StockMovementRepo.FindManyByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+
+		results.Result = movements
+		return nil
+	})
+	if err := errGroup.Wait(); err != nil {
+		logger.WithCtx(ctx).Error("GetMovements", err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetLowStockReport lists the products at or below their low-stock threshold, the lowest quantity first
+func (s *inventoryService) GetLowStockReport(
+	ctx context.Context,
+	req *model.GetLowStockReportRequest,
+) (*model.GetLowStockReportResponse, error) {
+	results := &model.GetLowStockReportResponse{}
+	filter := &repository.FindProductByFilter{
+		Filter: repository.Filter{
+			Fields: []string{"products.id", "products.name", "products.price", "products.quantity", "products.reserved_quantity", "products.low_stock_threshold", "products.category_id"},
+		},
+		CategoryIDs:    req.CategoryIDs,
+		Page:           req.Page,
+		Limit:          req.Limit,
+		BelowLimit:     true,
+		Sort:           []repository.OrderBy{{Field: "quantity", Order: repository.SORT_ASC}},
+		CategoryFields: []string{"categories.id", "categories.name"},
+	}
+
+	errGroup, errCtx := errgroup.WithContext(ctx)
+	errGroup.Go(func() error {
+		count, err := s.postgresRepo.ProductRepo.CountByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+
+		results.Count = count
+		return nil
+	})
+	errGroup.Go(func() error {
+		products, err := s.postgresRepo.ProductRepo.FindManyByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+		for i := range products {
+			products[i].SetStatus()
+		}
+
+		results.Result = products
+		return nil
+	})
+	if err := errGroup.Wait(); err != nil {
+		logger.WithCtx(ctx).Error("GetLowStockReport", err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// -------------------------------------------------------------------------------
+// recordStockMovement posts the movement to the ledger on behalf of the user or the API key of the request
+func recordStockMovement(
+	ctx context.Context,
+	tx *gorm.DB,
+	postgresRepo repository.RepositoryCollections,
+	movement *entity.StockMovement,
+) error {
+	if user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User); ok {
+		movement.ActorType = entity.STOCK_ACTOR_USER
+		movement.ActorID = user.ID
+	} else if principal, ok := ctx.Value(string(utils.PRINCIPAL_CONTEXT_KEY)).(*entity.ServicePrincipal); ok {
+		movement.ActorType = entity.STOCK_ACTOR_API_KEY
+		movement.ActorID = principal.ApiKeyID
+	} else {
+		return errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	if err := postgresRepo.StockMovementRepo.Record(ctx, tx, movement); err != nil {
+		if err == repository.ErrInsufficientStock {
+			return errors.New(errors.ErrCodeInsufficientStock)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	helper_mocks "sondth-test_soa/mocks/helper"
+	repo_mocks "sondth-test_soa/mocks/repository"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_inventoryService_RecordMovement(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.RecordStockMovementRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(stockMovementRepo *repo_mocks.IStockMovementRepository, variantRepo *repo_mocks.IProductVariantRepository, productHelper *helper_mocks.IProductHelper)
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+	principal := &entity.ServicePrincipal{ApiKeyID: testApiKeyID, Name: "warehouse-sync"}
+	apiKeyCtx := context.WithValue(context.Background(), string(utils.PRINCIPAL_CONTEXT_KEY), principal)
+	variantID := uuid.New()
+
+	tests := []testCase{
+		{
+			name: "Receive Success",
+			args: args{
+				ctx: ctx,
+				req: &model.RecordStockMovementRequest{
+					ProductID: testProductID,
+					Type:      entity.STOCK_MOVEMENT_RECEIVE,
+					Quantity:  20,
+					Reason:    "Purchase order 42",
+				},
+			},
+			wantErr: false,
+			mock: func(stockMovementRepo *repo_mocks.IStockMovementRepository, variantRepo *repo_mocks.IProductVariantRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", ctx, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.MatchedBy(func(filter *repository.FindProductVariantByFilter) bool {
+					return filter.ID == nil && *filter.ProductID == testProductID
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+				stockMovementRepo.On("Record", ctx, mock.Anything, mock.MatchedBy(func(movement *entity.StockMovement) bool {
+					return movement.ProductID == testProductID &&
+						movement.Quantity == 20 &&
+						movement.Reason == "Purchase order 42" &&
+						movement.ActorType == entity.STOCK_ACTOR_USER &&
+						movement.ActorID == userID
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Reserve Variant Success - API Key",
+			args: args{
+				ctx: apiKeyCtx,
+				req: &model.RecordStockMovementRequest{
+					ProductID: testProductID,
+					VariantID: &variantID,
+					Type:      entity.STOCK_MOVEMENT_RESERVE,
+					Quantity:  2,
+					Reason:    "Order 1001",
+				},
+			},
+			wantErr: false,
+			mock: func(stockMovementRepo *repo_mocks.IStockMovementRepository, variantRepo *repo_mocks.IProductVariantRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", apiKeyCtx, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", apiKeyCtx, mock.Anything, mock.MatchedBy(func(filter *repository.FindProductVariantByFilter) bool {
+					return *filter.ID == variantID && *filter.ProductID == testProductID
+				})).Return(&entity.ProductVariant{ID: variantID, ProductID: testProductID}, nil).Once()
+				stockMovementRepo.On("Record", apiKeyCtx, mock.Anything, mock.MatchedBy(func(movement *entity.StockMovement) bool {
+					return *movement.VariantID == variantID &&
+						movement.ActorType == entity.STOCK_ACTOR_API_KEY &&
+						movement.ActorID == testApiKeyID
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Negative Quantity Only For Adjustments",
+			args: args{
+				ctx: ctx,
+				req: &model.RecordStockMovementRequest{
+					ProductID: testProductID,
+					Type:      entity.STOCK_MOVEMENT_SELL,
+					Quantity:  -1,
+					Reason:    "Order 1001",
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeStockQuantityInvalid,
+			mock: func(stockMovementRepo *repo_mocks.IStockMovementRepository, variantRepo *repo_mocks.IProductVariantRepository, productHelper *helper_mocks.IProductHelper) {
+			},
+		},
+		{
+			name: "Variant Of Another Product",
+			args: args{
+				ctx: ctx,
+				req: &model.RecordStockMovementRequest{
+					ProductID: testProductID,
+					VariantID: &variantID,
+					Type:      entity.STOCK_MOVEMENT_RECEIVE,
+					Quantity:  1,
+					Reason:    "Return",
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeVariantNotFound,
+			mock: func(stockMovementRepo *repo_mocks.IStockMovementRepository, variantRepo *repo_mocks.IProductVariantRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", ctx, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+		{
+			name: "Variant Required",
+			args: args{
+				ctx: ctx,
+				req: &model.RecordStockMovementRequest{
+					ProductID: testProductID,
+					Type:      entity.STOCK_MOVEMENT_RECEIVE,
+					Quantity:  1,
+					Reason:    "Return",
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeVariantRequired,
+			mock: func(stockMovementRepo *repo_mocks.IStockMovementRepository, variantRepo *repo_mocks.IProductVariantRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", ctx, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.Anything).Return(&entity.ProductVariant{ID: variantID, ProductID: testProductID}, nil).Once()
+			},
+		},
+		{
+			name: "Insufficient Stock",
+			args: args{
+				ctx: ctx,
+				req: &model.RecordStockMovementRequest{
+					ProductID: testProductID,
+					Type:      entity.STOCK_MOVEMENT_ADJUST,
+					Quantity:  -50,
+					Reason:    "Stock count",
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeInsufficientStock,
+			mock: func(stockMovementRepo *repo_mocks.IStockMovementRepository, variantRepo *repo_mocks.IProductVariantRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", ctx, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+				stockMovementRepo.On("Record", ctx, mock.Anything, mock.Anything).Return(repository.ErrInsufficientStock).Once()
+			},
+		},
+		{
+			name: "Unauthorized",
+			args: args{
+				ctx: context.Background(),
+				req: &model.RecordStockMovementRequest{
+					ProductID: testProductID,
+					Type:      entity.STOCK_MOVEMENT_RECEIVE,
+					Quantity:  1,
+					Reason:    "Return",
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUnauthorized,
+			mock: func(stockMovementRepo *repo_mocks.IStockMovementRepository, variantRepo *repo_mocks.IProductVariantRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", mock.Anything, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			stockMovementRepo := repo_mocks.NewIStockMovementRepository(t)
+			variantRepo := repo_mocks.NewIProductVariantRepository(t)
+			productHelper := helper_mocks.NewIProductHelper(t)
+
+			// Setup mocks
+			tt.mock(stockMovementRepo, variantRepo, productHelper)
+
+			s := &inventoryService{
+				postgresRepo: repository.RepositoryCollections{
+					StockMovementRepo:  stockMovementRepo,
+					ProductVariantRepo: variantRepo,
+				},
+				helper: helper.HelperCollections{
+					ProductHelper: productHelper,
+				},
+			}
+
+			_, err := s.RecordMovement(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("inventoryService.RecordMovement() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("inventoryService.RecordMovement() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+		})
+	}
+}
+
+func Test_inventoryService_GetLowStockReport(t *testing.T) {
+	ctx := context.Background()
+	products := []entity.Product{
+		{ID: uuid.New(), Name: "Sold Out", Quantity: 0, LowStockThreshold: 5},
+		{ID: uuid.New(), Name: "Running Low", Quantity: 3, LowStockThreshold: 5},
+	}
+
+	productRepo := repo_mocks.NewIProductRepository(t)
+	isReportFilter := mock.MatchedBy(func(filter *repository.FindProductByFilter) bool {
+		return filter.BelowLimit && filter.Status == nil && filter.Sort[0].Field == "quantity"
+	})
+	productRepo.On("CountByFilter", mock.Anything, mock.Anything, isReportFilter).Return(int64(len(products)), nil).Once()
+	productRepo.On("FindManyByFilter", mock.Anything, mock.Anything, isReportFilter).Return(products, nil).Once()
+
+	s := &inventoryService{
+		postgresRepo: repository.RepositoryCollections{
+			ProductRepo: productRepo,
+		},
+	}
+
+	got, err := s.GetLowStockReport(ctx, &model.GetLowStockReportRequest{})
+	if err != nil {
+		t.Fatalf("inventoryService.GetLowStockReport() error = %v", err)
+	}
+	if got.Count != 2 {
+		t.Errorf("inventoryService.GetLowStockReport() count = %v, want 2", got.Count)
+	}
+	wantStatuses := []string{entity.PRODUCT_STATUS_OUT_OF_STOCK, entity.PRODUCT_STATUS_LOW_STOCK}
+	for i, product := range got.Result {
+		if product.Status != wantStatuses[i] {
+			t.Errorf("inventoryService.GetLowStockReport() status of %v = %v, want %v", product.Name, product.Status, wantStatuses[i])
+		}
+	}
+}
@@ -6,19 +6,31 @@ import (
 )
 
 type ServiceCollections struct {
-	CategorySvc ICategoryService
-	ProductSvc  IProductService
-	UserService IUserService
-	ReviewSvc   IReviewService
-	WishlistSvc IWishlistService
+	CategorySvc  ICategoryService
+	ProductSvc   IProductService
+	UserService  IUserService
+	ReviewSvc    IReviewService
+	WishlistSvc  IWishlistService
+	RoleSvc      IRoleService
+	OAuthSvc     IOAuthService
+	ApiKeySvc    IApiKeyService
+	PrivacySvc   IPrivacyService
+	ProfileSvc   IProfileService
+	InventorySvc IInventoryService
 }
 
 func RegisterServices(helpers helper.HelperCollections, repositories repository.RepositoryCollections) ServiceCollections {
 	return ServiceCollections{
-		CategorySvc: NewCategoryService(repositories, helpers),
-		ProductSvc:  NewProductService(repositories, helpers),
-		UserService: NewUserService(repositories, helpers),
-		ReviewSvc:   NewReviewService(repositories, helpers),
-		WishlistSvc: NewWishlistService(repositories, helpers),
+		CategorySvc:  NewCategoryService(repositories, helpers),
+		ProductSvc:   NewProductService(repositories, helpers),
+		UserService:  NewUserService(repositories, helpers),
+		ReviewSvc:    NewReviewService(repositories, helpers),
+		WishlistSvc:  NewWishlistService(repositories, helpers),
+		RoleSvc:      NewRoleService(repositories, helpers),
+		OAuthSvc:     NewOAuthService(repositories, helpers),
+		ApiKeySvc:    NewApiKeyService(repositories, helpers),
+		PrivacySvc:   NewPrivacyService(repositories, helpers),
+		ProfileSvc:   NewProfileService(repositories, helpers),
+		InventorySvc: NewInventoryService(repositories, helpers),
 	}
 }
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+)
+
+type oAuthService struct {
+	postgresRepo repository.RepositoryCollections
+	helper       helper.HelperCollections
+}
+
+func NewOAuthService(
+	postgresRepo repository.RepositoryCollections,
+	helper helper.HelperCollections,
+) IOAuthService {
+	return &oAuthService{
+		postgresRepo: postgresRepo,
+		helper:       helper,
+	}
+}
+
+func (s *oAuthService) GetJWKS(
+	ctx context.Context,
+	req *model.GetJWKSRequest,
+) (*model.GetJWKSResponse, error) {
+	return &model.GetJWKSResponse{
+		Keys: s.helper.OAuthHelper.GetJSONWebKeys(),
+	}, nil
+}
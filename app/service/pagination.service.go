@@ -0,0 +1,161 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	_errors "errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	"sondth-test_soa/package/errors"
+)
+
+// DEFAULT_CURSOR_LIMIT is the page size of a list requested by cursor without a limit
+const DEFAULT_CURSOR_LIMIT = 10
+
+// cursor is the content of the opaque cursors of list responses
+type cursor struct {
+	ID       uuid.UUID            `json:"id"`
+	Backward bool                 `json:"backward,omitempty"`
+	Sort     []repository.OrderBy `json:"sort,omitempty"`
+}
+
+func (c *cursor) encode() *string {
+	content, _ := json.Marshal(c)
+	encoded := base64.RawURLEncoding.EncodeToString(content)
+	return &encoded
+}
+
+func decodeCursor(encoded string) (*cursor, error) {
+	content, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeCursorInvalid)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(content, &c); err != nil || c.ID == uuid.Nil {
+		return nil, errors.New(errors.ErrCodeCursorInvalid)
+	}
+
+	return &c, nil
+}
+
+// listPage is where a page of a list starts: after the row of a cursor, at a page number or at the start
+type listPage struct {
+	Sort   []repository.OrderBy
+	Keyset *repository.Keyset
+	Page   *int
+	Limit  *int
+	Peek   bool // one row past the page is read, telling if there's a next page
+}
+
+func newListPage(
+	req model.CursorRequest,
+	sort []repository.OrderBy,
+	fields repository.SortFields,
+	page *int,
+	limit *int,
+) (*listPage, error) {
+	p := &listPage{Sort: sort, Page: page}
+	size := 0
+	if limit != nil && *limit > 0 {
+		size = *limit
+	}
+
+	if req.Cursor != nil {
+		c, err := decodeCursor(*req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Sort = c.Sort
+		p.Keyset = &repository.Keyset{ID: c.ID, Backward: c.Backward}
+		p.Page = nil
+		if size == 0 {
+			size = DEFAULT_CURSOR_LIMIT
+		}
+	}
+	if err := validateSort(p.Sort, fields); err != nil {
+		return nil, err
+	}
+
+	if size == 0 {
+		return p, nil // the whole list
+	}
+	if p.Page != nil && *p.Page <= 1 {
+		p.Page = nil
+	}
+
+	p.Limit = &size
+	p.Peek = true
+	return p, nil
+}
+
+// cutPage drops the row read to know if there's a next page, puts the rows back in order and writes the cursors
+func cutPage[T any](p *listPage, rows []T, id func(T) uuid.UUID) ([]T, model.CursorResponse) {
+	var cursors model.CursorResponse
+	if p.Limit == nil {
+		return rows, cursors
+	}
+
+	more := len(rows) > *p.Limit
+	if more {
+		rows = rows[:*p.Limit]
+	}
+	backward := p.Keyset != nil && p.Keyset.Backward
+	if backward {
+		slices.Reverse(rows)
+	}
+	if len(rows) == 0 {
+		return rows, cursors
+	}
+
+	first := &cursor{ID: id(rows[0]), Backward: true, Sort: p.Sort}
+	last := &cursor{ID: id(rows[len(rows)-1]), Sort: p.Sort}
+	if backward {
+		cursors.NextCursor = last.encode()
+		if more {
+			cursors.PrevCursor = first.encode()
+		}
+	} else {
+		if more {
+			cursors.NextCursor = last.encode()
+		}
+		if p.Keyset != nil || p.Page != nil {
+			cursors.PrevCursor = first.encode()
+		}
+	}
+
+	return rows, cursors
+}
+
+// keysetError tells the cursor is invalid when the row it continues from doesn't exist anymore
+func keysetError(err error) error {
+	if _errors.Is(err, repository.ErrKeysetNotFound) {
+		return errors.New(errors.ErrCodeCursorInvalid)
+	}
+
+	return err
+}
+
+// validateSort makes sure a list is only sorted by the keys of its whitelist, in a known direction
+func validateSort(keys []repository.OrderBy, fields repository.SortFields) error {
+	if len(keys) > repository.MAX_SORT_KEYS {
+		return errors.Newf(errors.ErrCodeTooManySortKeys, repository.MAX_SORT_KEYS)
+	}
+
+	for _, key := range keys {
+		if !fields.Has(key.Field) {
+			return errors.Newf(errors.ErrCodeSortFieldInvalid, key.Field, strings.Join(fields.Keys(), ", "))
+		}
+		if key.Order != "" && !strings.EqualFold(key.Order, repository.SORT_ASC) && !strings.EqualFold(key.Order, repository.SORT_DESC) {
+			return errors.Newf(errors.ErrCodeSortDirectionInvalid, key.Order)
+		}
+	}
+
+	return nil
+}
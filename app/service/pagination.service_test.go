@@ -0,0 +1,207 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	"sondth-test_soa/package/errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	testCursorLimit   = 2
+	testInvalidCursor = "not a cursor"
+)
+
+func Test_validateSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []repository.OrderBy
+		wantErr bool
+		errCode int
+	}{
+		{
+			name: "No Sort",
+		},
+		{
+			name: "Several Keys",
+			keys: []repository.OrderBy{{Field: "price", Order: "DESC"}, {Field: "name"}},
+		},
+		{
+			name:    "Unknown Field",
+			keys:    []repository.OrderBy{{Field: "price; DROP TABLE products"}},
+			wantErr: true,
+			errCode: errors.ErrCodeSortFieldInvalid,
+		},
+		{
+			name:    "Unknown Direction",
+			keys:    []repository.OrderBy{{Field: "rating", Order: "sideways"}},
+			wantErr: true,
+			errCode: errors.ErrCodeSortDirectionInvalid,
+		},
+		{
+			name:    "Too Many Keys",
+			keys:    []repository.OrderBy{{Field: "price"}, {Field: "name"}, {Field: "rating"}, {Field: "popularity"}},
+			wantErr: true,
+			errCode: errors.ErrCodeTooManySortKeys,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSort(tt.keys, repository.ProductSortFields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSort() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("validateSort() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+		})
+	}
+}
+
+func Test_decodeCursor(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		name    string
+		encoded string
+		want    *cursor
+		wantErr bool
+	}{
+		{
+			name:    "Round Trip",
+			encoded: *(&cursor{ID: id, Backward: true, Sort: []repository.OrderBy{{Field: "price", Order: "DESC"}}}).encode(),
+			want:    &cursor{ID: id, Backward: true, Sort: []repository.OrderBy{{Field: "price", Order: "DESC"}}},
+		},
+		{
+			name:    "Not Base64",
+			encoded: testInvalidCursor,
+			wantErr: true,
+		},
+		{
+			name:    "No Row",
+			encoded: *(&cursor{}).encode(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.encoded)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != errors.ErrCodeCursorInvalid {
+				t.Errorf("decodeCursor() error code = %v, want %v", err.(*errors.CustomError).Code, errors.ErrCodeCursorInvalid)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeCursor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cutPage(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	page := 2
+	id := func(id uuid.UUID) uuid.UUID { return id }
+	forward := func(id uuid.UUID) *string { return (&cursor{ID: id}).encode() }
+	backward := func(id uuid.UUID) *string { return (&cursor{ID: id, Backward: true}).encode() }
+
+	tests := []struct {
+		name        string
+		req         model.CursorRequest
+		page        *int
+		rows        []uuid.UUID
+		wantRows    []uuid.UUID
+		wantCursors model.CursorResponse
+	}{
+		{
+			name:     "First Page",
+			rows:     ids,
+			wantRows: ids[:2],
+			wantCursors: model.CursorResponse{
+				NextCursor: forward(ids[1]),
+			},
+		},
+		{
+			name:        "Last Page",
+			rows:        ids[:1],
+			wantRows:    ids[:1],
+			wantCursors: model.CursorResponse{},
+		},
+		{
+			name:     "Page Number",
+			page:     &page,
+			rows:     ids,
+			wantRows: ids[:2],
+			wantCursors: model.CursorResponse{
+				NextCursor: forward(ids[1]),
+				PrevCursor: backward(ids[0]),
+			},
+		},
+		{
+			// A full last page has no row past it
+			name:     "Last Page Number",
+			page:     &page,
+			rows:     ids[:2],
+			wantRows: ids[:2],
+			wantCursors: model.CursorResponse{
+				PrevCursor: backward(ids[0]),
+			},
+		},
+		{
+			name:     "After Cursor",
+			req:      model.CursorRequest{Cursor: forward(uuid.New())},
+			rows:     ids[:2],
+			wantRows: ids[:2],
+			wantCursors: model.CursorResponse{
+				PrevCursor: backward(ids[0]),
+			},
+		},
+		{
+			// Rows before a cursor are read in the reverse order
+			name:     "Before Cursor",
+			req:      model.CursorRequest{Cursor: backward(uuid.New())},
+			rows:     ids,
+			wantRows: []uuid.UUID{ids[1], ids[0]},
+			wantCursors: model.CursorResponse{
+				NextCursor: forward(ids[0]),
+				PrevCursor: backward(ids[1]),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newListPage(tt.req, nil, repository.ProductSortFields, tt.page, &testCursorLimit)
+			if err != nil {
+				t.Fatalf("newListPage() error = %v", err)
+			}
+
+			rows, cursors := cutPage(p, append([]uuid.UUID{}, tt.rows...), id)
+			if !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("cutPage() rows = %v, want %v", rows, tt.wantRows)
+			}
+			if !reflect.DeepEqual(cursors, tt.wantCursors) {
+				t.Errorf("cutPage() cursors = %v, want %v", cursors, tt.wantCursors)
+			}
+		})
+	}
+}
+
+func Test_keysetError(t *testing.T) {
+	if err := keysetError(repository.ErrKeysetNotFound); err.(*errors.CustomError).Code != errors.ErrCodeCursorInvalid {
+		t.Errorf("keysetError() error code = %v, want %v", err.(*errors.CustomError).Code, errors.ErrCodeCursorInvalid)
+	}
+
+	err := errors.New(errors.ErrCodeInternalServerError)
+	if got := keysetError(err); got != err {
+		t.Errorf("keysetError() = %v, want %v", got, err)
+	}
+}
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	"sondth-test_soa/package/errors"
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/utils"
+)
+
+type privacyService struct {
+	postgresRepo repository.RepositoryCollections
+	helper       helper.HelperCollections
+}
+
+func NewPrivacyService(
+	postgresRepo repository.RepositoryCollections,
+	helper helper.HelperCollections,
+) IPrivacyService {
+	return &privacyService{
+		postgresRepo: postgresRepo,
+		helper:       helper,
+	}
+}
+
+func (s *privacyService) RequestExport(
+	ctx context.Context,
+	req *model.RequestDataExportRequest,
+) (*model.RequestDataExportResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	format := req.Format
+	if format == "" {
+		format = entity.DATA_EXPORT_FORMAT_JSON
+	}
+
+	job := entity.NewDataJob(user.ID, entity.DATA_JOB_TYPE_EXPORT)
+	job.Format = &format
+	if err := s.createDataJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return &model.RequestDataExportResponse{
+		Job: *job,
+	}, nil
+}
+
+func (s *privacyService) RequestErasure(
+	ctx context.Context,
+	req *model.RequestDataErasureRequest,
+) (*model.RequestDataErasureResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &requestUser.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+
+	// Erasure can't be undone, so a stolen access token alone is not enough to ask for it
+	if err := user.CheckPassword(req.Password); err != nil {
+		return nil, errors.New(errors.ErrCodeIncorrectPassword)
+	}
+	if user.MfaEnabled {
+		if !user.VerifyMfaCode(req.MfaCode) {
+			return nil, errors.New(errors.ErrCodeInvalidMfaCode)
+		}
+		// Keeps the used recovery code or TOTP step, so the code can't be replayed
+		if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+	}
+
+	job := entity.NewDataJob(user.ID, entity.DATA_JOB_TYPE_ERASURE)
+	if err := s.createDataJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return &model.RequestDataErasureResponse{
+		Job: *job,
+	}, nil
+}
+
+func (s *privacyService) GetDataJob(
+	ctx context.Context,
+	req *model.GetDataJobRequest,
+) (*model.GetDataJobResponse, error) {
+	job, err := s.findUserDataJob(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.GetDataJobResponse{
+		Job: *job,
+	}, nil
+}
+
+func (s *privacyService) DownloadDataExport(
+	ctx context.Context,
+	req *model.DownloadDataExportRequest,
+) (*model.DownloadDataExportResponse, error) {
+	job, err := s.findUserDataJob(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Type != entity.DATA_JOB_TYPE_EXPORT ||
+		job.Status != entity.DATA_JOB_STATUS_COMPLETED ||
+		job.FilePath == nil ||
+		job.IsExpired() {
+		return nil, errors.New(errors.ErrCodeDataExportNotReady)
+	}
+
+	return &model.DownloadDataExportResponse{
+		FilePath: *job.FilePath,
+		FileName: fmt.Sprintf("personal-data-%s%s", job.ID.String(), filepath.Ext(*job.FilePath)),
+	}, nil
+}
+
+// ResumeDataJobs queues again the jobs that were left unfinished by a previous run
+func (s *privacyService) ResumeDataJobs(ctx context.Context) error {
+	jobs, err := s.postgresRepo.DataJobRepo.FindManyByFilter(ctx, nil, &repository.FindDataJobByFilter{
+		Statuses: []string{entity.DATA_JOB_STATUS_PENDING, entity.DATA_JOB_STATUS_PROCESSING},
+		Filter: repository.Filter{
+			Fields: []string{"id"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if err := s.enqueueDataJob(ctx, job.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------------------
+func (s *privacyService) createDataJob(ctx context.Context, job *entity.DataJob) error {
+	// Only one job of a type runs at a time for a user
+	_, err := s.postgresRepo.DataJobRepo.FindOneByFilter(ctx, nil, &repository.FindDataJobByFilter{
+		UserID:   &job.UserID,
+		Type:     &job.Type,
+		Statuses: []string{entity.DATA_JOB_STATUS_PENDING, entity.DATA_JOB_STATUS_PROCESSING},
+		Filter: repository.Filter{
+			Fields: []string{"id"},
+		},
+	})
+	if err == nil {
+		return errors.New(errors.ErrCodeDataJobInProgress)
+	}
+	if err != gorm.ErrRecordNotFound {
+		return errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	if err := s.postgresRepo.DataJobRepo.Create(ctx, nil, job); err != nil {
+		return errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// The job stays pending and is picked up again on the next start when the queue is full
+	if err := s.enqueueDataJob(ctx, job.ID); err != nil {
+		return errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return nil
+}
+
+func (s *privacyService) findUserDataJob(ctx context.Context, id uuid.UUID) (*entity.DataJob, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	job, err := s.postgresRepo.DataJobRepo.FindOneByFilter(ctx, nil, &repository.FindDataJobByFilter{
+		ID:     &id,
+		UserID: &user.ID,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeDataJobNotFound)
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return job, nil
+}
+
+func (s *privacyService) enqueueDataJob(ctx context.Context, jobID uuid.UUID) error {
+	return s.helper.JobHelper.Enqueue(ctx, fmt.Sprintf("data_job:%s", jobID.String()), func(ctx context.Context) {
+		s.runDataJob(ctx, jobID)
+	})
+}
+
+func (s *privacyService) runDataJob(ctx context.Context, jobID uuid.UUID) {
+	job, err := s.postgresRepo.DataJobRepo.FindOneByFilter(ctx, nil, &repository.FindDataJobByFilter{
+		ID: &jobID,
+	})
+	if err != nil {
+		logger.WithCtx(ctx).Error("runDataJob: find job", slog.String("job_id", jobID.String()), slog.String("error", err.Error()))
+		return
+	}
+	if job.IsDone() {
+		return
+	}
+
+	job.Status = entity.DATA_JOB_STATUS_PROCESSING
+	if err := s.postgresRepo.DataJobRepo.Update(ctx, nil, job); err != nil {
+		logger.WithCtx(ctx).Error("runDataJob: update job", slog.String("job_id", jobID.String()), slog.String("error", err.Error()))
+		return
+	}
+
+	switch job.Type {
+	case entity.DATA_JOB_TYPE_EXPORT:
+		err = s.exportUserData(ctx, job)
+	case entity.DATA_JOB_TYPE_ERASURE:
+		err = s.eraseUserData(ctx, job)
+	default:
+		err = fmt.Errorf("unknown data job type %q", job.Type)
+	}
+	if err != nil {
+		logger.WithCtx(ctx).Error("runDataJob", slog.String("job_id", jobID.String()), slog.String("error", err.Error()))
+	}
+
+	job.Complete(err)
+	if err := s.postgresRepo.DataJobRepo.Update(ctx, nil, job); err != nil {
+		logger.WithCtx(ctx).Error("runDataJob: update job", slog.String("job_id", jobID.String()), slog.String("error", err.Error()))
+		return
+	}
+
+	logger.WithCtx(ctx).Info("runDataJob", slog.String("job_id", jobID.String()), slog.String("status", job.Status))
+}
+
+func (s *privacyService) exportUserData(ctx context.Context, job *entity.DataJob) error {
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &job.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	reviews, err := s.postgresRepo.ReviewRepo.FindManyByFilter(ctx, nil, &repository.FindReviewByFilter{
+		UserID:        &job.UserID,
+		ProductFields: []string{"id", "name"},
+	})
+	if err != nil {
+		return err
+	}
+
+	wishlists, err := s.postgresRepo.WishlistRepo.FindManyByFilter(ctx, nil, &repository.FindWishlistByFilter{
+		UserID:        &job.UserID,
+		ProductFields: []string{"id", "name"},
+	})
+	if err != nil {
+		return err
+	}
+
+	addresses, err := s.postgresRepo.AddressRepo.FindManyByFilter(ctx, nil, &repository.FindAddressByFilter{
+		UserID: &job.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	preference, err := s.postgresRepo.PreferenceRepo.FindOneByFilter(ctx, nil, &repository.FindPreferenceByFilter{
+		UserID: &job.UserID,
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	data := &model.UserDataExport{
+		ExportedAt: time.Now().Unix(),
+		Profile: model.UserDataProfile{
+			ID:            user.ID,
+			Username:      user.Username,
+			Fullname:      user.Fullname,
+			Role:          user.Role,
+			Email:         user.Email,
+			Phone:         user.Phone,
+			EmailVerified: user.EmailVerified,
+			PhoneVerified: user.PhoneVerified,
+			MfaEnabled:    user.MfaEnabled,
+			Status:        user.Status,
+			AvatarURL:     user.AvatarURL,
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+		},
+		Reviews:    make([]model.UserDataReview, 0, len(reviews)),
+		Wishlists:  make([]model.UserDataWishlist, 0, len(wishlists)),
+		Addresses:  addresses,
+		Preference: preference,
+	}
+	for _, review := range reviews {
+		data.Reviews = append(data.Reviews, model.UserDataReview{
+			ID:          review.ID,
+			ProductID:   review.ProductID,
+			ProductName: review.Product.Name,
+			Rating:      review.Rating,
+			Comment:     review.Comment,
+			CreatedAt:   review.CreatedAt,
+			UpdatedAt:   review.UpdatedAt,
+		})
+	}
+	for _, wishlist := range wishlists {
+		data.Wishlists = append(data.Wishlists, model.UserDataWishlist{
+			ID:          wishlist.ID,
+			ProductID:   wishlist.ProductID,
+			ProductName: wishlist.Product.Name,
+			CreatedAt:   wishlist.CreatedAt,
+		})
+	}
+
+	return s.helper.DataExportHelper.WriteExport(ctx, job, data)
+}
+
+// eraseUserData removes the personal data of the user, reviews are kept and shown with an anonymized author
+func (s *privacyService) eraseUserData(ctx context.Context, job *entity.DataJob) error {
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &job.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Exports are copies of the personal data
+	exportType := entity.DATA_JOB_TYPE_EXPORT
+	exports, err := s.postgresRepo.DataJobRepo.FindManyByFilter(ctx, nil, &repository.FindDataJobByFilter{
+		UserID: &job.UserID,
+		Type:   &exportType,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range exports {
+		export := &exports[i]
+		if export.FilePath == nil {
+			continue
+		}
+		if err := s.helper.DataExportHelper.RemoveExport(ctx, *export.FilePath); err != nil {
+			return err
+		}
+		export.FilePath = nil
+		export.ExpiresAt = nil
+		if err := s.postgresRepo.DataJobRepo.Update(ctx, nil, export); err != nil {
+			return err
+		}
+	}
+
+	if err := s.postgresRepo.WishlistRepo.DeleteManyByFilter(ctx, nil, &repository.FindWishlistByFilter{
+		UserID: &job.UserID,
+	}); err != nil {
+		return err
+	}
+	if err := s.postgresRepo.AddressRepo.DeleteManyByFilter(ctx, nil, &repository.FindAddressByFilter{
+		UserID: &job.UserID,
+	}); err != nil {
+		return err
+	}
+	if err := s.postgresRepo.PreferenceRepo.DeleteManyByFilter(ctx, nil, &repository.FindPreferenceByFilter{
+		UserID: &job.UserID,
+	}); err != nil {
+		return err
+	}
+	if user.AvatarKey != nil {
+		if err := s.helper.AvatarHelper.Remove(ctx, *user.AvatarKey); err != nil {
+			return err
+		}
+	}
+	if err := s.postgresRepo.IdentityRepo.DeleteManyByFilter(ctx, nil, &repository.FindIdentityByFilter{
+		UserID: &job.UserID,
+	}); err != nil {
+		return err
+	}
+	if err := s.postgresRepo.PasswordHistoryRepo.DeleteManyByFilter(ctx, nil, &repository.FindPasswordHistoryByFilter{
+		UserID: &job.UserID,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.postgresRepo.SessionRepo.RevokeManyByFilter(ctx, nil, &repository.FindSessionByFilter{
+		UserID: &job.UserID,
+	}); err != nil {
+		return err
+	}
+	if err := s.helper.OAuthHelper.RevokeAllUserTokens(ctx, job.UserID); err != nil {
+		return err
+	}
+
+	// Nobody knows the new password, the account can't be signed in to again
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	if err := user.Erase(password); err != nil {
+		return err
+	}
+
+	return s.postgresRepo.UserRepo.Update(ctx, nil, user)
+}
@@ -0,0 +1,532 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	helper_mocks "sondth-test_soa/mocks/helper"
+	repo_mocks "sondth-test_soa/mocks/repository"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/package/totp"
+	"sondth-test_soa/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_privacyService_RequestExport(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.RequestDataExportRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper)
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+
+	tests := []testCase{
+		{
+			name: "Request Export Success",
+			args: args{
+				ctx: ctx,
+				req: &model.RequestDataExportRequest{Format: entity.DATA_EXPORT_FORMAT_ZIP},
+			},
+			wantErr: false,
+			mock: func(dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper) {
+				// No job running
+				dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindDataJobByFilter) bool {
+					return *filter.UserID == userID && *filter.Type == entity.DATA_JOB_TYPE_EXPORT
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+
+				dataJobRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(job *entity.DataJob) bool {
+					return job.UserID == userID &&
+						job.Status == entity.DATA_JOB_STATUS_PENDING &&
+						*job.Format == entity.DATA_EXPORT_FORMAT_ZIP
+				})).Return(nil).Once()
+
+				jobHelper.On("Enqueue", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name: "Request Export Failed - Job In Progress",
+			args: args{
+				ctx: ctx,
+				req: &model.RequestDataExportRequest{},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeDataJobInProgress,
+			mock: func(dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper) {
+				dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.DataJob{ID: uuid.New()}, nil).Once()
+			},
+		},
+		{
+			name: "Request Export Failed - Unauthorized",
+			args: args{
+				ctx: context.Background(),
+				req: &model.RequestDataExportRequest{},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUnauthorized,
+			mock: func(dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper) {
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			dataJobRepo := repo_mocks.NewIDataJobRepository(t)
+			jobHelper := helper_mocks.NewIJobHelper(t)
+
+			// Setup mocks
+			tt.mock(dataJobRepo, jobHelper)
+
+			s := &privacyService{
+				postgresRepo: repository.RepositoryCollections{
+					DataJobRepo: dataJobRepo,
+				},
+				helper: helper.HelperCollections{
+					JobHelper: jobHelper,
+				},
+			}
+
+			_, err := s.RequestExport(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("privacyService.RequestExport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("privacyService.RequestExport() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+		})
+	}
+}
+
+func Test_privacyService_RequestErasure(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.RequestDataErasureRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(userRepo *repo_mocks.IUserRepository, dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper)
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+	secret, _ := totp.GenerateSecret()
+	code, _ := totp.GenerateCode(secret, time.Now())
+	newUser := func(mfaEnabled bool) *entity.User {
+		user := &entity.User{ID: userID, Username: username, Password: hashedPassword}
+		if mfaEnabled {
+			user.MfaEnabled = true
+			user.MfaSecret = &secret
+		}
+		return user
+	}
+	isUser := mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+		return filter.ID != nil && *filter.ID == userID
+	})
+
+	tests := []testCase{
+		{
+			name: "Request Erasure Success",
+			args: args{
+				ctx: ctx,
+				req: &model.RequestDataErasureRequest{Password: password},
+			},
+			wantErr: false,
+			mock: func(userRepo *repo_mocks.IUserRepository, dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, isUser).Return(newUser(false), nil).Once()
+
+				// No job running
+				dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindDataJobByFilter) bool {
+					return *filter.UserID == userID && *filter.Type == entity.DATA_JOB_TYPE_ERASURE
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+				dataJobRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				jobHelper.On("Enqueue", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name: "Request Erasure With MFA Success",
+			args: args{
+				ctx: ctx,
+				req: &model.RequestDataErasureRequest{Password: password, MfaCode: code},
+			},
+			wantErr: false,
+			mock: func(userRepo *repo_mocks.IUserRepository, dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, isUser).Return(newUser(true), nil).Once()
+
+				// Used time step is kept so the code can't be replayed
+				userRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.MfaLastStep != nil
+				})).Return(nil).Once()
+
+				dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+				dataJobRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				jobHelper.On("Enqueue", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name: "Request Erasure Failed - Incorrect Password",
+			args: args{
+				ctx: ctx,
+				req: &model.RequestDataErasureRequest{Password: "wrong", MfaCode: code},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeIncorrectPassword,
+			mock: func(userRepo *repo_mocks.IUserRepository, dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, isUser).Return(newUser(true), nil).Once()
+			},
+		},
+		{
+			name: "Request Erasure Failed - Missing MFA Code",
+			args: args{
+				ctx: ctx,
+				req: &model.RequestDataErasureRequest{Password: password},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeInvalidMfaCode,
+			mock: func(userRepo *repo_mocks.IUserRepository, dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, isUser).Return(newUser(true), nil).Once()
+			},
+		},
+		{
+			name: "Request Erasure Failed - Unauthorized",
+			args: args{
+				ctx: context.Background(),
+				req: &model.RequestDataErasureRequest{Password: password},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUnauthorized,
+			mock: func(userRepo *repo_mocks.IUserRepository, dataJobRepo *repo_mocks.IDataJobRepository, jobHelper *helper_mocks.IJobHelper) {
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			userRepo := repo_mocks.NewIUserRepository(t)
+			dataJobRepo := repo_mocks.NewIDataJobRepository(t)
+			jobHelper := helper_mocks.NewIJobHelper(t)
+
+			// Setup mocks
+			tt.mock(userRepo, dataJobRepo, jobHelper)
+
+			s := &privacyService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:    userRepo,
+					DataJobRepo: dataJobRepo,
+				},
+				helper: helper.HelperCollections{
+					JobHelper: jobHelper,
+				},
+			}
+
+			_, err := s.RequestErasure(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("privacyService.RequestErasure() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("privacyService.RequestErasure() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+		})
+	}
+}
+
+func Test_privacyService_DownloadDataExport(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.DownloadDataExportRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(dataJobRepo *repo_mocks.IDataJobRepository)
+	}
+
+	userID := uuid.New()
+	jobID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+	filePath := "exports/" + jobID.String() + ".zip"
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	expiredAt := time.Now().Add(-time.Hour).Unix()
+
+	tests := []testCase{
+		{
+			name: "Download Export Success",
+			args: args{
+				ctx: ctx,
+				req: &model.DownloadDataExportRequest{ID: jobID},
+			},
+			wantErr: false,
+			mock: func(dataJobRepo *repo_mocks.IDataJobRepository) {
+				// Jobs are only looked up among the ones of the user
+				dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindDataJobByFilter) bool {
+					return *filter.ID == jobID && *filter.UserID == userID
+				})).Return(&entity.DataJob{
+					ID:        jobID,
+					Type:      entity.DATA_JOB_TYPE_EXPORT,
+					Status:    entity.DATA_JOB_STATUS_COMPLETED,
+					FilePath:  &filePath,
+					ExpiresAt: &expiresAt,
+				}, nil).Once()
+			},
+		},
+		{
+			name: "Download Export Failed - Expired",
+			args: args{
+				ctx: ctx,
+				req: &model.DownloadDataExportRequest{ID: jobID},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeDataExportNotReady,
+			mock: func(dataJobRepo *repo_mocks.IDataJobRepository) {
+				dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.DataJob{
+					ID:        jobID,
+					Type:      entity.DATA_JOB_TYPE_EXPORT,
+					Status:    entity.DATA_JOB_STATUS_COMPLETED,
+					FilePath:  &filePath,
+					ExpiresAt: &expiredAt,
+				}, nil).Once()
+			},
+		},
+		{
+			name: "Download Export Failed - Still Processing",
+			args: args{
+				ctx: ctx,
+				req: &model.DownloadDataExportRequest{ID: jobID},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeDataExportNotReady,
+			mock: func(dataJobRepo *repo_mocks.IDataJobRepository) {
+				dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.DataJob{
+					ID:     jobID,
+					Type:   entity.DATA_JOB_TYPE_EXPORT,
+					Status: entity.DATA_JOB_STATUS_PROCESSING,
+				}, nil).Once()
+			},
+		},
+		{
+			name: "Download Export Failed - Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.DownloadDataExportRequest{ID: jobID},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeDataJobNotFound,
+			mock: func(dataJobRepo *repo_mocks.IDataJobRepository) {
+				dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			dataJobRepo := repo_mocks.NewIDataJobRepository(t)
+
+			// Setup mocks
+			tt.mock(dataJobRepo)
+
+			s := &privacyService{
+				postgresRepo: repository.RepositoryCollections{
+					DataJobRepo: dataJobRepo,
+				},
+			}
+
+			got, err := s.DownloadDataExport(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("privacyService.DownloadDataExport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("privacyService.DownloadDataExport() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+			if err == nil && got.FilePath != filePath {
+				t.Errorf("privacyService.DownloadDataExport() file path = %v, want %v", got.FilePath, filePath)
+			}
+		})
+	}
+}
+
+func Test_privacyService_runDataJob(t *testing.T) {
+	type mocks struct {
+		userRepo            *repo_mocks.IUserRepository
+		dataJobRepo         *repo_mocks.IDataJobRepository
+		reviewRepo          *repo_mocks.IReviewRepository
+		wishlistRepo        *repo_mocks.IWishlistRepository
+		identityRepo        *repo_mocks.IIdentityRepository
+		passwordHistoryRepo *repo_mocks.IPasswordHistoryRepository
+		sessionRepo         *repo_mocks.ISessionRepository
+		addressRepo         *repo_mocks.IAddressRepository
+		preferenceRepo      *repo_mocks.IPreferenceRepository
+		dataExportHelper    *helper_mocks.IDataExportHelper
+		oauthHelper         *helper_mocks.IOAuthHelper
+		avatarHelper        *helper_mocks.IAvatarHelper
+	}
+
+	type testCase struct {
+		name       string
+		job        *entity.DataJob
+		wantStatus string
+		mock       func(m mocks, job *entity.DataJob)
+	}
+
+	userID := uuid.New()
+	email := "user@example.com"
+	filePath := "exports/previous.json"
+	avatarKey := "avatars/user/1.png"
+
+	tests := []testCase{
+		{
+			name:       "Export Completed",
+			job:        &entity.DataJob{ID: uuid.New(), UserID: userID, Type: entity.DATA_JOB_TYPE_EXPORT, Status: entity.DATA_JOB_STATUS_PENDING},
+			wantStatus: entity.DATA_JOB_STATUS_COMPLETED,
+			mock: func(m mocks, job *entity.DataJob) {
+				m.dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(job, nil).Once()
+				m.dataJobRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+				m.userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Username: "user", Password: "hashed"}, nil).Once()
+				m.reviewRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Review{
+					{ID: uuid.New(), UserID: userID, Rating: 4, Product: entity.Product{Name: "Phone"}},
+				}, nil).Once()
+				m.wishlistRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Wishlist{}, nil).Once()
+				m.addressRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Address{
+					{ID: uuid.New(), UserID: userID, City: "Hanoi", IsDefault: true},
+				}, nil).Once()
+				m.preferenceRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+
+				m.dataExportHelper.On("WriteExport", mock.Anything, job, mock.MatchedBy(func(data *model.UserDataExport) bool {
+					return data.Profile.ID == userID &&
+						len(data.Reviews) == 1 &&
+						data.Reviews[0].ProductName == "Phone" &&
+						len(data.Addresses) == 1 &&
+						data.Preference == nil
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:       "Erasure Completed",
+			job:        &entity.DataJob{ID: uuid.New(), UserID: userID, Type: entity.DATA_JOB_TYPE_ERASURE, Status: entity.DATA_JOB_STATUS_PENDING},
+			wantStatus: entity.DATA_JOB_STATUS_COMPLETED,
+			mock: func(m mocks, job *entity.DataJob) {
+				m.dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(job, nil).Once()
+				m.userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Username: "user", Email: &email, MfaEnabled: true, AvatarKey: &avatarKey}, nil).Once()
+
+				// Previous exports are removed
+				m.dataJobRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindDataJobByFilter) bool {
+					return *filter.UserID == userID && *filter.Type == entity.DATA_JOB_TYPE_EXPORT
+				})).Return([]entity.DataJob{{ID: uuid.New(), FilePath: &filePath}}, nil).Once()
+				m.dataExportHelper.On("RemoveExport", mock.Anything, filePath).Return(nil).Once()
+				m.dataJobRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+
+				m.wishlistRepo.On("DeleteManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				m.addressRepo.On("DeleteManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				m.preferenceRepo.On("DeleteManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				m.avatarHelper.On("Remove", mock.Anything, avatarKey).Return(nil).Once()
+				m.identityRepo.On("DeleteManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				m.passwordHistoryRepo.On("DeleteManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				m.sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				m.oauthHelper.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil).Once()
+
+				// The record stays but nothing identifies the person anymore
+				m.userRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.ID == userID &&
+						user.Email == nil &&
+						user.AvatarKey == nil &&
+						!user.MfaEnabled &&
+						user.IsDeleted() &&
+						user.Username != "user"
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:       "Export Failed",
+			job:        &entity.DataJob{ID: uuid.New(), UserID: userID, Type: entity.DATA_JOB_TYPE_EXPORT, Status: entity.DATA_JOB_STATUS_PENDING},
+			wantStatus: entity.DATA_JOB_STATUS_FAILED,
+			mock: func(m mocks, job *entity.DataJob) {
+				m.dataJobRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(job, nil).Once()
+				m.dataJobRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+				m.userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			m := mocks{
+				userRepo:            repo_mocks.NewIUserRepository(t),
+				dataJobRepo:         repo_mocks.NewIDataJobRepository(t),
+				reviewRepo:          repo_mocks.NewIReviewRepository(t),
+				wishlistRepo:        repo_mocks.NewIWishlistRepository(t),
+				identityRepo:        repo_mocks.NewIIdentityRepository(t),
+				passwordHistoryRepo: repo_mocks.NewIPasswordHistoryRepository(t),
+				sessionRepo:         repo_mocks.NewISessionRepository(t),
+				addressRepo:         repo_mocks.NewIAddressRepository(t),
+				preferenceRepo:      repo_mocks.NewIPreferenceRepository(t),
+				dataExportHelper:    helper_mocks.NewIDataExportHelper(t),
+				oauthHelper:         helper_mocks.NewIOAuthHelper(t),
+				avatarHelper:        helper_mocks.NewIAvatarHelper(t),
+			}
+
+			// Setup mocks
+			tt.mock(m, tt.job)
+
+			s := &privacyService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:            m.userRepo,
+					DataJobRepo:         m.dataJobRepo,
+					ReviewRepo:          m.reviewRepo,
+					WishlistRepo:        m.wishlistRepo,
+					IdentityRepo:        m.identityRepo,
+					PasswordHistoryRepo: m.passwordHistoryRepo,
+					SessionRepo:         m.sessionRepo,
+					AddressRepo:         m.addressRepo,
+					PreferenceRepo:      m.preferenceRepo,
+				},
+				helper: helper.HelperCollections{
+					DataExportHelper: m.dataExportHelper,
+					OAuthHelper:      m.oauthHelper,
+					AvatarHelper:     m.avatarHelper,
+				},
+			}
+
+			s.runDataJob(context.Background(), tt.job.ID)
+			if tt.job.Status != tt.wantStatus {
+				t.Errorf("privacyService.runDataJob() status = %v, want %v", tt.job.Status, tt.wantStatus)
+			}
+			if tt.job.CompletedAt == nil {
+				t.Errorf("privacyService.runDataJob() completed_at is not set")
+			}
+		})
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"sondth-test_soa/package/errors"
 	logger "sondth-test_soa/package/log"
 
+	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
 )
 
 type productService struct {
@@ -48,10 +50,22 @@ func (s *productService) Create(
 	product.Name = req.Name
 	product.Description = req.Description
 	product.Price = req.Price
-	product.Quantity = req.Quantity
 	product.CategoryID = req.CategoryID
+	if req.LowStockThreshold != nil {
+		product.LowStockThreshold = *req.LowStockThreshold
+	}
 
-	if err := s.postgresRepo.ProductRepo.Create(ctx, nil, product); err != nil {
+	// The product is never left without its initial stock
+	if err := s.postgresRepo.ProductRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.postgresRepo.ProductRepo.Create(ctx, tx, product); err != nil {
+			return err
+		}
+		if req.Quantity > 0 {
+			movement := entity.NewStockMovement(product.ID, entity.STOCK_MOVEMENT_RECEIVE, int64(req.Quantity), entity.STOCK_REASON_INITIAL)
+			return recordStockMovement(ctx, tx, s.postgresRepo, movement)
+		}
+		return nil
+	}); err != nil {
 		return nil, err
 	}
 
@@ -79,7 +93,9 @@ func (s *productService) Update(
 	product.Name = req.Name
 	product.Description = req.Description
 	product.Price = req.Price
-	product.Quantity = req.Quantity
+	if req.LowStockThreshold != nil {
+		product.LowStockThreshold = *req.LowStockThreshold
+	}
 
 	if err := s.postgresRepo.ProductRepo.Update(ctx, nil, product); err != nil {
 		return nil, err
@@ -93,10 +109,22 @@ func (s *productService) Delete(
 	req *model.DeleteProductRequest,
 ) (*model.DeleteProductResponse, error) {
 	// Check if product exists
-	product, err := s.helper.ProductHelper.ValidateProductID(ctx, req.ID)
+	product, err := s.postgresRepo.ProductRepo.FindOneByFilter(ctx, nil, &repository.FindProductByFilter{
+		Filter: repository.Filter{
+			Fields: []string{"id", "quantity", "reserved_quantity"},
+		},
+		ID: &req.ID,
+	})
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeProductNotFound)
+		}
 		return nil, err
 	}
+	// The stock has to be moved out first, as for a variant
+	if product.Quantity > 0 || product.Reserved > 0 {
+		return nil, errors.New(errors.ErrCodeProductHasStock)
+	}
 
 	if err := s.postgresRepo.ProductRepo.Delete(ctx, nil, product); err != nil {
 		return nil, err
@@ -109,42 +137,51 @@ func (s *productService) GetProducts(
 	ctx context.Context,
 	req *model.GetProductRequest,
 ) (*model.GetProductResponse, error) {
+	page, err := newListPage(req.CursorRequest, req.Sort, repository.ProductSortFields, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
 	results := &model.GetProductResponse{}
 	filter := &repository.FindProductByFilter{
 		Filter: repository.Filter{
-			Fields: []string{"products.id", "products.name", "products.description", "products.price", "products.quantity", "products.category_id"},
+			Fields: []string{"products.id", "products.name", "products.description", "products.price", "products.quantity", "products.low_stock_threshold", "products.category_id"},
 		},
 		Name:           req.Name,
 		CategoryIDs:    req.CategoryIDs,
-		Page:           req.Page,
-		Limit:          req.Limit,
+		Page:           page.Page,
+		Limit:          page.Limit,
+		Peek:           page.Peek,
 		Status:         req.Status,
-		Order:          req.Order,
+		Sort:           page.Sort,
+		Keyset:         page.Keyset,
 		CategoryFields: []string{"categories.id", "categories.name"},
 	}
 
 	errGroup, errCtx := errgroup.WithContext(ctx)
-	errGroup.Go(func() error {
-		count, err := s.postgresRepo.ProductRepo.CountByFilter(errCtx, nil, filter)
-		if err != nil {
-			return err
-		}
+	if !req.SkipCount {
+		errGroup.Go(func() error {
+			count, err := s.postgresRepo.ProductRepo.CountByFilter(errCtx, nil, filter)
+			if err != nil {
+				return err
+			}
 
-		results.Count = count
-		return nil
-	})
+			results.Count = &count
+			return nil
+		})
+	}
 	errGroup.Go(func() error {
 		products, err := s.postgresRepo.ProductRepo.FindManyByFilter(errCtx, nil, filter)
 		if err != nil {
+			return keysetError(err)
+		}
+		products, results.CursorResponse = cutPage(page, products, func(product entity.Product) uuid.UUID { return product.ID })
+		if err := s.setVariantSummaries(errCtx, products); err != nil {
 			return err
 		}
-		// Set status for each product based on quantity
+		// Set status for each product based on quantity and low-stock threshold
 		for i := range products {
-			if products[i].Quantity > 0 {
-				products[i].Status = entity.PRODUCT_STATUS_IN_STOCK
-			} else {
-				products[i].Status = entity.PRODUCT_STATUS_OUT_OF_STOCK
-			}
+			products[i].SetStatus()
 		}
 
 		results.Result = products
@@ -157,3 +194,192 @@ func (s *productService) GetProducts(
 
 	return results, nil
 }
+
+func (s *productService) CreateVariant(
+	ctx context.Context,
+	req *model.CreateProductVariantRequest,
+) (*model.CreateProductVariantResponse, error) {
+	// Check if product exists
+	if _, err := s.helper.ProductHelper.ValidateProductID(ctx, req.ProductID); err != nil {
+		return nil, err
+	}
+
+	variant := entity.NewProductVariant(req.ProductID)
+	if err := s.checkVariantAvailable(ctx, variant, req.Sku, req.Options); err != nil {
+		return nil, err
+	}
+	variant.Sku = req.Sku
+	variant.Options = req.Options
+	variant.Price = req.Price
+
+	// The variant is never left without its initial stock
+	if err := s.postgresRepo.ProductRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.postgresRepo.ProductVariantRepo.Create(ctx, tx, variant); err != nil {
+			return err
+		}
+		if req.Quantity > 0 {
+			movement := entity.NewStockMovement(variant.ProductID, entity.STOCK_MOVEMENT_RECEIVE, int64(req.Quantity), entity.STOCK_REASON_INITIAL)
+			movement.VariantID = &variant.ID
+			if err := recordStockMovement(ctx, tx, s.postgresRepo, movement); err != nil {
+				return err
+			}
+			variant.Quantity = movement.BalanceAfter
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	variant.SetStatus()
+
+	return &model.CreateProductVariantResponse{
+		Variant: *variant,
+	}, nil
+}
+
+func (s *productService) UpdateVariant(
+	ctx context.Context,
+	req *model.UpdateProductVariantRequest,
+) (*model.UpdateProductVariantResponse, error) {
+	variant, err := s.findVariant(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkVariantAvailable(ctx, variant, req.Sku, req.Options); err != nil {
+		return nil, err
+	}
+	variant.Sku = req.Sku
+	variant.Options = req.Options
+	variant.Price = req.Price
+
+	if err := s.postgresRepo.ProductVariantRepo.Update(ctx, nil, variant); err != nil {
+		return nil, err
+	}
+	variant.SetStatus()
+
+	return &model.UpdateProductVariantResponse{
+		Variant: *variant,
+	}, nil
+}
+
+func (s *productService) DeleteVariant(
+	ctx context.Context,
+	req *model.DeleteProductVariantRequest,
+) (*model.DeleteProductVariantResponse, error) {
+	variant, err := s.findVariant(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	// The stock of the variant is counted in the product and recorded in its movements,
+	// so it has to be moved out before the variant can go
+	if variant.Quantity > 0 || variant.Reserved > 0 {
+		return nil, errors.New(errors.ErrCodeVariantHasStock)
+	}
+
+	if err := s.postgresRepo.ProductVariantRepo.Delete(ctx, nil, variant); err != nil {
+		return nil, err
+	}
+
+	return &model.DeleteProductVariantResponse{}, nil
+}
+
+func (s *productService) GetVariants(
+	ctx context.Context,
+	req *model.GetProductVariantsRequest,
+) (*model.GetProductVariantsResponse, error) {
+	// Check if product exists
+	if _, err := s.helper.ProductHelper.ValidateProductID(ctx, req.ProductID); err != nil {
+		return nil, err
+	}
+
+	variants, err := s.postgresRepo.ProductVariantRepo.FindManyByFilter(ctx, nil, &repository.FindProductVariantByFilter{
+		ProductID: &req.ProductID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range variants {
+		variants[i].SetStatus()
+	}
+
+	return &model.GetProductVariantsResponse{
+		Variants: variants,
+	}, nil
+}
+
+// -------------------------------------------------------------------------------
+func (s *productService) findVariant(ctx context.Context, id uuid.UUID) (*entity.ProductVariant, error) {
+	variant, err := s.postgresRepo.ProductVariantRepo.FindOneByFilter(ctx, nil, &repository.FindProductVariantByFilter{
+		ID: &id,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeVariantNotFound)
+		}
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+// checkVariantAvailable makes sure no other variant uses the SKU, or the options within the same product
+func (s *productService) checkVariantAvailable(
+	ctx context.Context,
+	variant *entity.ProductVariant,
+	sku string,
+	options map[string]string,
+) error {
+	if sku != variant.Sku {
+		existing, err := s.postgresRepo.ProductVariantRepo.FindOneByFilter(ctx, nil, &repository.FindProductVariantByFilter{
+			Sku: &sku,
+		})
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if existing != nil && existing.ID != variant.ID {
+			return errors.New(errors.ErrCodeVariantSkuExisted)
+		}
+	}
+
+	variants, err := s.postgresRepo.ProductVariantRepo.FindManyByFilter(ctx, nil, &repository.FindProductVariantByFilter{
+		ProductID: &variant.ProductID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, other := range variants {
+		if other.ID != variant.ID && other.HasOptions(options) {
+			return errors.New(errors.ErrCodeVariantOptionsExisted)
+		}
+	}
+
+	return nil
+}
+
+// setVariantSummaries adds the price range and stock of the variants to the products having some
+func (s *productService) setVariantSummaries(ctx context.Context, products []entity.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+	summaries, err := s.postgresRepo.ProductVariantRepo.GetVariantSummaries(ctx, nil, productIDs)
+	if err != nil {
+		return err
+	}
+
+	summaryByProduct := make(map[uuid.UUID]entity.ProductVariantSummary, len(summaries))
+	for _, summary := range summaries {
+		summaryByProduct[summary.ProductID] = summary
+	}
+	for i := range products {
+		if summary, ok := summaryByProduct[products[i].ID]; ok {
+			products[i].Variants = &summary
+		}
+	}
+
+	return nil
+}
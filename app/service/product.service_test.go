@@ -10,6 +10,7 @@ import (
 	helper_mocks "sondth-test_soa/mocks/helper"
 	repo_mocks "sondth-test_soa/mocks/repository"
 	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
 	"testing"
 
 	"github.com/google/uuid"
@@ -37,16 +38,24 @@ func Test_productService_Create(t *testing.T) {
 		args    args
 		want    *model.CreateProductResponse
 		wantErr bool
-		mock    func(repo *repo_mocks.IProductRepository, categoryHelper *helper_mocks.ICategoryHelper)
+		mock    func(repo *repo_mocks.IProductRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, categoryHelper *helper_mocks.ICategoryHelper)
 	}
 
-	ctx := context.Background()
+	testUserID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: testUserID,
+	})
+	// The transaction runs the changes straight away
+	inTransaction := func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+		return fn(nil)
+	}
 	tests := []testCase{
 		{
 			name: "Create Success",
 			s: &productService{
 				postgresRepo: repository.RepositoryCollections{
-					ProductRepo: repo_mocks.NewIProductRepository(t),
+					ProductRepo:       repo_mocks.NewIProductRepository(t),
+					StockMovementRepo: repo_mocks.NewIStockMovementRepository(t),
 				},
 				helper: helper.HelperCollections{
 					CategoryHelper: helper_mocks.NewICategoryHelper(t),
@@ -64,7 +73,7 @@ func Test_productService_Create(t *testing.T) {
 			},
 			want:    &model.CreateProductResponse{},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IProductRepository, categoryHelper *helper_mocks.ICategoryHelper) {
+			mock: func(repo *repo_mocks.IProductRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, categoryHelper *helper_mocks.ICategoryHelper) {
 				// Mock category validation
 				categoryHelper.On("ValidateCategoryID", ctx, testCategoryID).Return(&entity.Category{}, nil).Once()
 
@@ -73,21 +82,31 @@ func Test_productService_Create(t *testing.T) {
 					return filter.Name != nil && *filter.Name == testProductName
 				})).Return(nil, gorm.ErrRecordNotFound).Once()
 
-				// Mock product creation
+				// Mock product creation, with its initial stock in one transaction
+				repo.On("Transaction", ctx, mock.Anything).Return(inTransaction).Once()
 				repo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(product *entity.Product) bool {
 					return product.Name == testProductName &&
 						*product.Description == testProductDesc &&
 						product.Price == testProductPrice &&
-						product.Quantity == testProductQuantity &&
 						product.CategoryID == testCategoryID
 				})).Return(nil).Once()
+
+				// Mock initial stock, the quantity is received into the ledger
+				stockMovementRepo.On("Record", ctx, mock.Anything, mock.MatchedBy(func(movement *entity.StockMovement) bool {
+					return movement.Type == entity.STOCK_MOVEMENT_RECEIVE &&
+						movement.Quantity == int64(testProductQuantity) &&
+						movement.VariantID == nil &&
+						movement.ActorType == entity.STOCK_ACTOR_USER &&
+						movement.ActorID == testUserID
+				})).Return(nil).Once()
 			},
 		},
 		{
 			name: "Category Not Found",
 			s: &productService{
 				postgresRepo: repository.RepositoryCollections{
-					ProductRepo: repo_mocks.NewIProductRepository(t),
+					ProductRepo:       repo_mocks.NewIProductRepository(t),
+					StockMovementRepo: repo_mocks.NewIStockMovementRepository(t),
 				},
 				helper: helper.HelperCollections{
 					CategoryHelper: helper_mocks.NewICategoryHelper(t),
@@ -105,7 +124,7 @@ func Test_productService_Create(t *testing.T) {
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IProductRepository, categoryHelper *helper_mocks.ICategoryHelper) {
+			mock: func(repo *repo_mocks.IProductRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, categoryHelper *helper_mocks.ICategoryHelper) {
 				// Mock category validation failure
 				categoryHelper.On("ValidateCategoryID", ctx, testCategoryID).Return(nil, errors.New(errors.ErrCodeCategoryNotFound)).Once()
 			},
@@ -114,7 +133,8 @@ func Test_productService_Create(t *testing.T) {
 			name: "Product Already Exists",
 			s: &productService{
 				postgresRepo: repository.RepositoryCollections{
-					ProductRepo: repo_mocks.NewIProductRepository(t),
+					ProductRepo:       repo_mocks.NewIProductRepository(t),
+					StockMovementRepo: repo_mocks.NewIStockMovementRepository(t),
 				},
 				helper: helper.HelperCollections{
 					CategoryHelper: helper_mocks.NewICategoryHelper(t),
@@ -132,7 +152,7 @@ func Test_productService_Create(t *testing.T) {
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IProductRepository, categoryHelper *helper_mocks.ICategoryHelper) {
+			mock: func(repo *repo_mocks.IProductRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, categoryHelper *helper_mocks.ICategoryHelper) {
 				// Mock category validation
 				categoryHelper.On("ValidateCategoryID", ctx, testCategoryID).Return(&entity.Category{}, nil).Once()
 
@@ -146,7 +166,8 @@ func Test_productService_Create(t *testing.T) {
 			name: "Create Error",
 			s: &productService{
 				postgresRepo: repository.RepositoryCollections{
-					ProductRepo: repo_mocks.NewIProductRepository(t),
+					ProductRepo:       repo_mocks.NewIProductRepository(t),
+					StockMovementRepo: repo_mocks.NewIStockMovementRepository(t),
 				},
 				helper: helper.HelperCollections{
 					CategoryHelper: helper_mocks.NewICategoryHelper(t),
@@ -164,7 +185,7 @@ func Test_productService_Create(t *testing.T) {
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IProductRepository, categoryHelper *helper_mocks.ICategoryHelper) {
+			mock: func(repo *repo_mocks.IProductRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, categoryHelper *helper_mocks.ICategoryHelper) {
 				// Mock category validation
 				categoryHelper.On("ValidateCategoryID", ctx, testCategoryID).Return(&entity.Category{}, nil).Once()
 
@@ -174,11 +195,11 @@ func Test_productService_Create(t *testing.T) {
 				})).Return(nil, gorm.ErrRecordNotFound).Once()
 
 				// Mock product creation error
+				repo.On("Transaction", ctx, mock.Anything).Return(inTransaction).Once()
 				repo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(product *entity.Product) bool {
 					return product.Name == testProductName &&
 						*product.Description == testProductDesc &&
 						product.Price == testProductPrice &&
-						product.Quantity == testProductQuantity &&
 						product.CategoryID == testCategoryID
 				})).Return(errors.New(errors.ErrCodeInternalServerError)).Once()
 			},
@@ -190,6 +211,7 @@ func Test_productService_Create(t *testing.T) {
 			// Set up mocks
 			tt.mock(
 				tt.s.postgresRepo.ProductRepo.(*repo_mocks.IProductRepository),
+				tt.s.postgresRepo.StockMovementRepo.(*repo_mocks.IStockMovementRepository),
 				tt.s.helper.CategoryHelper.(*helper_mocks.ICategoryHelper),
 			)
 
@@ -244,14 +266,11 @@ func Test_productService_Update(t *testing.T) {
 			args: args{
 				ctx: ctx,
 				req: &model.UpdateProductRequest{
-					ID: testProductID,
-					CreateProductRequest: model.CreateProductRequest{
-						Name:        testProductName,
-						Description: &testProductDesc,
-						Price:       testProductPrice,
-						Quantity:    testProductQuantity,
-						CategoryID:  testCategoryID,
-					},
+					ID:          testProductID,
+					Name:        testProductName,
+					Description: &testProductDesc,
+					Price:       testProductPrice,
+					CategoryID:  testCategoryID,
 				},
 			},
 			want:    &model.UpdateProductResponse{},
@@ -285,14 +304,11 @@ func Test_productService_Update(t *testing.T) {
 			args: args{
 				ctx: ctx,
 				req: &model.UpdateProductRequest{
-					ID: testProductID,
-					CreateProductRequest: model.CreateProductRequest{
-						Name:        testProductName,
-						Description: &testProductDesc,
-						Price:       testProductPrice,
-						Quantity:    testProductQuantity,
-						CategoryID:  uuid.New(), // Different category ID
-					},
+					ID:          testProductID,
+					Name:        testProductName,
+					Description: &testProductDesc,
+					Price:       testProductPrice,
+					CategoryID:  uuid.New(), // Different category ID
 				},
 			},
 			want:    &model.UpdateProductResponse{},
@@ -327,14 +343,11 @@ func Test_productService_Update(t *testing.T) {
 			args: args{
 				ctx: ctx,
 				req: &model.UpdateProductRequest{
-					ID: testProductID,
-					CreateProductRequest: model.CreateProductRequest{
-						Name:        testProductName,
-						Description: &testProductDesc,
-						Price:       testProductPrice,
-						Quantity:    testProductQuantity,
-						CategoryID:  testCategoryID,
-					},
+					ID:          testProductID,
+					Name:        testProductName,
+					Description: &testProductDesc,
+					Price:       testProductPrice,
+					CategoryID:  testCategoryID,
 				},
 			},
 			want:    nil,
@@ -358,14 +371,11 @@ func Test_productService_Update(t *testing.T) {
 			args: args{
 				ctx: ctx,
 				req: &model.UpdateProductRequest{
-					ID: testProductID,
-					CreateProductRequest: model.CreateProductRequest{
-						Name:        testProductName,
-						Description: &testProductDesc,
-						Price:       testProductPrice,
-						Quantity:    testProductQuantity,
-						CategoryID:  uuid.New(), // Different category ID
-					},
+					ID:          testProductID,
+					Name:        testProductName,
+					Description: &testProductDesc,
+					Price:       testProductPrice,
+					CategoryID:  uuid.New(), // Different category ID
 				},
 			},
 			want:    nil,
@@ -392,14 +402,11 @@ func Test_productService_Update(t *testing.T) {
 			args: args{
 				ctx: ctx,
 				req: &model.UpdateProductRequest{
-					ID: testProductID,
-					CreateProductRequest: model.CreateProductRequest{
-						Name:        testProductName,
-						Description: &testProductDesc,
-						Price:       testProductPrice,
-						Quantity:    testProductQuantity,
-						CategoryID:  testCategoryID,
-					},
+					ID:          testProductID,
+					Name:        testProductName,
+					Description: &testProductDesc,
+					Price:       testProductPrice,
+					CategoryID:  testCategoryID,
 				},
 			},
 			want:    nil,
@@ -447,17 +454,17 @@ func Test_productService_Delete(t *testing.T) {
 		args    args
 		want    *model.DeleteProductResponse
 		wantErr bool
-		mock    func(repo *repo_mocks.IProductRepository, productHelper *helper_mocks.IProductHelper)
+		errCode int
+		mock    func(repo *repo_mocks.IProductRepository)
 	}
 
 	ctx := context.Background()
 	existingProduct := &entity.Product{
-		Name:        testProductName,
-		Description: &testProductDesc,
-		Price:       testProductPrice,
-		Quantity:    testProductQuantity,
-		CategoryID:  testCategoryID,
+		ID: testProductID,
 	}
+	isProduct := mock.MatchedBy(func(filter *repository.FindProductByFilter) bool {
+		return filter.ID != nil && *filter.ID == testProductID
+	})
 
 	tests := []testCase{
 		{
@@ -466,9 +473,6 @@ func Test_productService_Delete(t *testing.T) {
 				postgresRepo: repository.RepositoryCollections{
 					ProductRepo: repo_mocks.NewIProductRepository(t),
 				},
-				helper: helper.HelperCollections{
-					ProductHelper: helper_mocks.NewIProductHelper(t),
-				},
 			},
 			args: args{
 				ctx: ctx,
@@ -478,17 +482,13 @@ func Test_productService_Delete(t *testing.T) {
 			},
 			want:    &model.DeleteProductResponse{},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IProductRepository, productHelper *helper_mocks.IProductHelper) {
-				// Mock product validation
-				productHelper.On("ValidateProductID", ctx, testProductID).Return(existingProduct, nil).Once()
+			mock: func(repo *repo_mocks.IProductRepository) {
+				// Mock product lookup
+				repo.On("FindOneByFilter", ctx, mock.Anything, isProduct).Return(existingProduct, nil).Once()
 
 				// Mock product deletion
 				repo.On("Delete", ctx, mock.Anything, mock.MatchedBy(func(product *entity.Product) bool {
-					return product.Name == testProductName &&
-						*product.Description == testProductDesc &&
-						product.Price == testProductPrice &&
-						product.Quantity == testProductQuantity &&
-						product.CategoryID == testCategoryID
+					return product.ID == testProductID
 				})).Return(nil).Once()
 			},
 		},
@@ -498,8 +498,26 @@ func Test_productService_Delete(t *testing.T) {
 				postgresRepo: repository.RepositoryCollections{
 					ProductRepo: repo_mocks.NewIProductRepository(t),
 				},
-				helper: helper.HelperCollections{
-					ProductHelper: helper_mocks.NewIProductHelper(t),
+			},
+			args: args{
+				ctx: ctx,
+				req: &model.DeleteProductRequest{
+					ID: testProductID,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeProductNotFound,
+			mock: func(repo *repo_mocks.IProductRepository) {
+				// Mock product lookup failure
+				repo.On("FindOneByFilter", ctx, mock.Anything, isProduct).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+		{
+			name: "Product Has Stock",
+			s: &productService{
+				postgresRepo: repository.RepositoryCollections{
+					ProductRepo: repo_mocks.NewIProductRepository(t),
 				},
 			},
 			args: args{
@@ -510,19 +528,38 @@ func Test_productService_Delete(t *testing.T) {
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IProductRepository, productHelper *helper_mocks.IProductHelper) {
-				// Mock product validation failure
-				productHelper.On("ValidateProductID", ctx, testProductID).Return(nil, errors.New(errors.ErrCodeProductNotFound)).Once()
+			errCode: errors.ErrCodeProductHasStock,
+			mock: func(repo *repo_mocks.IProductRepository) {
+				// Mock a product with stock
+				repo.On("FindOneByFilter", ctx, mock.Anything, isProduct).Return(&entity.Product{ID: testProductID, Quantity: testProductQuantity}, nil).Once()
 			},
 		},
 		{
-			name: "Delete Error",
+			name: "Product Has Reserved Stock",
 			s: &productService{
 				postgresRepo: repository.RepositoryCollections{
 					ProductRepo: repo_mocks.NewIProductRepository(t),
 				},
-				helper: helper.HelperCollections{
-					ProductHelper: helper_mocks.NewIProductHelper(t),
+			},
+			args: args{
+				ctx: ctx,
+				req: &model.DeleteProductRequest{
+					ID: testProductID,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeProductHasStock,
+			mock: func(repo *repo_mocks.IProductRepository) {
+				// Mock a product with stock set aside for orders
+				repo.On("FindOneByFilter", ctx, mock.Anything, isProduct).Return(&entity.Product{ID: testProductID, Reserved: 2}, nil).Once()
+			},
+		},
+		{
+			name: "Delete Error",
+			s: &productService{
+				postgresRepo: repository.RepositoryCollections{
+					ProductRepo: repo_mocks.NewIProductRepository(t),
 				},
 			},
 			args: args{
@@ -533,9 +570,10 @@ func Test_productService_Delete(t *testing.T) {
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IProductRepository, productHelper *helper_mocks.IProductHelper) {
-				// Mock product validation
-				productHelper.On("ValidateProductID", ctx, testProductID).Return(existingProduct, nil).Once()
+			errCode: errors.ErrCodeInternalServerError,
+			mock: func(repo *repo_mocks.IProductRepository) {
+				// Mock product lookup
+				repo.On("FindOneByFilter", ctx, mock.Anything, isProduct).Return(existingProduct, nil).Once()
 
 				// Mock delete error
 				repo.On("Delete", ctx, mock.Anything, mock.Anything).Return(errors.New(errors.ErrCodeInternalServerError)).Once()
@@ -546,16 +584,16 @@ func Test_productService_Delete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Set up mocks
-			tt.mock(
-				tt.s.postgresRepo.ProductRepo.(*repo_mocks.IProductRepository),
-				tt.s.helper.ProductHelper.(*helper_mocks.IProductHelper),
-			)
+			tt.mock(tt.s.postgresRepo.ProductRepo.(*repo_mocks.IProductRepository))
 
 			got, err := tt.s.Delete(tt.args.ctx, tt.args.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("productService.Delete() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("productService.Delete() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("productService.Delete() = %v, want %v", got, tt.want)
 			}
@@ -575,7 +613,7 @@ func Test_productService_GetProducts(t *testing.T) {
 		args    args
 		want    *model.GetProductResponse
 		wantErr bool
-		mock    func(repo *repo_mocks.IProductRepository, ctx context.Context)
+		mock    func(repo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, ctx context.Context)
 	}
 
 	ctx := context.Background()
@@ -596,12 +634,16 @@ func Test_productService_GetProducts(t *testing.T) {
 		},
 	}
 
+	count := int64(len(products))
+	variantCount := int64(1)
+
 	tests := []testCase{
 		{
 			name: "Get Products Success",
 			s: &productService{
 				postgresRepo: repository.RepositoryCollections{
-					ProductRepo: repo_mocks.NewIProductRepository(t),
+					ProductRepo:        repo_mocks.NewIProductRepository(t),
+					ProductVariantRepo: repo_mocks.NewIProductVariantRepository(t),
 				},
 			},
 			args: args{
@@ -614,20 +656,21 @@ func Test_productService_GetProducts(t *testing.T) {
 				},
 			},
 			want: &model.GetProductResponse{
-				Count:  int64(len(products)),
+				Count:  &count,
 				Result: products,
 			},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IProductRepository, ctx context.Context) {
+			mock: func(repo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, ctx context.Context) {
 				// Mock find products
 				repo.On("FindManyByFilter", mock.MatchedBy(func(c context.Context) bool {
 					return true // Accept any context since we just want to verify the call
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindProductByFilter) bool {
 					return filter.Name != nil && *filter.Name == testProductName &&
 						len(filter.CategoryIDs) == 1 && filter.CategoryIDs[0] == testCategoryID &&
-						filter.Page != nil && *filter.Page == 1 &&
-						filter.Limit != nil && *filter.Limit == 10
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == 10 && filter.Peek
 				})).Return(products, nil).Once()
+				variantRepo.On("GetVariantSummaries", mock.Anything, mock.Anything, mock.Anything).Return([]entity.ProductVariantSummary{}, nil).Once()
 
 				// Mock count products
 				repo.On("CountByFilter", mock.MatchedBy(func(c context.Context) bool {
@@ -635,8 +678,8 @@ func Test_productService_GetProducts(t *testing.T) {
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindProductByFilter) bool {
 					return filter.Name != nil && *filter.Name == testProductName &&
 						len(filter.CategoryIDs) == 1 && filter.CategoryIDs[0] == testCategoryID &&
-						filter.Page != nil && *filter.Page == 1 &&
-						filter.Limit != nil && *filter.Limit == 10
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == 10 && filter.Peek
 				})).Return(int64(len(products)), nil).Once()
 			},
 		},
@@ -644,7 +687,8 @@ func Test_productService_GetProducts(t *testing.T) {
 			name: "Get Products Success - Empty Filter",
 			s: &productService{
 				postgresRepo: repository.RepositoryCollections{
-					ProductRepo: repo_mocks.NewIProductRepository(t),
+					ProductRepo:        repo_mocks.NewIProductRepository(t),
+					ProductVariantRepo: repo_mocks.NewIProductVariantRepository(t),
 				},
 			},
 			args: args{
@@ -655,20 +699,21 @@ func Test_productService_GetProducts(t *testing.T) {
 				},
 			},
 			want: &model.GetProductResponse{
-				Count:  int64(len(products)),
+				Count:  &count,
 				Result: products,
 			},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IProductRepository, ctx context.Context) {
+			mock: func(repo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, ctx context.Context) {
 				// Mock find products with nil filter
 				repo.On("FindManyByFilter", mock.MatchedBy(func(c context.Context) bool {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindProductByFilter) bool {
 					return filter.Name == nil &&
 						len(filter.CategoryIDs) == 0 &&
-						filter.Page != nil && *filter.Page == 1 &&
-						filter.Limit != nil && *filter.Limit == 10
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == 10 && filter.Peek
 				})).Return(products, nil).Once()
+				variantRepo.On("GetVariantSummaries", mock.Anything, mock.Anything, mock.Anything).Return([]entity.ProductVariantSummary{}, nil).Once()
 
 				// Mock count products with nil filter
 				repo.On("CountByFilter", mock.MatchedBy(func(c context.Context) bool {
@@ -676,16 +721,136 @@ func Test_productService_GetProducts(t *testing.T) {
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindProductByFilter) bool {
 					return filter.Name == nil &&
 						len(filter.CategoryIDs) == 0 &&
-						filter.Page != nil && *filter.Page == 1 &&
-						filter.Limit != nil && *filter.Limit == 10
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == 10 && filter.Peek
 				})).Return(int64(len(products)), nil).Once()
 			},
 		},
+		{
+			name: "Get Products Success - Variants",
+			s: &productService{
+				postgresRepo: repository.RepositoryCollections{
+					ProductRepo:        repo_mocks.NewIProductRepository(t),
+					ProductVariantRepo: repo_mocks.NewIProductVariantRepository(t),
+				},
+			},
+			args: args{
+				ctx:     ctx,
+				request: &model.GetProductRequest{},
+			},
+			want: &model.GetProductResponse{
+				Count: &variantCount,
+				Result: []entity.Product{
+					{
+						ID:     testProductID,
+						Status: entity.PRODUCT_STATUS_IN_STOCK,
+						Variants: &entity.ProductVariantSummary{
+							ProductID:    testProductID,
+							VariantCount: 2,
+							MinPrice:     80,
+							MaxPrice:     120,
+							InStock:      true,
+						},
+					},
+				},
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, ctx context.Context) {
+				// The product itself has no stock, one of its variants has
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Product{{ID: testProductID}}, nil).Once()
+				repo.On("CountByFilter", mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+				variantRepo.On("GetVariantSummaries", mock.Anything, mock.Anything, []uuid.UUID{testProductID}).Return([]entity.ProductVariantSummary{
+					{ProductID: testProductID, VariantCount: 2, MinPrice: 80, MaxPrice: 120, InStock: true},
+				}, nil).Once()
+			},
+		},
+		{
+			name: "Get Products Success - Cursor Without Count",
+			s: &productService{
+				postgresRepo: repository.RepositoryCollections{
+					ProductRepo:        repo_mocks.NewIProductRepository(t),
+					ProductVariantRepo: repo_mocks.NewIProductVariantRepository(t),
+				},
+			},
+			args: args{
+				ctx: ctx,
+				request: &model.GetProductRequest{
+					Limit: &testCursorLimit,
+					CursorRequest: model.CursorRequest{
+						Cursor:    (&cursor{ID: testProductID}).encode(),
+						SkipCount: true,
+					},
+				},
+			},
+			want: &model.GetProductResponse{
+				Result: products,
+				CursorResponse: model.CursorResponse{
+					NextCursor: (&cursor{ID: products[1].ID}).encode(),
+					PrevCursor: (&cursor{ID: products[0].ID, Backward: true}).encode(),
+				},
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, ctx context.Context) {
+				// No count, the products after the cursor plus one telling there's a next page
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindProductByFilter) bool {
+					return filter.Keyset != nil && filter.Keyset.ID == testProductID && !filter.Keyset.Backward &&
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == testCursorLimit && filter.Peek
+				})).Return(append(append([]entity.Product{}, products...), entity.Product{}), nil).Once()
+				variantRepo.On("GetVariantSummaries", mock.Anything, mock.Anything, mock.Anything).Return([]entity.ProductVariantSummary{}, nil).Once()
+			},
+		},
+		{
+			name: "Get Products Invalid Cursor",
+			s: &productService{
+				postgresRepo: repository.RepositoryCollections{
+					ProductRepo:        repo_mocks.NewIProductRepository(t),
+					ProductVariantRepo: repo_mocks.NewIProductVariantRepository(t),
+				},
+			},
+			args: args{
+				ctx: ctx,
+				request: &model.GetProductRequest{
+					CursorRequest: model.CursorRequest{
+						Cursor: &testInvalidCursor,
+					},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, ctx context.Context) {
+			},
+		},
+		{
+			name: "Get Products Deleted Cursor",
+			s: &productService{
+				postgresRepo: repository.RepositoryCollections{
+					ProductRepo:        repo_mocks.NewIProductRepository(t),
+					ProductVariantRepo: repo_mocks.NewIProductVariantRepository(t),
+				},
+			},
+			args: args{
+				ctx: ctx,
+				request: &model.GetProductRequest{
+					CursorRequest: model.CursorRequest{
+						Cursor:    (&cursor{ID: testProductID}).encode(),
+						SkipCount: true,
+					},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, ctx context.Context) {
+				// The product of the cursor was deleted since the previous page
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, repository.ErrKeysetNotFound).Once()
+			},
+		},
 		{
 			name: "Get Products Error",
 			s: &productService{
 				postgresRepo: repository.RepositoryCollections{
-					ProductRepo: repo_mocks.NewIProductRepository(t),
+					ProductRepo:        repo_mocks.NewIProductRepository(t),
+					ProductVariantRepo: repo_mocks.NewIProductVariantRepository(t),
 				},
 			},
 			args: args{
@@ -694,7 +859,7 @@ func Test_productService_GetProducts(t *testing.T) {
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IProductRepository, ctx context.Context) {
+			mock: func(repo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, ctx context.Context) {
 				// Mock find products error
 				repo.On("FindManyByFilter", mock.MatchedBy(func(c context.Context) bool {
 					return true
@@ -714,7 +879,7 @@ func Test_productService_GetProducts(t *testing.T) {
 			tt.args.ctx = ctx
 
 			// Setup mocks
-			tt.mock(tt.s.postgresRepo.ProductRepo.(*repo_mocks.IProductRepository), ctx)
+			tt.mock(tt.s.postgresRepo.ProductRepo.(*repo_mocks.IProductRepository), tt.s.postgresRepo.ProductVariantRepo.(*repo_mocks.IProductVariantRepository), ctx)
 
 			got, err := tt.s.GetProducts(tt.args.ctx, tt.args.request)
 			if (err != nil) != tt.wantErr {
@@ -758,3 +923,243 @@ func TestNewProductService(t *testing.T) {
 		})
 	}
 }
+
+func Test_productService_CreateVariant(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.CreateProductVariantRequest
+	}
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(productRepo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, productHelper *helper_mocks.IProductHelper)
+	}
+
+	testUserID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: testUserID,
+	})
+	price := float64(120)
+	req := &model.CreateProductVariantRequest{
+		ProductID: testProductID,
+		Sku:       "SHIRT-M-RED",
+		Options:   map[string]string{"size": "M", "color": "red"},
+		Price:     &price,
+		Quantity:  5,
+	}
+	// The transaction runs the changes straight away
+	inTransaction := func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+		return fn(nil)
+	}
+
+	tests := []testCase{
+		{
+			name:    "Create Variant Success",
+			args:    args{ctx: ctx, req: req},
+			wantErr: false,
+			mock: func(productRepo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", ctx, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.MatchedBy(func(filter *repository.FindProductVariantByFilter) bool {
+					return filter.Sku != nil && *filter.Sku == req.Sku
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+				variantRepo.On("FindManyByFilter", ctx, mock.Anything, mock.Anything).Return([]entity.ProductVariant{
+					{ID: uuid.New(), ProductID: testProductID, Sku: "SHIRT-L-RED", Options: map[string]string{"size": "L", "color": "red"}},
+				}, nil).Once()
+				productRepo.On("Transaction", ctx, mock.Anything).Return(inTransaction).Once()
+				variantRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(variant *entity.ProductVariant) bool {
+					return variant.ProductID == testProductID &&
+						variant.Sku == req.Sku &&
+						*variant.Price == price
+				})).Return(nil).Once()
+				stockMovementRepo.On("Record", ctx, mock.Anything, mock.MatchedBy(func(movement *entity.StockMovement) bool {
+					return movement.ProductID == testProductID &&
+						movement.VariantID != nil &&
+						movement.Type == entity.STOCK_MOVEMENT_RECEIVE &&
+						movement.Quantity == 5 &&
+						movement.ActorID == testUserID
+				})).Run(func(args mock.Arguments) {
+					args.Get(2).(*entity.StockMovement).BalanceAfter = 5
+				}).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Initial Stock Failed",
+			args:    args{ctx: context.Background(), req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeUnauthorized,
+			mock: func(productRepo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", mock.Anything, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+				variantRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.ProductVariant{}, nil).Once()
+
+				// The created variant is rolled back with the failed movement
+				productRepo.On("Transaction", mock.Anything, mock.Anything).Return(inTransaction).Once()
+				variantRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Product Not Found",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeProductNotFound,
+			mock: func(productRepo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", ctx, testProductID).Return(nil, errors.New(errors.ErrCodeProductNotFound)).Once()
+			},
+		},
+		{
+			name:    "SKU Already Exists",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeVariantSkuExisted,
+			mock: func(productRepo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", ctx, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.Anything).Return(&entity.ProductVariant{ID: uuid.New(), Sku: req.Sku}, nil).Once()
+			},
+		},
+		{
+			name:    "Options Already Exist",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeVariantOptionsExisted,
+			mock: func(productRepo *repo_mocks.IProductRepository, variantRepo *repo_mocks.IProductVariantRepository, stockMovementRepo *repo_mocks.IStockMovementRepository, productHelper *helper_mocks.IProductHelper) {
+				productHelper.On("ValidateProductID", ctx, testProductID).Return(&entity.Product{ID: testProductID}, nil).Once()
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+				variantRepo.On("FindManyByFilter", ctx, mock.Anything, mock.Anything).Return([]entity.ProductVariant{
+					{ID: uuid.New(), ProductID: testProductID, Sku: "OTHER", Options: map[string]string{"color": "red", "size": "M"}},
+				}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			productRepo := repo_mocks.NewIProductRepository(t)
+			variantRepo := repo_mocks.NewIProductVariantRepository(t)
+			stockMovementRepo := repo_mocks.NewIStockMovementRepository(t)
+			productHelper := helper_mocks.NewIProductHelper(t)
+
+			// Setup mocks
+			tt.mock(productRepo, variantRepo, stockMovementRepo, productHelper)
+
+			s := &productService{
+				postgresRepo: repository.RepositoryCollections{
+					ProductRepo:        productRepo,
+					ProductVariantRepo: variantRepo,
+					StockMovementRepo:  stockMovementRepo,
+				},
+				helper: helper.HelperCollections{
+					ProductHelper: productHelper,
+				},
+			}
+
+			got, err := s.CreateVariant(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("productService.CreateVariant() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if err.(*errors.CustomError).Code != tt.errCode {
+					t.Errorf("productService.CreateVariant() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+				}
+				return
+			}
+			if got.Variant.Status != entity.PRODUCT_STATUS_IN_STOCK {
+				t.Errorf("productService.CreateVariant() status = %v, want %v", got.Variant.Status, entity.PRODUCT_STATUS_IN_STOCK)
+			}
+		})
+	}
+}
+
+func Test_productService_DeleteVariant(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.DeleteProductVariantRequest
+	}
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(variantRepo *repo_mocks.IProductVariantRepository)
+	}
+
+	ctx := context.Background()
+	variantID := uuid.New()
+	req := &model.DeleteProductVariantRequest{
+		ID: variantID,
+	}
+
+	tests := []testCase{
+		{
+			name:    "Delete Variant Success",
+			args:    args{ctx: ctx, req: req},
+			wantErr: false,
+			mock: func(variantRepo *repo_mocks.IProductVariantRepository) {
+				variant := &entity.ProductVariant{ID: variantID, ProductID: testProductID}
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.Anything).Return(variant, nil).Once()
+				variantRepo.On("Delete", ctx, mock.Anything, variant).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Variant Not Found",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeVariantNotFound,
+			mock: func(variantRepo *repo_mocks.IProductVariantRepository) {
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+		{
+			name:    "Variant Has Stock",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeVariantHasStock,
+			mock: func(variantRepo *repo_mocks.IProductVariantRepository) {
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.Anything).Return(&entity.ProductVariant{
+					ID:       variantID,
+					Quantity: 3,
+				}, nil).Once()
+			},
+		},
+		{
+			name:    "Variant Has Reserved Stock",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeVariantHasStock,
+			mock: func(variantRepo *repo_mocks.IProductVariantRepository) {
+				variantRepo.On("FindOneByFilter", ctx, mock.Anything, mock.Anything).Return(&entity.ProductVariant{
+					ID:       variantID,
+					Reserved: 1,
+				}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			variantRepo := repo_mocks.NewIProductVariantRepository(t)
+
+			// Setup mocks
+			tt.mock(variantRepo)
+
+			s := &productService{
+				postgresRepo: repository.RepositoryCollections{
+					ProductVariantRepo: variantRepo,
+				},
+			}
+
+			_, err := s.DeleteVariant(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("productService.DeleteVariant() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("productService.DeleteVariant() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+		})
+	}
+}
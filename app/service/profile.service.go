@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	"sondth-test_soa/package/errors"
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/utils"
+
+	"github.com/google/uuid"
+)
+
+type profileService struct {
+	postgresRepo repository.RepositoryCollections
+	helper       helper.HelperCollections
+}
+
+func NewProfileService(
+	postgresRepo repository.RepositoryCollections,
+	helper helper.HelperCollections,
+) IProfileService {
+	return &profileService{
+		postgresRepo: postgresRepo,
+		helper:       helper,
+	}
+}
+
+func (s *profileService) GetAddresses(
+	ctx context.Context,
+	req *model.GetAddressesRequest,
+) (*model.GetAddressesResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	addresses, err := s.postgresRepo.AddressRepo.FindManyByFilter(ctx, nil, &repository.FindAddressByFilter{
+		UserID: &user.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.GetAddressesResponse{
+		Addresses: addresses,
+	}, nil
+}
+
+func (s *profileService) CreateAddress(
+	ctx context.Context,
+	req *model.CreateAddressRequest,
+) (*model.CreateAddressResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	count, err := s.postgresRepo.AddressRepo.CountByFilter(ctx, nil, &repository.FindAddressByFilter{
+		UserID: &user.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if count >= entity.MAX_ADDRESSES_PER_USER {
+		return nil, errors.Newf(errors.ErrCodeAddressLimitReached, entity.MAX_ADDRESSES_PER_USER)
+	}
+
+	address := entity.NewAddress(user.ID)
+	address.Label = req.Label
+	address.Recipient = req.Recipient
+	address.Phone = req.Phone
+	address.Line1 = req.Line1
+	address.Line2 = req.Line2
+	address.City = req.City
+	address.State = req.State
+	address.PostalCode = req.PostalCode
+	address.Country = req.Country
+
+	// The first address is the default one, otherwise it only becomes the default on request
+	if count == 0 || req.IsDefault {
+		if err := s.unsetDefaultAddress(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		address.IsDefault = true
+	}
+
+	if err := s.postgresRepo.AddressRepo.Create(ctx, nil, address); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.CreateAddressResponse{
+		Address: *address,
+	}, nil
+}
+
+func (s *profileService) UpdateAddress(
+	ctx context.Context,
+	req *model.UpdateAddressRequest,
+) (*model.UpdateAddressResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	address, err := s.findUserAddress(ctx, user.ID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != nil {
+		address.Label = req.Label
+	}
+	if req.Recipient != nil {
+		address.Recipient = *req.Recipient
+	}
+	if req.Phone != nil {
+		address.Phone = *req.Phone
+	}
+	if req.Line1 != nil {
+		address.Line1 = *req.Line1
+	}
+	if req.Line2 != nil {
+		address.Line2 = req.Line2
+	}
+	if req.City != nil {
+		address.City = *req.City
+	}
+	if req.State != nil {
+		address.State = req.State
+	}
+	if req.PostalCode != nil {
+		address.PostalCode = req.PostalCode
+	}
+	if req.Country != nil {
+		address.Country = *req.Country
+	}
+
+	if err := s.postgresRepo.AddressRepo.Update(ctx, nil, address); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.UpdateAddressResponse{
+		Address: *address,
+	}, nil
+}
+
+func (s *profileService) DeleteAddress(
+	ctx context.Context,
+	req *model.DeleteAddressRequest,
+) (*model.DeleteAddressResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	address, err := s.findUserAddress(ctx, user.ID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.postgresRepo.AddressRepo.Delete(ctx, nil, address); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Keep a default shipping address while the address book isn't empty
+	if address.IsDefault {
+		addresses, err := s.postgresRepo.AddressRepo.FindManyByFilter(ctx, nil, &repository.FindAddressByFilter{
+			UserID: &user.ID,
+		})
+		if err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+		if len(addresses) > 0 {
+			addresses[0].IsDefault = true
+			if err := s.postgresRepo.AddressRepo.Update(ctx, nil, &addresses[0]); err != nil {
+				return nil, errors.New(errors.ErrCodeInternalServerError)
+			}
+		}
+	}
+
+	return &model.DeleteAddressResponse{}, nil
+}
+
+func (s *profileService) SetDefaultAddress(
+	ctx context.Context,
+	req *model.SetDefaultAddressRequest,
+) (*model.SetDefaultAddressResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	address, err := s.findUserAddress(ctx, user.ID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !address.IsDefault {
+		if err := s.unsetDefaultAddress(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		address.IsDefault = true
+		if err := s.postgresRepo.AddressRepo.Update(ctx, nil, address); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+	}
+
+	return &model.SetDefaultAddressResponse{
+		Address: *address,
+	}, nil
+}
+
+func (s *profileService) UploadAvatar(
+	ctx context.Context,
+	req *model.UploadAvatarRequest,
+) (*model.UploadAvatarResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &requestUser.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+
+	key, url, err := s.helper.AvatarHelper.Upload(ctx, user.ID, req.Content)
+	if err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		logger.WithCtx(ctx).Error("UploadAvatar", err)
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	previousKey := user.AvatarKey
+	user.AvatarKey = &key
+	user.AvatarURL = &url
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		s.removeAvatar(ctx, key)
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if previousKey != nil {
+		s.removeAvatar(ctx, *previousKey)
+	}
+
+	return &model.UploadAvatarResponse{
+		AvatarURL: url,
+	}, nil
+}
+
+func (s *profileService) DeleteAvatar(
+	ctx context.Context,
+	req *model.DeleteAvatarRequest,
+) (*model.DeleteAvatarResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &requestUser.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+	if user.AvatarKey == nil {
+		return &model.DeleteAvatarResponse{}, nil
+	}
+
+	key := *user.AvatarKey
+	user.AvatarKey = nil
+	user.AvatarURL = nil
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	s.removeAvatar(ctx, key)
+
+	return &model.DeleteAvatarResponse{}, nil
+}
+
+func (s *profileService) GetPreferences(
+	ctx context.Context,
+	req *model.GetPreferencesRequest,
+) (*model.GetPreferencesResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	preference, err := s.findPreference(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.GetPreferencesResponse{
+		Preference: *preference,
+	}, nil
+}
+
+func (s *profileService) UpdatePreferences(
+	ctx context.Context,
+	req *model.UpdatePreferencesRequest,
+) (*model.UpdatePreferencesResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	preference, err := s.findPreference(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Language != nil {
+		// Only languages of the message catalog can be picked
+		if !errors.IsSupportedLanguage(*req.Language) {
+			return nil, errors.Newf(errors.ErrCodeValidatorFormat, "Language")
+		}
+		preference.Language = *req.Language
+	}
+	if req.Currency != nil {
+		preference.Currency = *req.Currency
+	}
+	if req.NotifyEmail != nil {
+		preference.NotifyEmail = *req.NotifyEmail
+	}
+	if req.NotifySms != nil {
+		preference.NotifySms = *req.NotifySms
+	}
+	if req.NotifyMarketing != nil {
+		preference.NotifyMarketing = *req.NotifyMarketing
+	}
+
+	if err := s.postgresRepo.PreferenceRepo.Save(ctx, nil, preference); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.UpdatePreferencesResponse{
+		Preference: *preference,
+	}, nil
+}
+
+// -------------------------------------------------------------------------------
+// findUserAddress finds an address of the address book of the user, addresses of others are not found
+func (s *profileService) findUserAddress(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entity.Address, error) {
+	address, err := s.postgresRepo.AddressRepo.FindOneByFilter(ctx, nil, &repository.FindAddressByFilter{
+		ID:     &id,
+		UserID: &userID,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeAddressNotFound)
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return address, nil
+}
+
+// unsetDefaultAddress clears the current default, only one address of a user can be the default
+func (s *profileService) unsetDefaultAddress(ctx context.Context, userID uuid.UUID) error {
+	if err := s.postgresRepo.AddressRepo.UnsetDefaultByFilter(ctx, nil, &repository.FindAddressByFilter{
+		UserID: &userID,
+	}); err != nil {
+		return errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return nil
+}
+
+// findPreference returns the stored preferences, or the defaults while the user never changed them
+func (s *profileService) findPreference(ctx context.Context, userID uuid.UUID) (*entity.Preference, error) {
+	preference, err := s.postgresRepo.PreferenceRepo.FindOneByFilter(ctx, nil, &repository.FindPreferenceByFilter{
+		UserID: &userID,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return entity.NewPreference(userID, errors.GetDefaultLanguage()), nil
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return preference, nil
+}
+
+// removeAvatar deletes a replaced avatar, a leftover file is not worth failing the request
+func (s *profileService) removeAvatar(ctx context.Context, key string) {
+	if err := s.helper.AvatarHelper.Remove(ctx, key); err != nil {
+		logger.WithCtx(ctx).Error("removeAvatar", err)
+	}
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	helper_mocks "sondth-test_soa/mocks/helper"
+	repo_mocks "sondth-test_soa/mocks/repository"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_profileService_CreateAddress(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.CreateAddressRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(addressRepo *repo_mocks.IAddressRepository)
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+	req := &model.CreateAddressRequest{
+		Recipient: "Nguyen Van A",
+		Phone:     "0912345678",
+		Line1:     "1 Trang Tien",
+		City:      "Hanoi",
+		Country:   "VN",
+	}
+
+	tests := []testCase{
+		{
+			name: "First Address Is Default",
+			args: args{
+				ctx: ctx,
+				req: req,
+			},
+			wantErr: false,
+			mock: func(addressRepo *repo_mocks.IAddressRepository) {
+				addressRepo.On("CountByFilter", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+				addressRepo.On("UnsetDefaultByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindAddressByFilter) bool {
+					return *filter.UserID == userID
+				})).Return(nil).Once()
+				addressRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(address *entity.Address) bool {
+					return address.UserID == userID && address.IsDefault && address.Country == "VN"
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Other Address Is Not Default",
+			args: args{
+				ctx: ctx,
+				req: req,
+			},
+			wantErr: false,
+			mock: func(addressRepo *repo_mocks.IAddressRepository) {
+				addressRepo.On("CountByFilter", mock.Anything, mock.Anything, mock.Anything).Return(int64(2), nil).Once()
+				addressRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(address *entity.Address) bool {
+					return !address.IsDefault
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Address Limit Reached",
+			args: args{
+				ctx: ctx,
+				req: req,
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeAddressLimitReached,
+			mock: func(addressRepo *repo_mocks.IAddressRepository) {
+				addressRepo.On("CountByFilter", mock.Anything, mock.Anything, mock.Anything).Return(int64(entity.MAX_ADDRESSES_PER_USER), nil).Once()
+			},
+		},
+		{
+			name: "Unauthorized",
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUnauthorized,
+			mock:    func(addressRepo *repo_mocks.IAddressRepository) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			addressRepo := repo_mocks.NewIAddressRepository(t)
+
+			// Setup mocks
+			tt.mock(addressRepo)
+
+			s := &profileService{
+				postgresRepo: repository.RepositoryCollections{
+					AddressRepo: addressRepo,
+				},
+			}
+
+			_, err := s.CreateAddress(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("profileService.CreateAddress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("profileService.CreateAddress() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+		})
+	}
+}
+
+func Test_profileService_DeleteAddress(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.DeleteAddressRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(addressRepo *repo_mocks.IAddressRepository)
+	}
+
+	userID := uuid.New()
+	addressID := uuid.New()
+	remainingID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+
+	tests := []testCase{
+		{
+			name: "Default Moves To Remaining Address",
+			args: args{
+				ctx: ctx,
+				req: &model.DeleteAddressRequest{ID: addressID},
+			},
+			wantErr: false,
+			mock: func(addressRepo *repo_mocks.IAddressRepository) {
+				addressRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindAddressByFilter) bool {
+					return *filter.ID == addressID && *filter.UserID == userID
+				})).Return(&entity.Address{ID: addressID, UserID: userID, IsDefault: true}, nil).Once()
+				addressRepo.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				addressRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Address{
+					{ID: remainingID, UserID: userID},
+				}, nil).Once()
+				addressRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(address *entity.Address) bool {
+					return address.ID == remainingID && address.IsDefault
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Delete Other Address",
+			args: args{
+				ctx: ctx,
+				req: &model.DeleteAddressRequest{ID: addressID},
+			},
+			wantErr: false,
+			mock: func(addressRepo *repo_mocks.IAddressRepository) {
+				addressRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Address{ID: addressID, UserID: userID}, nil).Once()
+				addressRepo.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name: "Address Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.DeleteAddressRequest{ID: addressID},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeAddressNotFound,
+			mock: func(addressRepo *repo_mocks.IAddressRepository) {
+				addressRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			addressRepo := repo_mocks.NewIAddressRepository(t)
+
+			// Setup mocks
+			tt.mock(addressRepo)
+
+			s := &profileService{
+				postgresRepo: repository.RepositoryCollections{
+					AddressRepo: addressRepo,
+				},
+			}
+
+			_, err := s.DeleteAddress(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("profileService.DeleteAddress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("profileService.DeleteAddress() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+		})
+	}
+}
+
+func Test_profileService_UploadAvatar(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.UploadAvatarRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(userRepo *repo_mocks.IUserRepository, avatarHelper *helper_mocks.IAvatarHelper)
+	}
+
+	userID := uuid.New()
+	previousKey := "avatars/previous.png"
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+	content := []byte("image")
+
+	tests := []testCase{
+		{
+			name: "Replace Avatar",
+			args: args{
+				ctx: ctx,
+				req: &model.UploadAvatarRequest{Content: content},
+			},
+			wantErr: false,
+			mock: func(userRepo *repo_mocks.IUserRepository, avatarHelper *helper_mocks.IAvatarHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, AvatarKey: &previousKey}, nil).Once()
+				avatarHelper.On("Upload", mock.Anything, userID, content).Return("avatars/new.png", "/uploads/avatars/new.png", nil).Once()
+				userRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return *user.AvatarKey == "avatars/new.png" && *user.AvatarURL == "/uploads/avatars/new.png"
+				})).Return(nil).Once()
+
+				// The replaced avatar is removed
+				avatarHelper.On("Remove", mock.Anything, previousKey).Return(nil).Once()
+			},
+		},
+		{
+			name: "Invalid Avatar",
+			args: args{
+				ctx: ctx,
+				req: &model.UploadAvatarRequest{Content: content},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeAvatarInvalid,
+			mock: func(userRepo *repo_mocks.IUserRepository, avatarHelper *helper_mocks.IAvatarHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID}, nil).Once()
+				avatarHelper.On("Upload", mock.Anything, userID, content).Return("", "", errors.New(errors.ErrCodeAvatarInvalid)).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			userRepo := repo_mocks.NewIUserRepository(t)
+			avatarHelper := helper_mocks.NewIAvatarHelper(t)
+
+			// Setup mocks
+			tt.mock(userRepo, avatarHelper)
+
+			s := &profileService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: userRepo,
+				},
+				helper: helper.HelperCollections{
+					AvatarHelper: avatarHelper,
+				},
+			}
+
+			_, err := s.UploadAvatar(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("profileService.UploadAvatar() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("profileService.UploadAvatar() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+		})
+	}
+}
+
+func Test_profileService_UpdatePreferences(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+	language := "en"
+	notifyMarketing := true
+
+	// Initialize mocks
+	preferenceRepo := repo_mocks.NewIPreferenceRepository(t)
+
+	// Users who never changed their preferences start from the defaults
+	preferenceRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+	preferenceRepo.On("Save", mock.Anything, mock.Anything, mock.MatchedBy(func(preference *entity.Preference) bool {
+		return preference.UserID == userID &&
+			preference.Language == language &&
+			preference.Currency == entity.DEFAULT_CURRENCY &&
+			preference.NotifyEmail &&
+			preference.NotifyMarketing
+	})).Return(nil).Once()
+
+	s := &profileService{
+		postgresRepo: repository.RepositoryCollections{
+			PreferenceRepo: preferenceRepo,
+		},
+	}
+
+	res, err := s.UpdatePreferences(ctx, &model.UpdatePreferencesRequest{
+		Language:        &language,
+		NotifyMarketing: &notifyMarketing,
+	})
+	if err != nil {
+		t.Fatalf("profileService.UpdatePreferences() error = %v", err)
+	}
+	if res.Preference.Language != language {
+		t.Errorf("profileService.UpdatePreferences() language = %v, want %v", res.Preference.Language, language)
+	}
+}
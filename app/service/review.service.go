@@ -10,6 +10,7 @@ import (
 	"sondth-test_soa/package/errors"
 	"sondth-test_soa/utils"
 
+	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -100,10 +101,19 @@ func (s *reviewService) GetReviews(
 	ctx context.Context,
 	req *model.GetReviewsRequest,
 ) (*model.GetReviewsResponse, error) {
+	page, err := newListPage(req.CursorRequest, req.Sort, repository.ReviewSortFields, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := &repository.FindReviewByFilter{
 		ProductName: req.ProductName,
-		Page:        req.Page,
-		Limit:       req.Limit,
+		Page:        page.Page,
+		Limit:       page.Limit,
+		Peek:        page.Peek,
+		Sort:        page.Sort,
+		Keyset:      page.Keyset,
+		UserFields:  []string{"id", "username", "fullname", "status"},
 	}
 
 	errGroup, errCtx := errgroup.WithContext(ctx)
@@ -113,28 +123,38 @@ func (s *reviewService) GetReviews(
 		var err error
 		reviews, err = s.postgresRepo.ReviewRepo.FindManyByFilter(errCtx, nil, filter)
 		if err != nil {
-			return err
+			return keysetError(err)
 		}
 		return nil
 	})
 
-	var count int64
-	errGroup.Go(func() error {
-		var err error
-		count, err = s.postgresRepo.ReviewRepo.CountByFilter(errCtx, nil, filter)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
+	var count *int64
+	if !req.SkipCount {
+		errGroup.Go(func() error {
+			total, err := s.postgresRepo.ReviewRepo.CountByFilter(errCtx, nil, filter)
+			if err != nil {
+				return err
+			}
+			count = &total
+			return nil
+		})
+	}
 
 	if err := errGroup.Wait(); err != nil {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
 
+	reviews, cursors := cutPage(page, reviews, func(review entity.Review) uuid.UUID { return review.ID })
+
+	// Reviews of deleted users stay, without their author
+	for i := range reviews {
+		reviews[i].AnonymizeDeletedAuthor()
+	}
+
 	return &model.GetReviewsResponse{
-		Reviews: reviews,
-		Count:   count,
+		Reviews:        reviews,
+		Count:          count,
+		CursorResponse: cursors,
 	}, nil
 }
 
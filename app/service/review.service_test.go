@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"reflect"
+	"slices"
 	"testing"
 
 	"sondth-test_soa/app/entity"
@@ -295,6 +297,7 @@ func Test_reviewService_GetReviews(t *testing.T) {
 		},
 	}
 
+	count := int64(1)
 	tests := []testCase{
 		{
 			name: "Get Reviews Success",
@@ -308,7 +311,7 @@ func Test_reviewService_GetReviews(t *testing.T) {
 			},
 			want: &model.GetReviewsResponse{
 				Reviews: reviews,
-				Count:   1,
+				Count:   &count,
 			},
 			wantErr: false,
 			mock: func(repo *repo_mocks.IReviewRepository) {
@@ -317,8 +320,8 @@ func Test_reviewService_GetReviews(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindReviewByFilter) bool {
 					return filter.ProductName != nil && *filter.ProductName == productName &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(reviews, nil).Once()
 
 				// Mock count reviews
@@ -326,11 +329,58 @@ func Test_reviewService_GetReviews(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindReviewByFilter) bool {
 					return filter.ProductName != nil && *filter.ProductName == productName &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(int64(1), nil).Once()
 			},
 		},
+		{
+			name: "Get Reviews Anonymizes Deleted Author",
+			args: args{
+				ctx: ctx,
+				req: &model.GetReviewsRequest{},
+			},
+			want: &model.GetReviewsResponse{
+				Reviews: []entity.Review{
+					{
+						ID:        reviewID,
+						UserID:    uuid.Nil,
+						ProductID: productID,
+						Rating:    rating,
+						Comment:   comment,
+						User: entity.User{
+							Username: entity.ANONYMIZED_USERNAME,
+							Fullname: entity.ANONYMIZED_FULLNAME,
+							Status:   entity.USER_STATUS_DELETED,
+						},
+					},
+				},
+				Count: &count,
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IReviewRepository) {
+				// The author is loaded with its status
+				isWithAuthor := mock.MatchedBy(func(filter *repository.FindReviewByFilter) bool {
+					return slices.Contains(filter.UserFields, "status")
+				})
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, isWithAuthor).Return([]entity.Review{
+					{
+						ID:        reviewID,
+						UserID:    userID,
+						ProductID: productID,
+						Rating:    rating,
+						Comment:   comment,
+						User: entity.User{
+							ID:       userID,
+							Username: "deleted-author",
+							Fullname: "Deleted Author",
+							Status:   entity.USER_STATUS_DELETED,
+						},
+					},
+				}, nil).Once()
+				repo.On("CountByFilter", mock.Anything, mock.Anything, isWithAuthor).Return(int64(1), nil).Once()
+			},
+		},
 		{
 			name: "Get Reviews Failed - Find Error",
 			args: args{
@@ -349,8 +399,8 @@ func Test_reviewService_GetReviews(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindReviewByFilter) bool {
 					return filter.ProductName != nil && *filter.ProductName == productName &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(nil, errors.New(errors.ErrCodeInternalServerError)).Once()
 
 				// Mock count reviews
@@ -358,8 +408,8 @@ func Test_reviewService_GetReviews(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindReviewByFilter) bool {
 					return filter.ProductName != nil && *filter.ProductName == productName &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(int64(0), errors.New(errors.ErrCodeInternalServerError)).Once()
 			},
 		},
@@ -381,8 +431,8 @@ func Test_reviewService_GetReviews(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindReviewByFilter) bool {
 					return filter.ProductName != nil && *filter.ProductName == productName &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(reviews, nil).Once()
 
 				// Mock count reviews error
@@ -390,8 +440,8 @@ func Test_reviewService_GetReviews(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindReviewByFilter) bool {
 					return filter.ProductName != nil && *filter.ProductName == productName &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(int64(0), errors.New(errors.ErrCodeInternalServerError)).Once()
 			},
 		},
@@ -419,6 +469,9 @@ func Test_reviewService_GetReviews(t *testing.T) {
 			if err == nil && got == nil {
 				t.Error("reviewService.GetReviews() got nil response, want non-nil")
 			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reviewService.GetReviews() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
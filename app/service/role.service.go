@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+type roleService struct {
+	postgresRepo repository.RepositoryCollections
+	helper       helper.HelperCollections
+}
+
+func NewRoleService(
+	postgresRepo repository.RepositoryCollections,
+	helper helper.HelperCollections,
+) IRoleService {
+	return &roleService{
+		postgresRepo: postgresRepo,
+		helper:       helper,
+	}
+}
+
+func (s *roleService) Create(
+	ctx context.Context,
+	req *model.CreateRoleRequest,
+) (*model.CreateRoleResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	// Check role name
+	existedRole, err := s.postgresRepo.RoleRepo.FindOneByFilter(ctx, nil, &repository.FindRoleByFilter{
+		Name: &req.Name,
+		Filter: repository.Filter{
+			Fields: []string{"id"},
+		},
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if existedRole != nil {
+		return nil, errors.New(errors.ErrCodeRoleExisted)
+	}
+
+	permissions, err := s.findPermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGrantable(ctx, requestUser, permissions); err != nil {
+		return nil, err
+	}
+
+	role := entity.NewRole()
+	role.Name = req.Name
+	role.Description = req.Description
+	role.Permissions = permissions
+	if err := s.postgresRepo.RoleRepo.Create(ctx, nil, role); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.CreateRoleResponse{
+		Role: *role,
+	}, nil
+}
+
+func (s *roleService) Update(
+	ctx context.Context,
+	req *model.UpdateRoleRequest,
+) (*model.UpdateRoleResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	role, err := s.findRole(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == entity.ROLE_ADMIN && !requestUser.IsAdmin() {
+		return nil, errors.New(errors.ErrCodeAdminRoleChangeDenied)
+	}
+
+	permissions, err := s.findPermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGrantable(ctx, requestUser, permissions); err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		role.Description = req.Description
+	}
+	// The role is never left without its permissions when replacing them fails
+	if err := s.postgresRepo.RoleRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.postgresRepo.RoleRepo.Update(ctx, tx, role); err != nil {
+			return err
+		}
+		if req.Permissions != nil {
+			return s.postgresRepo.RoleRepo.ReplacePermissions(ctx, tx, role, permissions)
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if req.Permissions != nil {
+		role.Permissions = permissions
+		if err := s.revokeRoleHolderSessions(ctx, role.ID); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+	}
+
+	return &model.UpdateRoleResponse{
+		Role: *role,
+	}, nil
+}
+
+func (s *roleService) Delete(
+	ctx context.Context,
+	req *model.DeleteRoleRequest,
+) (*model.DeleteRoleResponse, error) {
+	role, err := s.findRole(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if role.IsBuiltIn() {
+		return nil, errors.New(errors.ErrCodeRoleBuiltIn)
+	}
+
+	if err := s.postgresRepo.RoleRepo.Delete(ctx, nil, role); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.DeleteRoleResponse{}, nil
+}
+
+func (s *roleService) GetRoles(
+	ctx context.Context,
+	req *model.GetRolesRequest,
+) (*model.GetRolesResponse, error) {
+	filter := &repository.FindRoleByFilter{
+		Page:             req.Page,
+		Limit:            req.Limit,
+		PermissionFields: []string{"id", "code"},
+	}
+
+	errGroup, errCtx := errgroup.WithContext(ctx)
+
+	var roles []entity.Role
+	errGroup.Go(func() error {
+		var err error
+		roles, err = s.postgresRepo.RoleRepo.FindManyByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	var count int64
+	errGroup.Go(func() error {
+		var err error
+		count, err = s.postgresRepo.RoleRepo.CountByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if err := errGroup.Wait(); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.GetRolesResponse{
+		Roles: roles,
+		Count: count,
+	}, nil
+}
+
+func (s *roleService) GetPermissions(
+	ctx context.Context,
+	req *model.GetPermissionsRequest,
+) (*model.GetPermissionsResponse, error) {
+	permissions, err := s.postgresRepo.PermissionRepo.FindManyByFilter(ctx, nil, &repository.FindPermissionByFilter{})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.GetPermissionsResponse{
+		Permissions: permissions,
+	}, nil
+}
+
+func (s *roleService) AssignRole(
+	ctx context.Context,
+	req *model.AssignRoleRequest,
+) (*model.AssignRoleResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	if req.UserID == requestUser.ID {
+		return nil, errors.New(errors.ErrCodeCannotChangeOwnRole)
+	}
+
+	// Check user
+	user, err := s.findUser(ctx, requestUser, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check role
+	role, err := s.findRole(ctx, req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == entity.ROLE_ADMIN && !requestUser.IsAdmin() {
+		return nil, errors.New(errors.ErrCodeAdminRoleForbidden)
+	}
+	if err := s.checkGrantable(ctx, requestUser, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	// Check existing assignment
+	assignedRole, err := s.postgresRepo.RoleRepo.FindOneByFilter(ctx, nil, &repository.FindRoleByFilter{
+		ID:     &req.RoleID,
+		UserID: &req.UserID,
+		Filter: repository.Filter{
+			Fields: []string{"id"},
+		},
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if assignedRole != nil {
+		return nil, errors.New(errors.ErrCodeRoleAlreadyAssigned)
+	}
+
+	if err := s.postgresRepo.RoleRepo.AssignToUser(ctx, nil, entity.NewUserRole(user.ID, req.RoleID)); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := revokeUserSessions(ctx, s.postgresRepo, s.helper, user.ID); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.AssignRoleResponse{}, nil
+}
+
+func (s *roleService) UnassignRole(
+	ctx context.Context,
+	req *model.UnassignRoleRequest,
+) (*model.UnassignRoleResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	if _, err := s.findUser(ctx, requestUser, req.UserID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.postgresRepo.RoleRepo.FindOneByFilter(ctx, nil, &repository.FindRoleByFilter{
+		ID:     &req.RoleID,
+		UserID: &req.UserID,
+		Filter: repository.Filter{
+			Fields: []string{"id"},
+		},
+	}); err != nil {
+		return nil, errors.New(errors.ErrCodeRoleNotFound)
+	}
+
+	if err := s.postgresRepo.RoleRepo.UnassignFromUser(ctx, nil, &entity.UserRole{
+		UserID: req.UserID,
+		RoleID: req.RoleID,
+	}); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := revokeUserSessions(ctx, s.postgresRepo, s.helper, req.UserID); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.UnassignRoleResponse{}, nil
+}
+
+// -------------------------------------------------------------------------------
+func (s *roleService) findRole(ctx context.Context, roleID uuid.UUID) (*entity.Role, error) {
+	role, err := s.postgresRepo.RoleRepo.FindOneByFilter(ctx, nil, &repository.FindRoleByFilter{
+		ID:               &roleID,
+		PermissionFields: []string{"id", "code"},
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeRoleNotFound)
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return role, nil
+}
+
+// findUser finds the user whose roles change, who can't have more privileges than the request user
+func (s *roleService) findUser(ctx context.Context, requestUser *entity.User, userID uuid.UUID) (*entity.User, error) {
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &userID,
+		Filter: repository.Filter{
+			Fields: []string{"id", "role"},
+		},
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+	if err := checkUserPrivileges(ctx, s.helper, requestUser, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *roleService) findPermissions(ctx context.Context, codes []string) ([]entity.Permission, error) {
+	if len(codes) == 0 {
+		return []entity.Permission{}, nil
+	}
+	codes = utils.Unique(codes)
+
+	permissions, err := s.postgresRepo.PermissionRepo.FindManyByFilter(ctx, nil, &repository.FindPermissionByFilter{
+		Codes: codes,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if len(permissions) != len(codes) {
+		return nil, errors.New(errors.ErrCodePermissionNotFound)
+	}
+
+	return permissions, nil
+}
+
+// checkGrantable makes sure a role never gives more than the request user can do, the same as API keys
+func (s *roleService) checkGrantable(ctx context.Context, requestUser *entity.User, permissions []entity.Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	granted, err := s.helper.UserHelper.GetPermissions(ctx, requestUser)
+	if err != nil {
+		return errors.New(errors.ErrCodeInternalServerError)
+	}
+	for _, permission := range permissions {
+		if !slices.Contains(granted, permission.Code) {
+			return errors.Newf(errors.ErrCodeRolePermissionNotHeld, permission.Code)
+		}
+	}
+
+	return nil
+}
+
+// revokeRoleHolderSessions signs out every user holding the role, their tokens were issued with its former permissions
+func (s *roleService) revokeRoleHolderSessions(ctx context.Context, roleID uuid.UUID) error {
+	users, err := s.postgresRepo.UserRepo.FindManyByFilter(ctx, nil, &repository.FindUserByFilter{
+		Filter: repository.Filter{
+			Fields: []string{"id"},
+		},
+		RoleID: &roleID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := revokeUserSessions(ctx, s.postgresRepo, s.helper, user.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
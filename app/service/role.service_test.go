@@ -0,0 +1,730 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"sondth-test_soa/app/entity"
+	"sondth-test_soa/app/helper"
+	"sondth-test_soa/app/model"
+	"sondth-test_soa/app/repository"
+	helper_mocks "sondth-test_soa/mocks/helper"
+	repo_mocks "sondth-test_soa/mocks/repository"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var (
+	testRoleID   = uuid.New()
+	testRoleName = "moderator"
+)
+
+func Test_roleService_Create(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.CreateRoleRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.CreateRoleResponse
+		wantErr bool
+		errCode int
+		mock    func(roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, userHelper *helper_mocks.IUserHelper)
+	}
+
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+	permissions := []entity.Permission{
+		{ID: uuid.New(), Code: entity.PERMISSION_REVIEW_MODERATE},
+	}
+
+	tests := []testCase{
+		{
+			name: "Create Role Success",
+			args: args{
+				ctx: ctx,
+				req: &model.CreateRoleRequest{
+					Name:        testRoleName,
+					Permissions: []string{entity.PERMISSION_REVIEW_MODERATE, entity.PERMISSION_REVIEW_MODERATE},
+				},
+			},
+			want:    &model.CreateRoleResponse{},
+			wantErr: false,
+			mock: func(roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, userHelper *helper_mocks.IUserHelper) {
+				// Mock role name check
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindRoleByFilter) bool {
+					return filter.Name != nil && *filter.Name == testRoleName
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+
+				// Mock permission lookup, duplicated codes are ignored
+				permissionRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindPermissionByFilter) bool {
+					return len(filter.Codes) == 1 && filter.Codes[0] == entity.PERMISSION_REVIEW_MODERATE
+				})).Return(permissions, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{entity.PERMISSION_ROLE_MANAGE, entity.PERMISSION_REVIEW_MODERATE}, nil).Once()
+
+				// Mock role creation
+				roleRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(role *entity.Role) bool {
+					return role.Name == testRoleName && len(role.Permissions) == 1
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Create Role Failed - Role Exists",
+			args: args{
+				ctx: ctx,
+				req: &model.CreateRoleRequest{
+					Name: testRoleName,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, userHelper *helper_mocks.IUserHelper) {
+				// Mock role exists
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindRoleByFilter) bool {
+					return filter.Name != nil && *filter.Name == testRoleName
+				})).Return(&entity.Role{ID: testRoleID}, nil).Once()
+			},
+		},
+		{
+			name: "Create Role Failed - Permission Not Held",
+			args: args{
+				ctx: ctx,
+				req: &model.CreateRoleRequest{
+					Name:        testRoleName,
+					Permissions: []string{entity.PERMISSION_REVIEW_MODERATE},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeRolePermissionNotHeld,
+			mock: func(roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, userHelper *helper_mocks.IUserHelper) {
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+				permissionRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(permissions, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{entity.PERMISSION_ROLE_MANAGE}, nil).Once()
+			},
+		},
+		{
+			name: "Create Role Failed - Unknown Permission",
+			args: args{
+				ctx: ctx,
+				req: &model.CreateRoleRequest{
+					Name:        testRoleName,
+					Permissions: []string{"unknown:write"},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, userHelper *helper_mocks.IUserHelper) {
+				// Mock role name check
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindRoleByFilter) bool {
+					return filter.Name != nil && *filter.Name == testRoleName
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+
+				// Mock permission not found
+				permissionRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Permission{}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			roleRepo := repo_mocks.NewIRoleRepository(t)
+			permissionRepo := repo_mocks.NewIPermissionRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+
+			// Setup mocks
+			tt.mock(roleRepo, permissionRepo, userHelper)
+
+			s := &roleService{
+				postgresRepo: repository.RepositoryCollections{
+					RoleRepo:       roleRepo,
+					PermissionRepo: permissionRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper: userHelper,
+				},
+			}
+
+			got, err := s.Create(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("roleService.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errCode != 0 && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("roleService.Create() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+			if err == nil && got == nil {
+				t.Error("roleService.Create() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_roleService_Delete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.DeleteRoleRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.DeleteRoleResponse
+		wantErr bool
+		mock    func(roleRepo *repo_mocks.IRoleRepository)
+	}
+
+	ctx := context.Background()
+
+	tests := []testCase{
+		{
+			name: "Delete Role Success",
+			args: args{
+				ctx: ctx,
+				req: &model.DeleteRoleRequest{
+					ID: testRoleID,
+				},
+			},
+			want:    &model.DeleteRoleResponse{},
+			wantErr: false,
+			mock: func(roleRepo *repo_mocks.IRoleRepository) {
+				role := &entity.Role{ID: testRoleID, Name: testRoleName}
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindRoleByFilter) bool {
+					return filter.ID != nil && *filter.ID == testRoleID
+				})).Return(role, nil).Once()
+
+				roleRepo.On("Delete", mock.Anything, mock.Anything, role).Return(nil).Once()
+			},
+		},
+		{
+			name: "Delete Role Failed - Built In Role",
+			args: args{
+				ctx: ctx,
+				req: &model.DeleteRoleRequest{
+					ID: testRoleID,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(roleRepo *repo_mocks.IRoleRepository) {
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindRoleByFilter) bool {
+					return filter.ID != nil && *filter.ID == testRoleID
+				})).Return(&entity.Role{ID: testRoleID, Name: entity.ROLE_ADMIN}, nil).Once()
+			},
+		},
+		{
+			name: "Delete Role Failed - Role Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.DeleteRoleRequest{
+					ID: testRoleID,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(roleRepo *repo_mocks.IRoleRepository) {
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			roleRepo := repo_mocks.NewIRoleRepository(t)
+
+			// Setup mocks
+			tt.mock(roleRepo)
+
+			s := &roleService{
+				postgresRepo: repository.RepositoryCollections{
+					RoleRepo: roleRepo,
+				},
+			}
+
+			got, err := s.Delete(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("roleService.Delete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got == nil {
+				t.Error("roleService.Delete() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_roleService_AssignRole(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.AssignRoleRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.AssignRoleResponse
+		wantErr bool
+		errCode int
+		mock    func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper)
+	}
+
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+	req := &model.AssignRoleRequest{
+		UserID: userID,
+		RoleID: testRoleID,
+	}
+	role := &entity.Role{
+		ID:          testRoleID,
+		Name:        testRoleName,
+		Permissions: []entity.Permission{{ID: uuid.New(), Code: entity.PERMISSION_REVIEW_MODERATE}},
+	}
+	staffPermissions := []string{entity.PERMISSION_ROLE_MANAGE, entity.PERMISSION_REVIEW_MODERATE}
+
+	tests := []testCase{
+		{
+			name:    "Assign Role Success",
+			args:    args{ctx: ctx, req: req},
+			want:    &model.AssignRoleResponse{},
+			wantErr: false,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				// Mock find user, a customer
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(&entity.User{ID: userID, Role: entity.ROLE_USER}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+
+				// Mock find role, its permissions are held by the request user
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindRoleByFilter) bool {
+					return filter.ID != nil && *filter.ID == testRoleID && filter.UserID == nil
+				})).Return(role, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return(staffPermissions, nil).Once()
+
+				// Mock no existing assignment
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindRoleByFilter) bool {
+					return filter.ID != nil && *filter.ID == testRoleID && filter.UserID != nil && *filter.UserID == userID
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+
+				// Mock assignment
+				roleRepo.On("AssignToUser", mock.Anything, mock.Anything, mock.MatchedBy(func(userRole *entity.UserRole) bool {
+					return userRole.UserID == userID && userRole.RoleID == testRoleID
+				})).Return(nil).Once()
+
+				// Mock sign out of the user
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindSessionByFilter) bool {
+					return filter.UserID != nil && *filter.UserID == userID
+				})).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Assign Role Failed - Already Assigned",
+			args:    args{ctx: ctx, req: req},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeRoleAlreadyAssigned,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				// Mock find user
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Role: entity.ROLE_USER}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+
+				// Mock find role
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindRoleByFilter) bool {
+					return filter.UserID == nil
+				})).Return(role, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return(staffPermissions, nil).Once()
+
+				// Mock existing assignment
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindRoleByFilter) bool {
+					return filter.UserID != nil
+				})).Return(&entity.Role{ID: testRoleID}, nil).Once()
+			},
+		},
+		{
+			name:    "Assign Role Failed - User Not Found",
+			args:    args{ctx: ctx, req: req},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeUserNotFound,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				// Mock user not found
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+		{
+			name: "Assign Role Failed - Own Account",
+			args: args{
+				ctx: ctx,
+				req: &model.AssignRoleRequest{
+					UserID: staff.ID,
+					RoleID: testRoleID,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeCannotChangeOwnRole,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+			},
+		},
+		{
+			name:    "Assign Role Failed - Admin Role By Staff",
+			args:    args{ctx: ctx, req: req},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeAdminRoleForbidden,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Role: entity.ROLE_USER}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Role{ID: testRoleID, Name: entity.ROLE_ADMIN}, nil).Once()
+			},
+		},
+		{
+			name:    "Assign Role Failed - Permission Not Held",
+			args:    args{ctx: ctx, req: req},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeRolePermissionNotHeld,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Role: entity.ROLE_USER}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(role, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{entity.PERMISSION_ROLE_MANAGE}, nil).Once()
+			},
+		},
+		{
+			name:    "Assign Role Failed - Admin Target",
+			args:    args{ctx: ctx, req: req},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Role: entity.ROLE_ADMIN}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			userRepo := repo_mocks.NewIUserRepository(t)
+			roleRepo := repo_mocks.NewIRoleRepository(t)
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(userRepo, roleRepo, sessionRepo, userHelper, oauthHelper)
+
+			s := &roleService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:    userRepo,
+					RoleRepo:    roleRepo,
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:  userHelper,
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			got, err := s.AssignRole(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("roleService.AssignRole() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errCode != 0 && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("roleService.AssignRole() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+			if err == nil && got == nil {
+				t.Error("roleService.AssignRole() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_roleService_UnassignRole(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.UnassignRoleRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper)
+	}
+
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+
+	tests := []testCase{
+		{
+			name: "Unassign Role Success",
+			args: args{
+				ctx: ctx,
+				req: &model.UnassignRoleRequest{
+					UserID: userID,
+					RoleID: testRoleID,
+				},
+			},
+			wantErr: false,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Role: entity.ROLE_USER}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Role{ID: testRoleID}, nil).Once()
+				roleRepo.On("UnassignFromUser", mock.Anything, mock.Anything, mock.MatchedBy(func(userRole *entity.UserRole) bool {
+					return userRole.UserID == userID && userRole.RoleID == testRoleID
+				})).Return(nil).Once()
+
+				// The permissions of the role stop working right away
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindSessionByFilter) bool {
+					return filter.UserID != nil && *filter.UserID == userID
+				})).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Unassign Role Failed - Not Assigned",
+			args: args{
+				ctx: ctx,
+				req: &model.UnassignRoleRequest{
+					UserID: userID,
+					RoleID: testRoleID,
+				},
+			},
+			wantErr: true,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Role: entity.ROLE_USER}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+		{
+			name: "Unassign Role Failed - More Privileged User",
+			args: args{
+				ctx: ctx,
+				req: &model.UnassignRoleRequest{
+					UserID: userID,
+					RoleID: testRoleID,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Role: "manager"}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{entity.PERMISSION_USER_IMPERSONATE}, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{entity.PERMISSION_ROLE_MANAGE}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			userRepo := repo_mocks.NewIUserRepository(t)
+			roleRepo := repo_mocks.NewIRoleRepository(t)
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(userRepo, roleRepo, sessionRepo, userHelper, oauthHelper)
+
+			s := &roleService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:    userRepo,
+					RoleRepo:    roleRepo,
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:  userHelper,
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			got, err := s.UnassignRole(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("roleService.UnassignRole() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errCode != 0 && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("roleService.UnassignRole() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+			if err == nil && got == nil {
+				t.Error("roleService.UnassignRole() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_roleService_Update(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.UpdateRoleRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper)
+	}
+
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+	holderID := uuid.New()
+	permissions := []entity.Permission{{ID: uuid.New(), Code: entity.PERMISSION_PRODUCT_WRITE}}
+	// The transaction runs the changes straight away
+	inTransaction := func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+		return fn(nil)
+	}
+
+	tests := []testCase{
+		{
+			name: "Update Role Success - Permissions Replaced",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateRoleRequest{
+					ID:          testRoleID,
+					Permissions: []string{entity.PERMISSION_PRODUCT_WRITE},
+				},
+			},
+			wantErr: false,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Role{ID: testRoleID, Name: testRoleName}, nil).Once()
+				permissionRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(permissions, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{entity.PERMISSION_ROLE_MANAGE, entity.PERMISSION_PRODUCT_WRITE}, nil).Once()
+				roleRepo.On("Transaction", mock.Anything, mock.Anything).Return(inTransaction).Once()
+				roleRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				roleRepo.On("ReplacePermissions", mock.Anything, mock.Anything, mock.Anything, permissions).Return(nil).Once()
+
+				// Every holder of the role is signed out
+				userRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.RoleID != nil && *filter.RoleID == testRoleID
+				})).Return([]entity.User{{ID: holderID}}, nil).Once()
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindSessionByFilter) bool {
+					return filter.UserID != nil && *filter.UserID == holderID
+				})).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, holderID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Update Role Success - Description Only",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateRoleRequest{
+					ID:          testRoleID,
+					Description: &testRoleName,
+				},
+			},
+			wantErr: false,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Role{ID: testRoleID, Name: testRoleName}, nil).Once()
+				roleRepo.On("Transaction", mock.Anything, mock.Anything).Return(inTransaction).Once()
+				roleRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name: "Update Role Failed - Replace Permissions Error",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateRoleRequest{
+					ID:          testRoleID,
+					Permissions: []string{entity.PERMISSION_PRODUCT_WRITE},
+				},
+			},
+			wantErr: true,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Role{ID: testRoleID, Name: testRoleName}, nil).Once()
+				permissionRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(permissions, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{entity.PERMISSION_ROLE_MANAGE, entity.PERMISSION_PRODUCT_WRITE}, nil).Once()
+				roleRepo.On("Transaction", mock.Anything, mock.Anything).Return(inTransaction).Once()
+				roleRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				roleRepo.On("ReplacePermissions", mock.Anything, mock.Anything, mock.Anything, permissions).Return(gorm.ErrInvalidTransaction).Once()
+			},
+		},
+		{
+			name: "Update Role Failed - Permission Not Held",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateRoleRequest{
+					ID:          testRoleID,
+					Permissions: []string{entity.PERMISSION_PRODUCT_WRITE},
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeRolePermissionNotHeld,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Role{ID: testRoleID, Name: testRoleName}, nil).Once()
+				permissionRepo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(permissions, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{entity.PERMISSION_ROLE_MANAGE}, nil).Once()
+			},
+		},
+		{
+			name: "Update Role Failed - Admin Role By Staff",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateRoleRequest{
+					ID:          testRoleID,
+					Description: &testRoleName,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeAdminRoleChangeDenied,
+			mock: func(userRepo *repo_mocks.IUserRepository, roleRepo *repo_mocks.IRoleRepository, permissionRepo *repo_mocks.IPermissionRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				roleRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Role{ID: testRoleID, Name: entity.ROLE_ADMIN}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			userRepo := repo_mocks.NewIUserRepository(t)
+			roleRepo := repo_mocks.NewIRoleRepository(t)
+			permissionRepo := repo_mocks.NewIPermissionRepository(t)
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(userRepo, roleRepo, permissionRepo, sessionRepo, userHelper, oauthHelper)
+
+			s := &roleService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:       userRepo,
+					RoleRepo:       roleRepo,
+					PermissionRepo: permissionRepo,
+					SessionRepo:    sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:  userHelper,
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			got, err := s.Update(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("roleService.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errCode != 0 && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("roleService.Update() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+			if err == nil && got == nil {
+				t.Error("roleService.Update() got nil response, want non-nil")
+			}
+		})
+	}
+}
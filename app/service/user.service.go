@@ -2,14 +2,21 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
 
 	"sondth-test_soa/app/entity"
 	"sondth-test_soa/app/helper"
 	"sondth-test_soa/app/model"
 	"sondth-test_soa/app/repository"
 	"sondth-test_soa/package/errors"
+	logger "sondth-test_soa/package/log"
+	"sondth-test_soa/package/totp"
 	"sondth-test_soa/utils"
 
+	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
@@ -34,24 +41,30 @@ func (s *userService) Register(
 	req *model.UserRegisterRequest,
 ) (*model.UserRegisterResponse, error) {
 	// Check username
-	existedUser, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
-		Username: &req.Username,
-		Filter: repository.Filter{
-			Fields: []string{"id"},
-		},
-	})
-	if existedUser != nil || err != nil && err != gorm.ErrRecordNotFound {
-		return nil, errors.New(errors.ErrCodeUserExisted)
+	if err := s.checkUsernameAvailable(ctx, req.Username); err != nil {
+		return nil, err
+	}
+
+	// Check email and phone number
+	if err := s.checkContactAvailable(ctx, nil, req.Email, req.Phone); err != nil {
+		return nil, err
 	}
 
 	user := entity.NewUser()
 	user.Username = req.Username
-	user.Password = req.Password
+	if err := user.SetPassword(req.Password); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
 	user.Fullname = req.Fullname
 	user.Role = entity.ROLE_USER
+	user.Email = req.Email
+	user.Phone = req.Phone
 	if err := s.postgresRepo.UserRepo.Create(ctx, nil, user); err != nil {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
+	if err := s.helper.PasswordHelper.RecordPassword(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
 
 	return &model.UserRegisterResponse{}, nil
 }
@@ -60,29 +73,69 @@ func (s *userService) Login(
 	ctx context.Context,
 	req *model.UserLoginRequest,
 ) (*model.UserLoginResponse, error) {
+	// Reject while the username or IP is locked out
+	if err := s.helper.LoginAttemptHelper.CheckLoginLocked(ctx, req.Username, req.ClientIP); err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
 	// Find user by username
 	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
 		Username: &req.Username,
 		Filter: repository.Filter{
-			Fields: []string{"id", "password"},
+			Fields: []string{"id", "password", "mfa_enabled", "status", "suspended_until"},
 		},
 	})
 	if err != nil {
-		return nil, errors.New(errors.ErrCodeUserNotFound)
+		return nil, s.loginFailed(ctx, req.Username, req.ClientIP, errors.ErrCodeUserNotFound)
 	}
 
 	// Check password
 	if err := user.CheckPassword(req.Password); err != nil {
-		return nil, errors.New(errors.ErrCodeIncorrectPassword)
+		return nil, s.loginFailed(ctx, req.Username, req.ClientIP, errors.ErrCodeIncorrectPassword)
 	}
 
-	// Generate tokens
-	accessToken, err := s.helper.OAuthHelper.GenerateAccessToken(*user)
-	if err != nil {
+	// Deleted users are treated as unknown, only the right password tells a suspension apart
+	if user.IsDeleted() {
+		return nil, s.loginFailed(ctx, req.Username, req.ClientIP, errors.ErrCodeUserNotFound)
+	}
+	if user.IsSuspended() {
+		return nil, errors.New(errors.ErrCodeUserSuspended)
+	}
+	if user.IsInvited() {
+		return nil, errors.New(errors.ErrCodeUserNotActivated)
+	}
+
+	// Upgrade the stored hash to the configured algorithm and parameters
+	if user.PasswordNeedsRehash() {
+		if err := user.SetPassword(req.Password); err != nil {
+			logger.WithCtx(ctx).Error("Login: rehash password", err)
+		} else if err := s.postgresRepo.UserRepo.UpdatePassword(ctx, nil, user); err != nil {
+			logger.WithCtx(ctx).Error("Login: rehash password", err)
+		}
+	}
+
+	// Users with 2FA have to exchange the challenge token and a code for tokens,
+	// their failures are only cleared by a right code so wrong codes add up across challenges
+	if user.MfaEnabled {
+		mfaToken, err := s.helper.OAuthHelper.GenerateMfaChallengeToken(ctx, user.ID)
+		if err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+
+		return &model.UserLoginResponse{
+			MfaRequired: true,
+			MfaToken:    mfaToken,
+		}, nil
+	}
+	if err := s.helper.LoginAttemptHelper.ResetLoginFailures(ctx, req.Username); err != nil {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
 
-	refreshToken, err := s.helper.OAuthHelper.GenerateRefreshToken(*user)
+	// Generate tokens for a new session
+	accessToken, refreshToken, err := s.issueLoginTokens(ctx, user, req.ClientInfo, false)
 	if err != nil {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
@@ -93,146 +146,1550 @@ func (s *userService) Login(
 	}, nil
 }
 
-func (s *userService) ForgetPassword(
+func (s *userService) LoginMfa(
 	ctx context.Context,
-	req *model.ForgetPasswordRequest,
-) (*model.ForgetPasswordResponse, error) {
-	// Find user by username
+	req *model.LoginMfaRequest,
+) (*model.LoginMfaResponse, error) {
+	// Verify challenge token
+	userID, err := s.helper.OAuthHelper.VerifyMfaChallengeToken(ctx, req.MfaToken)
+	if err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Find user by ID
 	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
-		Username: &req.Username,
+		ID: &userID,
 	})
 	if err != nil {
 		return nil, errors.New(errors.ErrCodeUserNotFound)
 	}
+	if !user.MfaEnabled {
+		return nil, errors.New(errors.ErrCodeInvalidToken)
+	}
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+	// Wrong codes count toward the lockout of the user, not only of the challenge
+	if err := s.helper.LoginAttemptHelper.CheckLoginLocked(ctx, user.Username, req.ClientIP); err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
 
-	// Update password
-	user.Password = req.NewPassword
-
-	// Update user
+	// Check code
+	recoveryCodes := len(user.MfaRecoveryCodes)
+	if !user.VerifyMfaCode(req.Code) {
+		return nil, s.loginFailed(ctx, user.Username, req.ClientIP, errors.ErrCodeInvalidMfaCode)
+	}
+	if len(user.MfaRecoveryCodes) != recoveryCodes {
+		// A recovery code was used
+		logger.WithCtx(ctx).Info("LoginMfa: recovery code used", slog.String("user_id", user.ID.String()))
+	}
+	// Keeps the used recovery code or TOTP step, so the code can't be replayed
 	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
+	if err := s.helper.LoginAttemptHelper.ResetLoginFailures(ctx, user.Username); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
 
-	return &model.ForgetPasswordResponse{}, nil
+	if err := s.helper.OAuthHelper.RevokeMfaChallengeToken(ctx, req.MfaToken); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Generate tokens for a new session
+	accessToken, refreshToken, err := s.issueLoginTokens(ctx, user, req.ClientInfo, true)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.LoginMfaResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
 }
 
-func (s *userService) ChangePassword(
+func (s *userService) OidcAuthorize(
 	ctx context.Context,
-	req *model.ChangeUserPasswordRequest,
-) (*model.ChangeUserPasswordResponse, error) {
-	if !s.helper.UserHelper.IsValidPermission(ctx, req.ID) {
+	req *model.OidcAuthorizeRequest,
+) (*model.OidcAuthorizeResponse, error) {
+	authorizationURL, err := s.helper.OIDCHelper.GetAuthorizationURL(ctx, req.Provider)
+	if err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.OidcAuthorizeResponse{
+		AuthorizationURL: authorizationURL,
+	}, nil
+}
+
+func (s *userService) OidcCallback(
+	ctx context.Context,
+	req *model.OidcCallbackRequest,
+) (*model.UserLoginResponse, error) {
+	info, err := s.helper.OIDCHelper.ExchangeCode(ctx, req.State, req.Code)
+	if err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	user, err := s.findOrCreateOidcUser(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	// Users with 2FA have to exchange the challenge token and a code for tokens
+	if user.MfaEnabled {
+		mfaToken, err := s.helper.OAuthHelper.GenerateMfaChallengeToken(ctx, user.ID)
+		if err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+
+		return &model.UserLoginResponse{
+			MfaRequired: true,
+			MfaToken:    mfaToken,
+		}, nil
+	}
+
+	// Generate tokens for a new session
+	accessToken, refreshToken, err := s.issueLoginTokens(ctx, user, req.ClientInfo, false)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.UserLoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *userService) EnrollMfa(
+	ctx context.Context,
+	req *model.EnrollMfaRequest,
+) (*model.EnrollMfaResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
 		return nil, errors.New(errors.ErrCodeUnauthorized)
 	}
 
 	// Find user by ID
 	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
-		ID: &req.ID,
+		ID: &requestUser.ID,
 	})
 	if err != nil {
 		return nil, errors.New(errors.ErrCodeUserNotFound)
 	}
-
-	// Check old password
-	if err := user.CheckPassword(req.OldPassword); err != nil {
-		return nil, errors.New(errors.ErrCodeIncorrectPassword)
+	if user.MfaEnabled {
+		return nil, errors.New(errors.ErrCodeMfaAlreadyEnabled)
 	}
 
-	// Update password
-	user.Password = req.NewPassword
+	// Store a pending secret, 2FA is only enabled once a code is confirmed
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	user.MfaSecret = &secret
+	user.MfaRecoveryCodes = nil
 	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
 
-	return &model.ChangeUserPasswordResponse{}, nil
+	return &model.EnrollMfaResponse{
+		Secret:          secret,
+		ProvisioningURI: s.helper.OAuthHelper.GetMfaProvisioningURI(user.Username, secret),
+	}, nil
 }
 
-func (s *userService) UpdateUser(
+func (s *userService) ActivateMfa(
 	ctx context.Context,
-	req *model.UpdateUserRequest,
-) (*model.UpdateUserResponse, error) {
-	if !s.helper.UserHelper.IsValidPermission(ctx, req.ID) {
+	req *model.ActivateMfaRequest,
+) (*model.ActivateMfaResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
 		return nil, errors.New(errors.ErrCodeUnauthorized)
 	}
 
-	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &requestUser.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+	if user.MfaEnabled {
+		return nil, errors.New(errors.ErrCodeMfaAlreadyEnabled)
+	}
+	if user.MfaSecret == nil {
+		return nil, errors.New(errors.ErrCodeMfaNotEnrolled)
+	}
+
+	// Check code against the pending secret, it can't be used again to sign in
+	step, ok := totp.ValidateStep(*user.MfaSecret, req.Code, time.Now())
 	if !ok {
+		return nil, errors.New(errors.ErrCodeInvalidMfaCode)
+	}
+	user.MfaLastStep = &step
+
+	// Recovery codes are shown once, only their hashes are stored
+	recoveryCodes, err := totp.GenerateRecoveryCodes(utils.MFA_RECOVERY_CODES)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	user.MfaRecoveryCodes = make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		user.MfaRecoveryCodes = append(user.MfaRecoveryCodes, utils.HashToken(code))
+	}
+	user.MfaEnabled = true
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
 
+	return &model.ActivateMfaResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (s *userService) DisableMfa(
+	ctx context.Context,
+	req *model.DisableMfaRequest,
+) (*model.DisableMfaResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
 	// Find user by ID
 	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
-		ID: &req.ID,
+		ID: &requestUser.ID,
 	})
 	if err != nil {
 		return nil, errors.New(errors.ErrCodeUserNotFound)
 	}
+	if !user.MfaEnabled {
+		return nil, errors.New(errors.ErrCodeMfaNotEnrolled)
+	}
 
-	// Update fields if provided
-	if req.Username != nil {
-		user.Username = *req.Username
+	// Check code
+	if !user.VerifyMfaCode(req.Code) {
+		return nil, errors.New(errors.ErrCodeInvalidMfaCode)
 	}
-	if req.Fullname != nil {
-		user.Fullname = *req.Fullname
+
+	user.MfaEnabled = false
+	user.MfaSecret = nil
+	user.MfaRecoveryCodes = nil
+	user.MfaLastStep = nil
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
-	if req.Role != nil && requestUser.IsAdmin() {
-		if !s.helper.UserHelper.IsValidRole(*req.Role) {
-			return nil, errors.NewCustomError(
-				errors.ErrCodeValidatorFormat,
-				errors.GetCustomMessage(errors.ErrCodeValidatorFormat, "Role"),
-			)
+
+	return &model.DisableMfaResponse{}, nil
+}
+
+func (s *userService) RefreshToken(
+	ctx context.Context,
+	req *model.RefreshTokenRequest,
+) (*model.RefreshTokenResponse, error) {
+	// Consume refresh token
+	payload, err := s.helper.OAuthHelper.RotateRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
 		}
-		user.Role = *req.Role
+		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
 
-	// Update user
-	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &payload.UserID,
+		Filter: repository.Filter{
+			Fields: []string{"id", "status", "suspended_until"},
+		},
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	// The session must still be active
+	sessionID, err := uuid.Parse(payload.FamilyID)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInvalidToken)
+	}
+	active := true
+	session, err := s.postgresRepo.SessionRepo.FindOneByFilter(ctx, nil, &repository.FindSessionByFilter{
+		ID:     &sessionID,
+		UserID: &user.ID,
+		Active: &active,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeTokenRevoked)
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	session.LastSeenAt = time.Now().Unix()
+	session.IPAddress = req.ClientIP
+	session.UserAgent = req.UserAgent
+	if err := s.postgresRepo.SessionRepo.Update(ctx, nil, session); err != nil {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
 
-	return &model.UpdateUserResponse{
-		User: *user,
+	// Generate tokens in the same family
+	accessToken, err := s.helper.OAuthHelper.GenerateAccessToken(ctx, *user, payload.Session())
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	refreshToken, err := s.helper.OAuthHelper.GenerateRefreshToken(ctx, *user, payload.Session())
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
-func (s *userService) GetUsers(
+func (s *userService) Logout(
 	ctx context.Context,
-	req *model.GetUsersRequest,
-) (*model.GetUsersResponse, error) {
-	filter := &repository.FindUserByFilter{
-		Name:  req.Name,
-		Role:  req.Role,
-		Page:  req.Page,
-		Limit: req.Limit,
+	req *model.LogoutRequest,
+) (*model.LogoutResponse, error) {
+	payload, ok := ctx.Value(string(utils.TOKEN_CONTEXT_KEY)).(*model.UserJWTPayload)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
 	}
 
-	errGroup, errCtx := errgroup.WithContext(ctx)
-
-	var users []entity.User
-	errGroup.Go(func() error {
-		var err error
-		users, err = s.postgresRepo.UserRepo.FindManyByFilter(errCtx, nil, filter)
-		if err != nil {
-			return err
+	// Revoke current access token and its session
+	if err := s.helper.OAuthHelper.RevokeAccessToken(ctx, payload); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	// Impersonation tokens share the session of the admin, only the token itself is revoked
+	if payload.FamilyID != "" && !payload.IsImpersonated() {
+		if err := s.revokeSession(ctx, payload.UserID, payload.FamilyID); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
 		}
-		return nil
-	})
+	}
 
-	var count int64
-	errGroup.Go(func() error {
-		var err error
-		count, err = s.postgresRepo.UserRepo.CountByFilter(errCtx, nil, filter)
-		if err != nil {
-			return err
-		}
-		return nil
+	return &model.LogoutResponse{}, nil
+}
+
+func (s *userService) LogoutAll(
+	ctx context.Context,
+	req *model.LogoutAllRequest,
+) (*model.LogoutAllResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.LogoutAllResponse{}, nil
+}
+
+func (s *userService) ForgetPassword(
+	ctx context.Context,
+	req *model.ForgetPasswordRequest,
+) (*model.ForgetPasswordResponse, error) {
+	// Find user by username
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		Username: &req.Username,
+		Filter: repository.Filter{
+			Fields: []string{"id", "username"},
+		},
 	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// Respond the same way so usernames can't be enumerated
+			logger.WithCtx(ctx).Info("ForgetPassword: user not found", slog.String("username", req.Username))
+			return &model.ForgetPasswordResponse{}, nil
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
 
-	if err := errGroup.Wait(); err != nil {
+	// Issue a one-time reset token
+	token, err := s.helper.OAuthHelper.GeneratePasswordResetToken(ctx, user.ID)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := s.helper.NotificationHelper.SendPasswordResetToken(ctx, *user, token); err != nil {
+		logger.WithCtx(ctx).Error("SendPasswordResetToken", err)
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
 
-	return &model.GetUsersResponse{
-		Users: users,
-		Count: count,
-	}, nil
+	return &model.ForgetPasswordResponse{}, nil
+}
+
+func (s *userService) ResetPassword(
+	ctx context.Context,
+	req *model.ResetPasswordRequest,
+) (*model.ResetPasswordResponse, error) {
+	// Consume reset token
+	userID, err := s.helper.OAuthHelper.ConsumePasswordResetToken(ctx, req.Token)
+	if err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &userID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+
+	// Check password history
+	if err := s.helper.PasswordHelper.CheckPasswordReuse(ctx, user, req.NewPassword); err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Update password
+	if err := user.SetPassword(req.NewPassword); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := s.helper.PasswordHelper.RecordPassword(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Sign out every device of the user
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.ResetPasswordResponse{}, nil
+}
+
+func (s *userService) ChangePassword(
+	ctx context.Context,
+	req *model.ChangeUserPasswordRequest,
+) (*model.ChangeUserPasswordResponse, error) {
+	requestUser, err := s.authorizeUserAccess(ctx, req.ID, entity.PERMISSION_USER_WRITE)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &req.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+
+	// Users prove it's them with the old password, staff with user:write set it for less privileged users
+	if requestUser.ID == user.ID {
+		if err := user.CheckPassword(req.OldPassword); err != nil {
+			return nil, errors.New(errors.ErrCodeIncorrectPassword)
+		}
+	} else if err := s.checkPrivileges(ctx, requestUser, user); err != nil {
+		return nil, err
+	}
+
+	// Check password history
+	if err := s.helper.PasswordHelper.CheckPasswordReuse(ctx, user, req.NewPassword); err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Update password
+	if err := user.SetPassword(req.NewPassword); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := s.helper.PasswordHelper.RecordPassword(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Sign out every device of the user
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.ChangeUserPasswordResponse{}, nil
+}
+
+func (s *userService) UpdateUser(
+	ctx context.Context,
+	req *model.UpdateUserRequest,
+) (*model.UpdateUserResponse, error) {
+	requestUser, err := s.authorizeUserAccess(ctx, req.ID, entity.PERMISSION_USER_WRITE)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &req.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+	// Changing the contact of a more privileged user would let staff take the account over by resetting its password
+	if requestUser.ID != user.ID {
+		if err := s.checkPrivileges(ctx, requestUser, user); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update fields if provided
+	if req.Username != nil && *req.Username != user.Username {
+		if err := s.checkUsernameAvailable(ctx, *req.Username); err != nil {
+			return nil, err
+		}
+		user.Username = *req.Username
+	}
+	if err := s.applyProfileChanges(ctx, user, req.Fullname, req.Email, req.Phone); err != nil {
+		return nil, err
+	}
+
+	// Roles are changed by admins and role managers only, nobody changes their own role
+	roleChanged := req.Role != nil && *req.Role != user.Role
+	if roleChanged {
+		if requestUser.ID == user.ID {
+			return nil, errors.New(errors.ErrCodeCannotChangeOwnRole)
+		}
+		if !requestUser.IsAdmin() {
+			canManageRoles, err := s.helper.UserHelper.HasPermission(ctx, requestUser, entity.PERMISSION_ROLE_MANAGE)
+			if err != nil {
+				return nil, errors.New(errors.ErrCodeInternalServerError)
+			}
+			if !canManageRoles {
+				return nil, errors.New(errors.ErrCodeForbidden)
+			}
+		}
+		if err := s.validateRole(requestUser, *req.Role); err != nil {
+			return nil, err
+		}
+		user.Role = *req.Role
+	}
+
+	// Update user
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Tokens issued with the old role must not be used anymore
+	if roleChanged {
+		if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+	}
+
+	return &model.UpdateUserResponse{
+		User: *user,
+	}, nil
+}
+
+func (s *userService) GetMe(
+	ctx context.Context,
+	req *model.GetMeRequest,
+) (*model.GetMeResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	permissions, err := s.helper.UserHelper.GetPermissions(ctx, requestUser)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.GetMeResponse{
+		User:        *requestUser,
+		Permissions: permissions,
+	}, nil
+}
+
+func (s *userService) UpdateProfile(
+	ctx context.Context,
+	req *model.UpdateProfileRequest,
+) (*model.UpdateProfileResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &requestUser.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+
+	if err := s.applyProfileChanges(ctx, user, req.Fullname, req.Email, req.Phone); err != nil {
+		return nil, err
+	}
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.UpdateProfileResponse{
+		User: *user,
+	}, nil
+}
+
+func (s *userService) SendVerificationCode(
+	ctx context.Context,
+	req *model.SendVerificationCodeRequest,
+) (*model.SendVerificationCodeResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &requestUser.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+
+	target, ok := user.VerificationTarget(req.Channel)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeVerificationTargetMissing)
+	}
+	if user.IsVerified(req.Channel) {
+		return nil, errors.New(errors.ErrCodeAlreadyVerified)
+	}
+
+	// Issue a one-time code and send it to the address
+	code, err := s.helper.VerificationHelper.GenerateVerificationCode(ctx, user.ID, req.Channel, target)
+	if err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := s.helper.NotificationHelper.SendVerificationCode(ctx, req.Channel, target, code); err != nil {
+		logger.WithCtx(ctx).Error("SendVerificationCode", err)
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.SendVerificationCodeResponse{}, nil
+}
+
+func (s *userService) ConfirmVerificationCode(
+	ctx context.Context,
+	req *model.ConfirmVerificationCodeRequest,
+) (*model.ConfirmVerificationCodeResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &requestUser.ID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+
+	target, ok := user.VerificationTarget(req.Channel)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeVerificationTargetMissing)
+	}
+	if user.IsVerified(req.Channel) {
+		return nil, errors.New(errors.ErrCodeAlreadyVerified)
+	}
+
+	// Check code
+	if err := s.helper.VerificationHelper.VerifyVerificationCode(ctx, user.ID, req.Channel, target, req.Code); err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	user.SetVerified(req.Channel)
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.ConfirmVerificationCodeResponse{
+		User: *user,
+	}, nil
+}
+
+func (s *userService) GetSessions(
+	ctx context.Context,
+	req *model.GetSessionsRequest,
+) (*model.GetSessionsResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	active := true
+	sessions, err := s.postgresRepo.SessionRepo.FindManyByFilter(ctx, nil, &repository.FindSessionByFilter{
+		UserID: &user.ID,
+		Active: &active,
+		Page:   req.Page,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	res := &model.GetSessionsResponse{
+		Sessions: sessions,
+	}
+	if payload, ok := ctx.Value(string(utils.TOKEN_CONTEXT_KEY)).(*model.UserJWTPayload); ok {
+		res.CurrentSessionID = payload.FamilyID
+	}
+
+	return res, nil
+}
+
+func (s *userService) RevokeSession(
+	ctx context.Context,
+	req *model.RevokeSessionRequest,
+) (*model.RevokeSessionResponse, error) {
+	user, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	// Users can only revoke their own sessions
+	active := true
+	session, err := s.postgresRepo.SessionRepo.FindOneByFilter(ctx, nil, &repository.FindSessionByFilter{
+		ID:     &req.ID,
+		UserID: &user.ID,
+		Active: &active,
+		Filter: repository.Filter{
+			Fields: []string{"id", "user_id"},
+		},
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeSessionNotFound)
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	if err := s.revokeSession(ctx, session.UserID, session.ID.String()); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.RevokeSessionResponse{}, nil
+}
+
+func (s *userService) UpdateUserStatus(
+	ctx context.Context,
+	req *model.UpdateUserStatusRequest,
+) (*model.UpdateUserStatusResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	if requestUser.ID == req.UserID {
+		return nil, errors.New(errors.ErrCodeCannotChangeOwnStatus)
+	}
+	if req.Status == entity.USER_STATUS_SUSPENDED && req.SuspendedUntil != nil && *req.SuspendedUntil <= time.Now().Unix() {
+		return nil, errors.New(errors.ErrCodeSuspensionEndInvalid)
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &req.UserID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+	if err := s.checkPrivileges(ctx, requestUser, user); err != nil {
+		return nil, err
+	}
+
+	user.SetStatus(req.Status, req.Reason, req.SuspendedUntil)
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Suspended and deleted users are signed out everywhere
+	if req.Status != entity.USER_STATUS_ACTIVE {
+		if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+	}
+	logger.WithCtx(ctx).Info(
+		"UpdateUserStatus: status changed",
+		slog.String("user_id", user.ID.String()),
+		slog.String("status", req.Status),
+		slog.String("changed_by", requestUser.ID.String()),
+	)
+
+	return &model.UpdateUserStatusResponse{}, nil
+}
+
+func (s *userService) RevokeUserSessions(
+	ctx context.Context,
+	req *model.RevokeUserSessionsRequest,
+) (*model.RevokeUserSessionsResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &req.UserID,
+		Filter: repository.Filter{
+			Fields: []string{"id", "role"},
+		},
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+	if err := s.checkPrivileges(ctx, requestUser, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	logger.WithCtx(ctx).Info("RevokeUserSessions: all sessions revoked", slog.String("user_id", user.ID.String()))
+
+	return &model.RevokeUserSessionsResponse{}, nil
+}
+
+func (s *userService) UnlockUser(
+	ctx context.Context,
+	req *model.UnlockUserRequest,
+) (*model.UnlockUserResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	// Find user by ID
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &req.ID,
+		Filter: repository.Filter{
+			Fields: []string{"id", "username", "role"},
+		},
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+	if err := s.checkPrivileges(ctx, requestUser, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.helper.LoginAttemptHelper.ResetLoginFailures(ctx, user.Username); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	logger.WithCtx(ctx).Info("UnlockUser: login lockout cleared", slog.String("user_id", user.ID.String()))
+
+	return &model.UnlockUserResponse{}, nil
+}
+
+func (s *userService) GetUsers(
+	ctx context.Context,
+	req *model.GetUsersRequest,
+) (*model.GetUsersResponse, error) {
+	if req.CreatedFrom != nil && req.CreatedTo != nil && *req.CreatedFrom > *req.CreatedTo {
+		return nil, errors.Newf(errors.ErrCodeValidatorFormat, "CreatedTo")
+	}
+	page, err := newListPage(req.CursorRequest, req.Sort, repository.UserSortFields, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &repository.FindUserByFilter{
+		Name:        req.Name,
+		Role:        req.Role,
+		Status:      req.Status,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		Sort:        page.Sort,
+		Keyset:      page.Keyset,
+		Page:        page.Page,
+		Limit:       page.Limit,
+		Peek:        page.Peek,
+	}
+
+	errGroup, errCtx := errgroup.WithContext(ctx)
+
+	var users []entity.User
+	errGroup.Go(func() error {
+		var err error
+		users, err = s.postgresRepo.UserRepo.FindManyByFilter(errCtx, nil, filter)
+		if err != nil {
+			return keysetError(err)
+		}
+		return nil
+	})
+
+	var count *int64
+	if !req.SkipCount {
+		errGroup.Go(func() error {
+			total, err := s.postgresRepo.UserRepo.CountByFilter(errCtx, nil, filter)
+			if err != nil {
+				return err
+			}
+			count = &total
+			return nil
+		})
+	}
+
+	if err := errGroup.Wait(); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	users, cursors := cutPage(page, users, func(user entity.User) uuid.UUID { return user.ID })
+
+	return &model.GetUsersResponse{
+		Users:          users,
+		Count:          count,
+		CursorResponse: cursors,
+	}, nil
+}
+
+func (s *userService) CreateUser(
+	ctx context.Context,
+	req *model.CreateUserRequest,
+) (*model.CreateUserResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	if err := s.validateRole(requestUser, req.Role); err != nil {
+		return nil, err
+	}
+	if err := s.checkUsernameAvailable(ctx, req.Username); err != nil {
+		return nil, err
+	}
+	if err := s.checkContactAvailable(ctx, nil, req.Email, req.Phone); err != nil {
+		return nil, err
+	}
+
+	user := entity.NewUser()
+	user.Username = req.Username
+	if err := user.SetPassword(req.Password); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	user.Fullname = req.Fullname
+	user.Role = req.Role
+	user.Email = req.Email
+	user.Phone = req.Phone
+	if err := s.postgresRepo.UserRepo.Create(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := s.helper.PasswordHelper.RecordPassword(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.CreateUserResponse{
+		User: *user,
+	}, nil
+}
+
+func (s *userService) InviteUser(
+	ctx context.Context,
+	req *model.InviteUserRequest,
+) (*model.InviteUserResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	if err := s.validateRole(requestUser, req.Role); err != nil {
+		return nil, err
+	}
+	if err := s.checkUsernameAvailable(ctx, req.Username); err != nil {
+		return nil, err
+	}
+	if err := s.checkContactAvailable(ctx, nil, &req.Email, nil); err != nil {
+		return nil, err
+	}
+
+	// Nobody knows the password, the user chooses one with the activation link
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	user := entity.NewUser()
+	user.Username = req.Username
+	if err := user.SetPassword(password); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	user.Fullname = req.Fullname
+	user.Role = req.Role
+	user.Email = &req.Email
+	user.Status = entity.USER_STATUS_INVITED
+	if err := s.postgresRepo.UserRepo.Create(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	token, err := s.helper.OAuthHelper.GenerateInvitationToken(ctx, user.ID)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := s.helper.NotificationHelper.SendInvitation(ctx, *user, token); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.InviteUserResponse{
+		User: *user,
+	}, nil
+}
+
+func (s *userService) ActivateUser(
+	ctx context.Context,
+	req *model.ActivateUserRequest,
+) (*model.ActivateUserResponse, error) {
+	userID, err := s.helper.OAuthHelper.ConsumeInvitationToken(ctx, req.Token)
+	if err != nil {
+		if _, ok := err.(*errors.CustomError); ok {
+			return nil, err
+		}
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &userID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+	if !user.IsInvited() {
+		return nil, errors.New(errors.ErrCodeInvalidToken)
+	}
+
+	// The link was sent to the email, using it proves the address
+	if err := user.SetPassword(req.NewPassword); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	user.EmailVerified = true
+	user.SetStatus(entity.USER_STATUS_ACTIVE, nil, nil)
+	if err := s.postgresRepo.UserRepo.Update(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if err := s.helper.PasswordHelper.RecordPassword(ctx, nil, user); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.ActivateUserResponse{}, nil
+}
+
+func (s *userService) BulkUpdateUserRole(
+	ctx context.Context,
+	req *model.BulkUpdateUserRoleRequest,
+) (*model.BulkUpdateUserRoleResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	if slices.Contains(req.UserIDs, requestUser.ID) {
+		return nil, errors.New(errors.ErrCodeCannotChangeOwnRole)
+	}
+	if err := s.validateRole(requestUser, req.Role); err != nil {
+		return nil, err
+	}
+
+	users, err := s.postgresRepo.UserRepo.FindManyByFilter(ctx, nil, &repository.FindUserByFilter{
+		IDs: req.UserIDs,
+		Filter: repository.Filter{
+			Fields: []string{"id", "role"}, // all checkPrivileges needs to find the permissions of the users
+		},
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if len(users) != len(req.UserIDs) {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+
+	changedIDs := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		if err := s.checkPrivileges(ctx, requestUser, &user); err != nil {
+			return nil, err
+		}
+		if user.Role != req.Role {
+			changedIDs = append(changedIDs, user.ID)
+		}
+	}
+	if len(changedIDs) == 0 {
+		return &model.BulkUpdateUserRoleResponse{}, nil
+	}
+
+	if err := s.postgresRepo.UserRepo.UpdateRoleByFilter(ctx, nil, &repository.FindUserByFilter{
+		IDs: changedIDs,
+	}, req.Role); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	// Tokens issued with the old role must not be used anymore
+	for _, userID := range changedIDs {
+		if err := s.revokeAllSessions(ctx, userID); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+	}
+	logger.WithCtx(ctx).Info(
+		"BulkUpdateUserRole: roles changed",
+		slog.Int("count", len(changedIDs)),
+		slog.String("role", req.Role),
+		slog.String("changed_by", requestUser.ID.String()),
+	)
+
+	return &model.BulkUpdateUserRoleResponse{
+		Updated: len(changedIDs),
+	}, nil
+}
+
+func (s *userService) Impersonate(
+	ctx context.Context,
+	req *model.ImpersonateUserRequest,
+) (*model.ImpersonateUserResponse, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	payload, ok := ctx.Value(string(utils.TOKEN_CONTEXT_KEY)).(*model.UserJWTPayload)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	if payload.IsImpersonated() {
+		return nil, errors.New(errors.ErrCodeImpersonationForbidden)
+	}
+
+	user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		ID: &req.UserID,
+	})
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeUserNotFound)
+	}
+
+	// Only customers can be impersonated, impersonating staff would grant their permissions
+	if user.ID == requestUser.ID || user.IsAdmin() {
+		return nil, errors.New(errors.ErrCodeCannotImpersonate)
+	}
+	staffPermissions, err := s.helper.UserHelper.GetStaffPermissions(ctx, user)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if len(staffPermissions) > 0 {
+		return nil, errors.New(errors.ErrCodeCannotImpersonate)
+	}
+
+	accessToken, err := s.helper.OAuthHelper.GenerateImpersonationToken(ctx, requestUser.ID, *user, payload.Session())
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	log := entity.NewImpersonationLog(requestUser.ID, user.ID, entity.IMPERSONATION_ACTION_START)
+	log.ClientIP = req.ClientIP
+	log.UserAgent = req.UserAgent
+	if err := s.postgresRepo.ImpersonationLogRepo.Create(ctx, nil, log); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	logger.WithCtx(ctx).Info(
+		"Impersonate: impersonation started",
+		slog.String("impersonator_id", requestUser.ID.String()),
+		slog.String("user_id", user.ID.String()),
+	)
+
+	return &model.ImpersonateUserResponse{
+		AccessToken: accessToken,
+		ExpiresIn:   utils.IMPERSONATION_TOKEN_IAT,
+	}, nil
+}
+
+func (s *userService) GetImpersonationLogs(
+	ctx context.Context,
+	req *model.GetImpersonationLogsRequest,
+) (*model.GetImpersonationLogsResponse, error) {
+	filter := &repository.FindImpersonationLogByFilter{
+		ImpersonatorID: req.ImpersonatorID,
+		UserID:         req.UserID,
+		Page:           req.Page,
+		Limit:          req.Limit,
+	}
+
+	errGroup, errCtx := errgroup.WithContext(ctx)
+
+	var logs []entity.ImpersonationLog
+	errGroup.Go(func() error {
+		var err error
+		logs, err = s.postgresRepo.ImpersonationLogRepo.FindManyByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	var count int64
+	errGroup.Go(func() error {
+		var err error
+		count, err = s.postgresRepo.ImpersonationLogRepo.CountByFilter(errCtx, nil, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if err := errGroup.Wait(); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return &model.GetImpersonationLogsResponse{
+		Logs:  logs,
+		Count: count,
+	}, nil
+}
+
+// -------------------------------------------------------------------------------
+func checkUserStatus(user *entity.User) error {
+	if user.IsDeleted() {
+		return errors.New(errors.ErrCodeUserDeleted)
+	}
+	if user.IsSuspended() {
+		return errors.New(errors.ErrCodeUserSuspended)
+	}
+	if user.IsInvited() {
+		return errors.New(errors.ErrCodeUserNotActivated)
+	}
+
+	return nil
+}
+
+// authorizeUserAccess is the ownership-or-permission policy: users act on their own account,
+// acting on another account needs the permission
+func (s *userService) authorizeUserAccess(ctx context.Context, userID uuid.UUID, permission string) (*entity.User, error) {
+	requestUser, ok := ctx.Value(string(utils.USER_CONTEXT_KEY)).(*entity.User)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+	if requestUser.ID == userID {
+		return requestUser, nil
+	}
+
+	allowed, err := s.helper.UserHelper.HasPermission(ctx, requestUser, permission)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+	if !allowed {
+		return nil, errors.New(errors.ErrCodeUnauthorized)
+	}
+
+	return requestUser, nil
+}
+
+// checkPrivileges makes sure the user has no privilege the request user lacks, admins outrank everyone
+func (s *userService) checkPrivileges(ctx context.Context, requestUser *entity.User, user *entity.User) error {
+	return checkUserPrivileges(ctx, s.helper, requestUser, user)
+}
+
+// checkUserPrivileges is shared with the role service, as roles change what users can do
+func checkUserPrivileges(
+	ctx context.Context,
+	helper helper.HelperCollections,
+	requestUser *entity.User,
+	user *entity.User,
+) error {
+	if requestUser.IsAdmin() {
+		return nil
+	}
+	if user.IsAdmin() {
+		return errors.New(errors.ErrCodeUserMorePrivileged)
+	}
+
+	staffPermissions, err := helper.UserHelper.GetStaffPermissions(ctx, user)
+	if err != nil {
+		return errors.New(errors.ErrCodeInternalServerError)
+	}
+	if len(staffPermissions) == 0 {
+		return nil
+	}
+	permissions, err := helper.UserHelper.GetPermissions(ctx, requestUser)
+	if err != nil {
+		return errors.New(errors.ErrCodeInternalServerError)
+	}
+	for _, permission := range staffPermissions {
+		if !slices.Contains(permissions, permission) {
+			return errors.New(errors.ErrCodeUserMorePrivileged)
+		}
+	}
+
+	return nil
+}
+
+// applyProfileChanges sets the self-service fields that are provided, a changed address has to be verified again
+func (s *userService) applyProfileChanges(ctx context.Context, user *entity.User, fullname *string, email *string, phone *string) error {
+	if email != nil || phone != nil {
+		if err := s.checkContactAvailable(ctx, &user.ID, email, phone); err != nil {
+			return err
+		}
+	}
+
+	if fullname != nil {
+		user.Fullname = *fullname
+	}
+	if email != nil && (user.Email == nil || *user.Email != *email) {
+		user.Email = email
+		user.EmailVerified = false
+	}
+	if phone != nil && (user.Phone == nil || *user.Phone != *phone) {
+		user.Phone = phone
+		user.PhoneVerified = false
+	}
+
+	return nil
+}
+
+func (s *userService) checkUsernameAvailable(ctx context.Context, username string) error {
+	existedUser, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+		Username: &username,
+		Filter: repository.Filter{
+			Fields: []string{"id"},
+		},
+	})
+	if existedUser != nil || err != nil && err != gorm.ErrRecordNotFound {
+		return errors.New(errors.ErrCodeUserExisted)
+	}
+
+	return nil
+}
+
+// validateRole makes sure the role exists and that the request user may give it, only admins make other admins
+func (s *userService) validateRole(requestUser *entity.User, role string) error {
+	if !s.helper.UserHelper.IsValidRole(role) {
+		return errors.Newf(errors.ErrCodeValidatorFormat, "Role")
+	}
+	if role == entity.ROLE_ADMIN && !requestUser.IsAdmin() {
+		return errors.New(errors.ErrCodeAdminRoleForbidden)
+	}
+
+	return nil
+}
+
+func (s *userService) loginFailed(ctx context.Context, username string, clientIP string, code int) error {
+	if err := s.helper.LoginAttemptHelper.RecordLoginFailure(ctx, username, clientIP); err != nil {
+		logger.WithCtx(ctx).Error("RecordLoginFailure", err)
+		return errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return errors.New(code)
+}
+
+// checkContactAvailable makes sure the email and phone number aren't used by another user
+func (s *userService) checkContactAvailable(ctx context.Context, userID *uuid.UUID, email *string, phone *string) error {
+	if email != nil {
+		existedUser, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+			Email: email,
+			Filter: repository.Filter{
+				Fields: []string{"id"},
+			},
+		})
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return errors.New(errors.ErrCodeInternalServerError)
+		}
+		if existedUser != nil && (userID == nil || existedUser.ID != *userID) {
+			return errors.New(errors.ErrCodeEmailExisted)
+		}
+	}
+
+	if phone != nil {
+		existedUser, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+			Phone: phone,
+			Filter: repository.Filter{
+				Fields: []string{"id"},
+			},
+		})
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return errors.New(errors.ErrCodeInternalServerError)
+		}
+		if existedUser != nil && (userID == nil || existedUser.ID != *userID) {
+			return errors.New(errors.ErrCodePhoneExisted)
+		}
+	}
+
+	return nil
+}
+
+// findOrCreateOidcUser returns the user linked to the provider account. Unknown accounts are linked to
+// the user with the same verified email, or get a new user just-in-time.
+func (s *userService) findOrCreateOidcUser(ctx context.Context, info *model.OidcUserInfo) (*entity.User, error) {
+	identity, err := s.postgresRepo.IdentityRepo.FindOneByFilter(ctx, nil, &repository.FindIdentityByFilter{
+		Provider: &info.Provider,
+		Subject:  &info.Subject,
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	if identity != nil {
+		user, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+			ID: &identity.UserID,
+		})
+		if err != nil {
+			return nil, errors.New(errors.ErrCodeUserNotFound)
+		}
+
+		identity.LastLoginAt = time.Now().Unix()
+		if err := s.postgresRepo.IdentityRepo.Update(ctx, nil, identity); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+		return user, nil
+	}
+
+	var email *string
+	if info.Email != "" {
+		email = &info.Email
+	}
+
+	// Both sides have to have verified the email, otherwise anyone could take over an account
+	var user *entity.User
+	if email != nil && info.EmailVerified {
+		existedUser, err := s.postgresRepo.UserRepo.FindOneByFilter(ctx, nil, &repository.FindUserByFilter{
+			Email: email,
+		})
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+		if existedUser != nil {
+			if !existedUser.EmailVerified {
+				return nil, errors.New(errors.ErrCodeEmailExisted)
+			}
+			user = existedUser
+		}
+	}
+
+	if user == nil {
+		// The password is random, the account can set one through the forgot password flow
+		password, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+
+		user = entity.NewUser()
+		user.Username = fmt.Sprintf("%s_%s", info.Provider, info.Subject)
+		if err := user.SetPassword(password); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+		user.Fullname = info.Name
+		if user.Fullname == "" {
+			user.Fullname = user.Username
+		}
+		user.Role = entity.ROLE_USER
+		if email != nil {
+			if err := s.checkContactAvailable(ctx, nil, email, nil); err == nil {
+				user.Email = email
+				user.EmailVerified = info.EmailVerified
+			}
+		}
+		if err := s.postgresRepo.UserRepo.Create(ctx, nil, user); err != nil {
+			return nil, errors.New(errors.ErrCodeInternalServerError)
+		}
+	}
+
+	identity = entity.NewIdentity(user.ID, info.Provider, info.Subject)
+	identity.Email = email
+	if err := s.postgresRepo.IdentityRepo.Create(ctx, nil, identity); err != nil {
+		return nil, errors.New(errors.ErrCodeInternalServerError)
+	}
+
+	return user, nil
+}
+
+// issueLoginTokens creates a session and its token pair
+func (s *userService) issueLoginTokens(
+	ctx context.Context,
+	user *entity.User,
+	clientInfo model.ClientInfo,
+	mfaVerified bool,
+) (string, string, error) {
+	session, err := s.createSession(ctx, user.ID, clientInfo)
+	if err != nil {
+		return "", "", err
+	}
+	tokenSession := model.TokenSession{FamilyID: session.ID.String(), MfaVerified: mfaVerified}
+	accessToken, err := s.helper.OAuthHelper.GenerateAccessToken(ctx, *user, tokenSession)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, err := s.helper.OAuthHelper.GenerateRefreshToken(ctx, *user, tokenSession)
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+func (s *userService) createSession(ctx context.Context, userID uuid.UUID, client model.ClientInfo) (*entity.Session, error) {
+	session := entity.NewSession(userID, client.UserAgent, client.ClientIP)
+	if err := s.postgresRepo.SessionRepo.Create(ctx, nil, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *userService) revokeSession(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := s.postgresRepo.SessionRepo.RevokeManyByFilter(ctx, nil, &repository.FindSessionByFilter{
+		ID:     &id,
+		UserID: &userID,
+	}); err != nil {
+		return err
+	}
+
+	return s.helper.OAuthHelper.RevokeSession(ctx, sessionID)
+}
+
+// revokeAllSessions signs the user out of every device
+func (s *userService) revokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	return revokeUserSessions(ctx, s.postgresRepo, s.helper, userID)
+}
+
+// revokeUserSessions revokes the sessions of the user and every token issued before
+func revokeUserSessions(
+	ctx context.Context,
+	postgresRepo repository.RepositoryCollections,
+	helper helper.HelperCollections,
+	userID uuid.UUID,
+) error {
+	if err := postgresRepo.SessionRepo.RevokeManyByFilter(ctx, nil, &repository.FindSessionByFilter{
+		UserID: &userID,
+	}); err != nil {
+		return err
+	}
+
+	return helper.OAuthHelper.RevokeAllUserTokens(ctx, userID)
 }
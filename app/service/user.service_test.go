@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"reflect"
 	"testing"
+	"time"
 
 	"sondth-test_soa/app/entity"
 	"sondth-test_soa/app/helper"
@@ -11,11 +13,14 @@ import (
 	helper_mocks "sondth-test_soa/mocks/helper"
 	repo_mocks "sondth-test_soa/mocks/repository"
 	"sondth-test_soa/package/errors"
+	_password "sondth-test_soa/package/password"
+	"sondth-test_soa/package/totp"
 	"sondth-test_soa/utils"
 
 	"github.com/google/uuid"
 
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -40,10 +45,11 @@ func Test_userService_Register(t *testing.T) {
 		args    args
 		want    *model.UserRegisterResponse
 		wantErr bool
-		mock    func(repo *repo_mocks.IUserRepository)
+		mock    func(repo *repo_mocks.IUserRepository, passwordHelper *helper_mocks.IPasswordHelper)
 	}
 
 	ctx := context.Background()
+	hashLikePassword := "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
 
 	tests := []testCase{
 		{
@@ -58,7 +64,7 @@ func Test_userService_Register(t *testing.T) {
 			},
 			want:    &model.UserRegisterResponse{},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IUserRepository) {
+			mock: func(repo *repo_mocks.IUserRepository, passwordHelper *helper_mocks.IPasswordHelper) {
 				// Mock username check
 				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
 					return true
@@ -71,10 +77,36 @@ func Test_userService_Register(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
 					return user.Username == username &&
-						user.Password == password &&
+						user.CheckPassword(password) == nil &&
 						user.Fullname == fullname &&
 						user.Role == entity.ROLE_USER
 				})).Return(nil).Once()
+
+				// Mock password history
+				passwordHelper.On("RecordPassword", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil).Once()
+			},
+		},
+		{
+			name: "Register Success - Password Looking Like A Hash",
+			args: args{
+				ctx: ctx,
+				req: &model.UserRegisterRequest{
+					Username: username,
+					Password: hashLikePassword,
+					Fullname: fullname,
+				},
+			},
+			want:    &model.UserRegisterResponse{},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+
+				// The password is hashed like any other, the user can sign in with it
+				repo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Password != hashLikePassword && user.CheckPassword(hashLikePassword) == nil
+				})).Return(nil).Once()
+
+				passwordHelper.On("RecordPassword", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil).Once()
 			},
 		},
 		{
@@ -89,7 +121,7 @@ func Test_userService_Register(t *testing.T) {
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository) {
+			mock: func(repo *repo_mocks.IUserRepository, passwordHelper *helper_mocks.IPasswordHelper) {
 				// Mock username exists
 				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
 					return true
@@ -104,14 +136,18 @@ func Test_userService_Register(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Initialize mocks
 			repo := repo_mocks.NewIUserRepository(t)
+			passwordHelper := helper_mocks.NewIPasswordHelper(t)
 
 			// Setup mocks
-			tt.mock(repo)
+			tt.mock(repo, passwordHelper)
 
 			s := &userService{
 				postgresRepo: repository.RepositoryCollections{
 					UserRepo: repo,
 				},
+				helper: helper.HelperCollections{
+					PasswordHelper: passwordHelper,
+				},
 			}
 
 			got, err := s.Register(tt.args.ctx, tt.args.req)
@@ -137,12 +173,13 @@ func Test_userService_Login(t *testing.T) {
 		args    args
 		want    *model.UserLoginResponse
 		wantErr bool
-		mock    func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper)
+		mock    func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository)
 	}
 
 	ctx := context.Background()
 	accessToken := "test-access-token"
 	refreshToken := "test-refresh-token"
+	clientIP := "127.0.0.1"
 
 	tests := []testCase{
 		{
@@ -150,8 +187,9 @@ func Test_userService_Login(t *testing.T) {
 			args: args{
 				ctx: ctx,
 				req: &model.UserLoginRequest{
-					Username: username,
-					Password: password,
+					Username:   username,
+					Password:   password,
+					ClientInfo: model.ClientInfo{ClientIP: clientIP},
 				},
 			},
 			want: &model.UserLoginResponse{
@@ -159,7 +197,10 @@ func Test_userService_Login(t *testing.T) {
 				RefreshToken: refreshToken,
 			},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, clientIP).Return(nil).Once()
+				loginAttemptHelper.On("ResetLoginFailures", mock.Anything, username).Return(nil).Once()
+
 				// Mock find user
 				user := &entity.User{
 					Username: username,
@@ -171,23 +212,106 @@ func Test_userService_Login(t *testing.T) {
 					return filter.Username != nil && *filter.Username == username
 				})).Return(user, nil).Once()
 
+				// Mock session creation
+				sessionRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.Session")).Return(nil).Once()
+
 				// Mock token generation
-				oauthHelper.On("GenerateAccessToken", mock.AnythingOfType("entity.User")).Return(accessToken, nil).Once()
-				oauthHelper.On("GenerateRefreshToken", mock.AnythingOfType("entity.User")).Return(refreshToken, nil).Once()
+				oauthHelper.On("GenerateAccessToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return(accessToken, nil).Once()
+				oauthHelper.On("GenerateRefreshToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return(refreshToken, nil).Once()
 			},
 		},
 		{
-			name: "Login Failed - User Not Found",
+			name: "Login Success - Rehash Outdated Password",
 			args: args{
 				ctx: ctx,
 				req: &model.UserLoginRequest{
+					Username:   username,
+					Password:   password,
+					ClientInfo: model.ClientInfo{ClientIP: clientIP},
+				},
+			},
+			want: &model.UserLoginResponse{
+				AccessToken:  accessToken,
+				RefreshToken: refreshToken,
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, clientIP).Return(nil).Once()
+				loginAttemptHelper.On("ResetLoginFailures", mock.Anything, username).Return(nil).Once()
+
+				// Mock find user with a hash of a lower bcrypt cost
+				outdatedParams := _password.DefaultParams()
+				outdatedParams.BcryptCost = bcrypt.MinCost
+				outdatedHash, _ := _password.Hash(password, outdatedParams)
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
 					Username: username,
-					Password: password,
+					Password: outdatedHash,
+				}, nil).Once()
+
+				// Mock rehash with the plain password, hashed by the entity hook on save
+				repo.On("UpdatePassword", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.CheckPassword(password) == nil
+				})).Return(nil).Once()
+
+				// Mock session creation
+				sessionRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.Session")).Return(nil).Once()
+
+				// Mock token generation
+				oauthHelper.On("GenerateAccessToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return(accessToken, nil).Once()
+				oauthHelper.On("GenerateRefreshToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return(refreshToken, nil).Once()
+			},
+		},
+		{
+			name: "Login Requires MFA",
+			args: args{
+				ctx: ctx,
+				req: &model.UserLoginRequest{
+					Username:   username,
+					Password:   password,
+					ClientInfo: model.ClientInfo{ClientIP: clientIP},
+				},
+			},
+			want: &model.UserLoginResponse{
+				MfaRequired: true,
+				MfaToken:    "test-mfa-token",
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				// Failures are kept until the code step
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, clientIP).Return(nil).Once()
+
+				user := &entity.User{
+					ID:         uuid.New(),
+					Username:   username,
+					Password:   hashedPassword,
+					MfaEnabled: true,
+				}
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Username != nil && *filter.Username == username
+				})).Return(user, nil).Once()
+
+				// Tokens are only issued after the code step
+				oauthHelper.On("GenerateMfaChallengeToken", mock.Anything, user.ID).Return("test-mfa-token", nil).Once()
+			},
+		},
+		{
+			name: "Login Failed - User Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.UserLoginRequest{
+					Username:   username,
+					Password:   password,
+					ClientInfo: model.ClientInfo{ClientIP: clientIP},
 				},
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, clientIP).Return(nil).Once()
+
+				// Failed attempt is counted
+				loginAttemptHelper.On("RecordLoginFailure", mock.Anything, username, clientIP).Return(nil).Once()
+
 				// Mock user not found
 				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
 					return true
@@ -196,23 +320,144 @@ func Test_userService_Login(t *testing.T) {
 				})).Return(nil, gorm.ErrRecordNotFound).Once()
 			},
 		},
+		{
+			name: "Login Failed - Incorrect Password",
+			args: args{
+				ctx: ctx,
+				req: &model.UserLoginRequest{
+					Username:   username,
+					Password:   newPassword,
+					ClientInfo: model.ClientInfo{ClientIP: clientIP},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, clientIP).Return(nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					Username: username,
+					Password: hashedPassword,
+				}, nil).Once()
+
+				// Failed attempt is counted
+				loginAttemptHelper.On("RecordLoginFailure", mock.Anything, username, clientIP).Return(nil).Once()
+			},
+		},
+		{
+			name: "Login Failed - Suspended",
+			args: args{
+				ctx: ctx,
+				req: &model.UserLoginRequest{
+					Username:   username,
+					Password:   password,
+					ClientInfo: model.ClientInfo{ClientIP: clientIP},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, clientIP).Return(nil).Once()
+				suspendedUntil := time.Now().Add(time.Hour).Unix()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					Username:       username,
+					Password:       hashedPassword,
+					Status:         entity.USER_STATUS_SUSPENDED,
+					SuspendedUntil: &suspendedUntil,
+				}, nil).Once()
+			},
+		},
+		{
+			name: "Login Success - Suspension Ended",
+			args: args{
+				ctx: ctx,
+				req: &model.UserLoginRequest{
+					Username:   username,
+					Password:   password,
+					ClientInfo: model.ClientInfo{ClientIP: clientIP},
+				},
+			},
+			want: &model.UserLoginResponse{
+				AccessToken:  accessToken,
+				RefreshToken: refreshToken,
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, clientIP).Return(nil).Once()
+				loginAttemptHelper.On("ResetLoginFailures", mock.Anything, username).Return(nil).Once()
+				suspendedUntil := time.Now().Add(-time.Hour).Unix()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					Username:       username,
+					Password:       hashedPassword,
+					Status:         entity.USER_STATUS_SUSPENDED,
+					SuspendedUntil: &suspendedUntil,
+				}, nil).Once()
+				sessionRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.Session")).Return(nil).Once()
+				oauthHelper.On("GenerateAccessToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return(accessToken, nil).Once()
+				oauthHelper.On("GenerateRefreshToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return(refreshToken, nil).Once()
+			},
+		},
+		{
+			name: "Login Failed - Deleted",
+			args: args{
+				ctx: ctx,
+				req: &model.UserLoginRequest{
+					Username:   username,
+					Password:   password,
+					ClientInfo: model.ClientInfo{ClientIP: clientIP},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, clientIP).Return(nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					Username: username,
+					Password: hashedPassword,
+					Status:   entity.USER_STATUS_DELETED,
+				}, nil).Once()
+
+				// Deleted users count as unknown users
+				loginAttemptHelper.On("RecordLoginFailure", mock.Anything, username, clientIP).Return(nil).Once()
+			},
+		},
+		{
+			name: "Login Failed - Locked Out",
+			args: args{
+				ctx: ctx,
+				req: &model.UserLoginRequest{
+					Username:   username,
+					Password:   password,
+					ClientInfo: model.ClientInfo{ClientIP: clientIP},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				// Password isn't checked while locked
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, clientIP).Return(errors.New(errors.ErrCodeAccountLocked)).Once()
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Initialize mocks
+			sessionRepo := repo_mocks.NewISessionRepository(t)
 			repo := repo_mocks.NewIUserRepository(t)
 			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+			loginAttemptHelper := helper_mocks.NewILoginAttemptHelper(t)
 
 			// Setup mocks
-			tt.mock(repo, oauthHelper)
+			tt.mock(repo, oauthHelper, loginAttemptHelper, sessionRepo)
 
 			s := &userService{
 				postgresRepo: repository.RepositoryCollections{
-					UserRepo: repo,
+					UserRepo:    repo,
+					SessionRepo: sessionRepo,
 				},
 				helper: helper.HelperCollections{
-					OAuthHelper: oauthHelper,
+					OAuthHelper:        oauthHelper,
+					LoginAttemptHelper: loginAttemptHelper,
 				},
 			}
 
@@ -228,106 +473,153 @@ func Test_userService_Login(t *testing.T) {
 	}
 }
 
-func Test_userService_GetUsers(t *testing.T) {
+func Test_userService_LoginMfa(t *testing.T) {
 	type args struct {
 		ctx context.Context
-		req *model.GetUsersRequest
+		req *model.LoginMfaRequest
 	}
 
 	type testCase struct {
 		name    string
 		args    args
-		want    *model.GetUsersResponse
 		wantErr bool
-		mock    func(repo *repo_mocks.IUserRepository)
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository)
 	}
 
-	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
-		ID:   userID,
-		Role: adminRole,
-	})
-
-	users := []entity.User{
-		{
-			ID:       userID,
-			Username: username,
-			Fullname: fullname,
-			Role:     role,
-		},
+	ctx := context.Background()
+	userID := uuid.New()
+	mfaToken := "test-mfa-token"
+	secret, _ := totp.GenerateSecret()
+	recoveryCode := "abcde-12345"
+
+	newMfaUser := func() *entity.User {
+		return &entity.User{
+			ID:               userID,
+			Username:         username,
+			MfaEnabled:       true,
+			MfaSecret:        &secret,
+			MfaRecoveryCodes: []string{utils.HashToken(recoveryCode)},
+		}
 	}
 
 	tests := []testCase{
 		{
-			name: "Get Users Success",
+			name: "Login With TOTP Code Success",
 			args: args{
 				ctx: ctx,
-				req: &model.GetUsersRequest{
-					Name:  &fullname,
-					Role:  &role,
-					Page:  &page,
-					Limit: &limit,
-				},
-			},
-			want: &model.GetUsersResponse{
-				Users: users,
-				Count: 1,
+				req: &model.LoginMfaRequest{MfaToken: mfaToken},
 			},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IUserRepository) {
-				// Mock find users
-				repo.On("FindManyByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.Name != nil && *filter.Name == fullname &&
-						filter.Role != nil && *filter.Role == role &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
-				})).Return(users, nil).Once()
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oauthHelper.On("VerifyMfaChallengeToken", mock.Anything, mfaToken).Return(userID, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(newMfaUser(), nil).Once()
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, mock.Anything).Return(nil).Once()
 
-				// Mock count users
-				repo.On("CountByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.Name != nil && *filter.Name == fullname &&
-						filter.Role != nil && *filter.Role == role &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
-				})).Return(int64(1), nil).Once()
+				// Used time step is kept so the code can't be replayed
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.MfaLastStep != nil
+				})).Return(nil).Once()
+				loginAttemptHelper.On("ResetLoginFailures", mock.Anything, username).Return(nil).Once()
+				oauthHelper.On("RevokeMfaChallengeToken", mock.Anything, mfaToken).Return(nil).Once()
+
+				// Mock session creation
+				sessionRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.Session")).Return(nil).Once()
+
+				// Tokens are marked as two-factor verified
+				isMfaSession := mock.MatchedBy(func(session model.TokenSession) bool {
+					return session.MfaVerified && session.FamilyID != ""
+				})
+				oauthHelper.On("GenerateAccessToken", mock.Anything, mock.AnythingOfType("entity.User"), isMfaSession).Return("access", nil).Once()
+				oauthHelper.On("GenerateRefreshToken", mock.Anything, mock.AnythingOfType("entity.User"), isMfaSession).Return("refresh", nil).Once()
 			},
 		},
 		{
-			name: "Get Users Failed - Find Error",
+			name: "Login With Recovery Code Success",
 			args: args{
 				ctx: ctx,
-				req: &model.GetUsersRequest{
-					Name:  &fullname,
-					Role:  &role,
-					Page:  &page,
-					Limit: &limit,
-				},
+				req: &model.LoginMfaRequest{MfaToken: mfaToken, Code: recoveryCode},
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oauthHelper.On("VerifyMfaChallengeToken", mock.Anything, mfaToken).Return(userID, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(newMfaUser(), nil).Once()
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, mock.Anything).Return(nil).Once()
+
+				// Used recovery code is removed
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return len(user.MfaRecoveryCodes) == 0
+				})).Return(nil).Once()
+				loginAttemptHelper.On("ResetLoginFailures", mock.Anything, username).Return(nil).Once()
+				oauthHelper.On("RevokeMfaChallengeToken", mock.Anything, mfaToken).Return(nil).Once()
+				sessionRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.Session")).Return(nil).Once()
+				oauthHelper.On("GenerateAccessToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return("access", nil).Once()
+				oauthHelper.On("GenerateRefreshToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return("refresh", nil).Once()
+			},
+		},
+		{
+			name: "Login Failed - Invalid Code",
+			args: args{
+				ctx: ctx,
+				req: &model.LoginMfaRequest{MfaToken: mfaToken, Code: "000000x"},
 			},
-			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository) {
-				// Mock find users error
-				repo.On("FindManyByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.Name != nil && *filter.Name == fullname &&
-						filter.Role != nil && *filter.Role == role &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
-				})).Return(nil, errors.New(errors.ErrCodeInternalServerError)).Once()
-
-				// Mock count users error
-				repo.On("CountByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.Name != nil && *filter.Name == fullname &&
-						filter.Role != nil && *filter.Role == role &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
-				})).Return(int64(0), errors.New(errors.ErrCodeInternalServerError)).Once()
+			errCode: errors.ErrCodeInvalidMfaCode,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oauthHelper.On("VerifyMfaChallengeToken", mock.Anything, mfaToken).Return(userID, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(newMfaUser(), nil).Once()
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, mock.Anything).Return(nil).Once()
+
+				// Wrong codes count toward the user lockout
+				loginAttemptHelper.On("RecordLoginFailure", mock.Anything, username, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name: "Login Failed - Replayed Code",
+			args: args{
+				ctx: ctx,
+				req: &model.LoginMfaRequest{MfaToken: mfaToken},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeInvalidMfaCode,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oauthHelper.On("VerifyMfaChallengeToken", mock.Anything, mfaToken).Return(userID, nil).Once()
+
+				// A code of the current time step, or the next one in the skew, was already used
+				user := newMfaUser()
+				step := time.Now().Unix()/totp.PERIOD + 1
+				user.MfaLastStep = &step
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(user, nil).Once()
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, mock.Anything).Return(nil).Once()
+				loginAttemptHelper.On("RecordLoginFailure", mock.Anything, username, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name: "Login Failed - Account Locked",
+			args: args{
+				ctx: ctx,
+				req: &model.LoginMfaRequest{MfaToken: mfaToken, Code: recoveryCode},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeAccountLocked,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oauthHelper.On("VerifyMfaChallengeToken", mock.Anything, mfaToken).Return(userID, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(newMfaUser(), nil).Once()
+				loginAttemptHelper.On("CheckLoginLocked", mock.Anything, username, mock.Anything).Return(errors.New(errors.ErrCodeAccountLocked)).Once()
+			},
+		},
+		{
+			name: "Login Failed - Invalid Challenge Token",
+			args: args{
+				ctx: ctx,
+				req: &model.LoginMfaRequest{MfaToken: mfaToken, Code: recoveryCode},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeInvalidToken,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oauthHelper.On("VerifyMfaChallengeToken", mock.Anything, mfaToken).Return(uuid.Nil, errors.New(errors.ErrCodeInvalidToken)).Once()
 			},
 		},
 	}
@@ -335,95 +627,168 @@ func Test_userService_GetUsers(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Initialize mocks
+			sessionRepo := repo_mocks.NewISessionRepository(t)
 			repo := repo_mocks.NewIUserRepository(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+			loginAttemptHelper := helper_mocks.NewILoginAttemptHelper(t)
 
 			// Setup mocks
-			tt.mock(repo)
+			tt.mock(repo, oauthHelper, loginAttemptHelper, sessionRepo)
 
 			s := &userService{
 				postgresRepo: repository.RepositoryCollections{
-					UserRepo: repo,
+					UserRepo:    repo,
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					OAuthHelper:        oauthHelper,
+					LoginAttemptHelper: loginAttemptHelper,
 				},
 			}
 
-			got, err := s.GetUsers(tt.args.ctx, tt.args.req)
+			req := *tt.args.req
+			if req.Code == "" {
+				req.Code, _ = totp.GenerateCode(secret, time.Now())
+			}
+
+			got, err := s.LoginMfa(tt.args.ctx, &req)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("userService.GetUsers() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("userService.LoginMfa() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if err == nil && got == nil {
-				t.Error("userService.GetUsers() got nil response, want non-nil")
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.LoginMfa() error = %v, want code %v", err, tt.errCode)
+				}
+				return
+			}
+			if got == nil || got.AccessToken == "" || got.RefreshToken == "" {
+				t.Errorf("userService.LoginMfa() got = %v, want token pair", got)
 			}
 		})
 	}
 }
 
-func Test_userService_ForgetPassword(t *testing.T) {
+func Test_userService_OidcCallback(t *testing.T) {
 	type args struct {
 		ctx context.Context
-		req *model.ForgetPasswordRequest
+		req *model.OidcCallbackRequest
 	}
 
 	type testCase struct {
 		name    string
 		args    args
-		want    *model.ForgetPasswordResponse
 		wantErr bool
-		mock    func(repo *repo_mocks.IUserRepository)
+		errCode int
+		want    *model.UserLoginResponse
+		mock    func(repo *repo_mocks.IUserRepository, identityRepo *repo_mocks.IIdentityRepository, oidcHelper *helper_mocks.IOIDCHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository)
 	}
 
 	ctx := context.Background()
+	userID := uuid.New()
+	email := "alice@example.com"
+	req := &model.OidcCallbackRequest{State: "state", Code: "code"}
+	info := &model.OidcUserInfo{
+		Provider:      "google",
+		Subject:       "1234567890",
+		Email:         email,
+		EmailVerified: true,
+		Name:          "Alice",
+	}
+	mockTokens := func(oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+		sessionRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.Session")).Return(nil).Once()
+		oauthHelper.On("GenerateAccessToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return("access", nil).Once()
+		oauthHelper.On("GenerateRefreshToken", mock.Anything, mock.AnythingOfType("entity.User"), mock.AnythingOfType("model.TokenSession")).Return("refresh", nil).Once()
+	}
 
 	tests := []testCase{
 		{
-			name: "Forget Password Success",
-			args: args{
-				ctx: ctx,
-				req: &model.ForgetPasswordRequest{
-					Username:    username,
-					NewPassword: newPassword,
-				},
-			},
-			want:    &model.ForgetPasswordResponse{},
+			name:    "Login With Linked Identity Success",
+			args:    args{ctx: ctx, req: req},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IUserRepository) {
-				// Mock find user
-				user := &entity.User{
-					Username: username,
-					Password: hashedPassword,
-				}
-				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.Username != nil && *filter.Username == username
-				})).Return(user, nil).Once()
-
-				// Mock update user
-				repo.On("Update", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
-					return u.Username == username && u.Password == newPassword
+			want:    &model.UserLoginResponse{AccessToken: "access", RefreshToken: "refresh"},
+			mock: func(repo *repo_mocks.IUserRepository, identityRepo *repo_mocks.IIdentityRepository, oidcHelper *helper_mocks.IOIDCHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oidcHelper.On("ExchangeCode", mock.Anything, "state", "code").Return(info, nil).Once()
+				identityRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindIdentityByFilter) bool {
+					return *filter.Provider == info.Provider && *filter.Subject == info.Subject
+				})).Return(&entity.Identity{UserID: userID, Provider: info.Provider, Subject: info.Subject}, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(&entity.User{ID: userID, Username: username}, nil).Once()
+				identityRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(identity *entity.Identity) bool {
+					return identity.LastLoginAt > 0
 				})).Return(nil).Once()
+				mockTokens(oauthHelper, sessionRepo)
 			},
 		},
 		{
-			name: "Forget Password Failed - User Not Found",
-			args: args{
-				ctx: ctx,
-				req: &model.ForgetPasswordRequest{
-					Username:    username,
-					NewPassword: newPassword,
-				},
+			name:    "Login Links Existing User With Verified Email",
+			args:    args{ctx: ctx, req: req},
+			wantErr: false,
+			want:    &model.UserLoginResponse{AccessToken: "access", RefreshToken: "refresh"},
+			mock: func(repo *repo_mocks.IUserRepository, identityRepo *repo_mocks.IIdentityRepository, oidcHelper *helper_mocks.IOIDCHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oidcHelper.On("ExchangeCode", mock.Anything, "state", "code").Return(info, nil).Once()
+				identityRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Email != nil && *filter.Email == email
+				})).Return(&entity.User{ID: userID, Username: username, Email: &email, EmailVerified: true}, nil).Once()
+				identityRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(identity *entity.Identity) bool {
+					return identity.UserID == userID && identity.Subject == info.Subject
+				})).Return(nil).Once()
+				mockTokens(oauthHelper, sessionRepo)
 			},
-			want:    nil,
+		},
+		{
+			name:    "Login Creates User Just In Time",
+			args:    args{ctx: ctx, req: req},
+			wantErr: false,
+			want:    &model.UserLoginResponse{AccessToken: "access", RefreshToken: "refresh"},
+			mock: func(repo *repo_mocks.IUserRepository, identityRepo *repo_mocks.IIdentityRepository, oidcHelper *helper_mocks.IOIDCHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oidcHelper.On("ExchangeCode", mock.Anything, "state", "code").Return(info, nil).Once()
+				identityRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Twice()
+				repo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Username == "google_1234567890" &&
+						user.Fullname == info.Name &&
+						user.Role == entity.ROLE_USER &&
+						user.Email != nil && *user.Email == email &&
+						user.EmailVerified
+				})).Return(nil).Once()
+				identityRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.Identity")).Return(nil).Once()
+				mockTokens(oauthHelper, sessionRepo)
+			},
+		},
+		{
+			name:    "Login Returns MFA Challenge",
+			args:    args{ctx: ctx, req: req},
+			wantErr: false,
+			want:    &model.UserLoginResponse{MfaRequired: true, MfaToken: "mfa"},
+			mock: func(repo *repo_mocks.IUserRepository, identityRepo *repo_mocks.IIdentityRepository, oidcHelper *helper_mocks.IOIDCHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oidcHelper.On("ExchangeCode", mock.Anything, "state", "code").Return(info, nil).Once()
+				identityRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Identity{UserID: userID}, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, MfaEnabled: true}, nil).Once()
+				identityRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				oauthHelper.On("GenerateMfaChallengeToken", mock.Anything, userID).Return("mfa", nil).Once()
+			},
+		},
+		{
+			name:    "Login Failed - Unverified Local Email",
+			args:    args{ctx: ctx, req: req},
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository) {
-				// Mock user not found
-				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.Username != nil && *filter.Username == username
-				})).Return(nil, gorm.ErrRecordNotFound).Once()
+			errCode: errors.ErrCodeEmailExisted,
+			mock: func(repo *repo_mocks.IUserRepository, identityRepo *repo_mocks.IIdentityRepository, oidcHelper *helper_mocks.IOIDCHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oidcHelper.On("ExchangeCode", mock.Anything, "state", "code").Return(info, nil).Once()
+				identityRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Email: &email}, nil).Once()
+			},
+		},
+		{
+			name:    "Login Failed - Invalid State",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeOidcInvalidState,
+			mock: func(repo *repo_mocks.IUserRepository, identityRepo *repo_mocks.IIdentityRepository, oidcHelper *helper_mocks.IOIDCHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oidcHelper.On("ExchangeCode", mock.Anything, "state", "code").Return(nil, errors.New(errors.ErrCodeOidcInvalidState)).Once()
 			},
 		},
 	}
@@ -432,158 +797,124 @@ func Test_userService_ForgetPassword(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Initialize mocks
 			repo := repo_mocks.NewIUserRepository(t)
+			identityRepo := repo_mocks.NewIIdentityRepository(t)
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			oidcHelper := helper_mocks.NewIOIDCHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
 
 			// Setup mocks
-			tt.mock(repo)
+			tt.mock(repo, identityRepo, oidcHelper, oauthHelper, sessionRepo)
 
 			s := &userService{
 				postgresRepo: repository.RepositoryCollections{
-					UserRepo: repo,
+					UserRepo:     repo,
+					IdentityRepo: identityRepo,
+					SessionRepo:  sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					OIDCHelper:  oidcHelper,
+					OAuthHelper: oauthHelper,
 				},
 			}
 
-			got, err := s.ForgetPassword(tt.args.ctx, tt.args.req)
+			got, err := s.OidcCallback(tt.args.ctx, tt.args.req)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("userService.ForgetPassword() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("userService.OidcCallback() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if err == nil && got == nil {
-				t.Error("userService.ForgetPassword() got nil response, want non-nil")
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.OidcCallback() error = %v, want code %v", err, tt.errCode)
+				}
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("userService.OidcCallback() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_userService_ChangePassword(t *testing.T) {
+func Test_userService_ActivateMfa(t *testing.T) {
 	type args struct {
 		ctx context.Context
-		req *model.ChangeUserPasswordRequest
+		req *model.ActivateMfaRequest
 	}
 
 	type testCase struct {
 		name    string
 		args    args
-		want    *model.ChangeUserPasswordResponse
 		wantErr bool
-		mock    func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper)
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository)
 	}
 
 	userID := uuid.New()
-	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
-		ID: userID,
-	})
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{ID: userID})
+	secret, _ := totp.GenerateSecret()
+	code, _ := totp.GenerateCode(secret, time.Now())
 
 	tests := []testCase{
 		{
-			name: "Change Password Success",
+			name: "Activate Success",
 			args: args{
 				ctx: ctx,
-				req: &model.ChangeUserPasswordRequest{
-					ID:          userID,
-					OldPassword: password,
-					NewPassword: newPassword,
-				},
+				req: &model.ActivateMfaRequest{Code: code},
 			},
-			want:    &model.ChangeUserPasswordResponse{},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper) {
-				// Mock permission check
-				userHelper.On("IsValidPermission", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), userID).Return(true).Once()
-
-				// Mock find user
-				user := &entity.User{
-					ID:       userID,
-					Password: hashedPassword,
-				}
-				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.ID != nil && *filter.ID == userID
-				})).Return(user, nil).Once()
-
-				// Mock update user
-				repo.On("Update", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
-					return u.ID == userID && u.Password == newPassword
+			mock: func(repo *repo_mocks.IUserRepository) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:        userID,
+					MfaSecret: &secret,
+				}, nil).Once()
+
+				// Only hashed recovery codes are stored
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.MfaEnabled && len(user.MfaRecoveryCodes) == utils.MFA_RECOVERY_CODES
 				})).Return(nil).Once()
 			},
 		},
 		{
-			name: "Change Password Failed - Invalid Permission",
+			name: "Activate Failed - Not Enrolled",
 			args: args{
 				ctx: ctx,
-				req: &model.ChangeUserPasswordRequest{
-					ID:          userID,
-					OldPassword: password,
-					NewPassword: newPassword,
-				},
+				req: &model.ActivateMfaRequest{Code: code},
 			},
-			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper) {
-				// Mock invalid permission
-				userHelper.On("IsValidPermission", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), userID).Return(false).Once()
+			errCode: errors.ErrCodeMfaNotEnrolled,
+			mock: func(repo *repo_mocks.IUserRepository) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID}, nil).Once()
 			},
 		},
 		{
-			name: "Change Password Failed - User Not Found",
+			name: "Activate Failed - Already Enabled",
 			args: args{
 				ctx: ctx,
-				req: &model.ChangeUserPasswordRequest{
-					ID:          userID,
-					OldPassword: password,
-					NewPassword: newPassword,
-				},
+				req: &model.ActivateMfaRequest{Code: code},
 			},
-			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper) {
-				// Mock permission check
-				userHelper.On("IsValidPermission", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), userID).Return(true).Once()
-
-				// Mock user not found
-				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.ID != nil && *filter.ID == userID
-				})).Return(nil, gorm.ErrRecordNotFound).Once()
+			errCode: errors.ErrCodeMfaAlreadyEnabled,
+			mock: func(repo *repo_mocks.IUserRepository) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:         userID,
+					MfaEnabled: true,
+					MfaSecret:  &secret,
+				}, nil).Once()
 			},
 		},
 		{
-			name: "Change Password Failed - Incorrect Old Password",
+			name: "Activate Failed - Invalid Code",
 			args: args{
 				ctx: ctx,
-				req: &model.ChangeUserPasswordRequest{
-					ID:          userID,
-					OldPassword: "wrongpass",
-					NewPassword: newPassword,
-				},
+				req: &model.ActivateMfaRequest{Code: "abcdef"},
 			},
-			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper) {
-				// Mock permission check
-				userHelper.On("IsValidPermission", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), userID).Return(true).Once()
-
-				// Mock find user
-				user := &entity.User{
-					ID:       userID,
-					Password: hashedPassword,
-				}
-				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.ID != nil && *filter.ID == userID
-				})).Return(user, nil).Once()
+			errCode: errors.ErrCodeInvalidMfaCode,
+			mock: func(repo *repo_mocks.IUserRepository) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:        userID,
+					MfaSecret: &secret,
+				}, nil).Once()
 			},
 		},
 	}
@@ -592,162 +923,128 @@ func Test_userService_ChangePassword(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Initialize mocks
 			repo := repo_mocks.NewIUserRepository(t)
-			userHelper := helper_mocks.NewIUserHelper(t)
 
 			// Setup mocks
-			tt.mock(repo, userHelper)
+			tt.mock(repo)
 
 			s := &userService{
 				postgresRepo: repository.RepositoryCollections{
 					UserRepo: repo,
 				},
-				helper: helper.HelperCollections{
-					UserHelper: userHelper,
-				},
 			}
 
-			got, err := s.ChangePassword(tt.args.ctx, tt.args.req)
+			got, err := s.ActivateMfa(tt.args.ctx, tt.args.req)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("userService.ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("userService.ActivateMfa() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if err == nil && got == nil {
-				t.Error("userService.ChangePassword() got nil response, want non-nil")
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.ActivateMfa() error = %v, want code %v", err, tt.errCode)
+				}
+				return
+			}
+			if got == nil || len(got.RecoveryCodes) != utils.MFA_RECOVERY_CODES {
+				t.Errorf("userService.ActivateMfa() got = %v, want %d recovery codes", got, utils.MFA_RECOVERY_CODES)
 			}
 		})
 	}
 }
 
-func Test_userService_UpdateUser(t *testing.T) {
+func Test_userService_RefreshToken(t *testing.T) {
 	type args struct {
 		ctx context.Context
-		req *model.UpdateUserRequest
+		req *model.RefreshTokenRequest
 	}
 
 	type testCase struct {
 		name    string
 		args    args
-		want    *model.UpdateUserResponse
+		want    *model.RefreshTokenResponse
 		wantErr bool
-		mock    func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper)
+		mock    func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository)
 	}
 
-	userID := uuid.New()
-	newUsername := "newuser"
-	newFullname := "New User"
-	adminRole := entity.ROLE_ADMIN
-
-	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
-		ID:   userID,
-		Role: adminRole,
-	})
+	ctx := context.Background()
+	familyID := uuid.NewString()
+	oldRefreshToken := "test-old-refresh-token"
+	accessToken := "test-access-token"
+	refreshToken := "test-refresh-token"
+	clientIP := "127.0.0.1"
+	payload := &model.UserJWTPayload{
+		UserID:   userID,
+		FamilyID: familyID,
+	}
 
 	tests := []testCase{
 		{
-			name: "Update User Success",
+			name: "Refresh Token Success",
 			args: args{
 				ctx: ctx,
-				req: &model.UpdateUserRequest{
-					ID:       userID,
-					Username: &newUsername,
-					Fullname: &newFullname,
-					Role:     &role,
+				req: &model.RefreshTokenRequest{
+					ClientInfo:   model.ClientInfo{ClientIP: clientIP},
+					RefreshToken: oldRefreshToken,
 				},
 			},
-			want: &model.UpdateUserResponse{
-				User: entity.User{
-					ID:       userID,
-					Username: newUsername,
-					Fullname: newFullname,
-					Role:     role,
-				},
+			want: &model.RefreshTokenResponse{
+				AccessToken:  accessToken,
+				RefreshToken: refreshToken,
 			},
 			wantErr: false,
-			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper) {
-				// Mock permission check
-				userHelper.On("IsValidPermission", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), userID).Return(true).Once()
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				// Mock rotate refresh token
+				oauthHelper.On("RotateRefreshToken", mock.Anything, oldRefreshToken).Return(payload, nil).Once()
 
 				// Mock find user
-				user := &entity.User{
-					ID:       userID,
-					Username: username,
-					Fullname: fullname,
-					Role:     role,
-				}
 				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
 					return filter.ID != nil && *filter.ID == userID
-				})).Return(user, nil).Once()
-
-				// Mock role validation
-				userHelper.On("IsValidRole", role).Return(true).Once()
-
-				// Mock update user
-				repo.On("Update", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
-					return u.ID == userID &&
-						u.Username == newUsername &&
-						u.Fullname == newFullname &&
-						u.Role == role
+				})).Return(&entity.User{ID: userID}, nil).Once()
+
+				// Mock active session lookup and last seen update
+				sessionRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindSessionByFilter) bool {
+					return filter.ID != nil && filter.ID.String() == familyID && filter.Active != nil && *filter.Active
+				})).Return(&entity.Session{ID: uuid.MustParse(familyID), UserID: userID}, nil).Once()
+				sessionRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(session *entity.Session) bool {
+					return session.IPAddress == clientIP
 				})).Return(nil).Once()
+
+				// Mock token generation in the same family
+				oauthHelper.On("GenerateAccessToken", mock.Anything, mock.AnythingOfType("entity.User"), model.TokenSession{FamilyID: familyID}).Return(accessToken, nil).Once()
+				oauthHelper.On("GenerateRefreshToken", mock.Anything, mock.AnythingOfType("entity.User"), model.TokenSession{FamilyID: familyID}).Return(refreshToken, nil).Once()
 			},
 		},
 		{
-			name: "Update User Failed - Invalid Permission",
+			name: "Refresh Token Failed - Token Reused",
 			args: args{
 				ctx: ctx,
-				req: &model.UpdateUserRequest{
-					ID:       userID,
-					Username: &newUsername,
-				},
-			},
-			want:    nil,
-			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper) {
-				// Mock invalid permission
-				userHelper.On("IsValidPermission", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), userID).Return(false).Once()
-			},
-		},
-		{
-			name: "Update User Failed - No User Context",
-			args: args{
-				ctx: context.Background(),
-				req: &model.UpdateUserRequest{
-					ID:       userID,
-					Username: &newUsername,
+				req: &model.RefreshTokenRequest{
+					ClientInfo:   model.ClientInfo{ClientIP: clientIP},
+					RefreshToken: oldRefreshToken,
 				},
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper) {
-				// Mock no user context
-				userHelper.On("IsValidPermission", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), userID).Return(false).Once()
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				// Mock reuse detected
+				oauthHelper.On("RotateRefreshToken", mock.Anything, oldRefreshToken).Return(nil, errors.New(errors.ErrCodeTokenReused)).Once()
 			},
 		},
 		{
-			name: "Update User Failed - User Not Found",
+			name: "Refresh Token Failed - User Not Found",
 			args: args{
 				ctx: ctx,
-				req: &model.UpdateUserRequest{
-					ID:       userID,
-					Username: &newUsername,
+				req: &model.RefreshTokenRequest{
+					ClientInfo:   model.ClientInfo{ClientIP: clientIP},
+					RefreshToken: oldRefreshToken,
 				},
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper) {
-				// Mock permission check
-				userHelper.On("IsValidPermission", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), userID).Return(true).Once()
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				// Mock rotate refresh token
+				oauthHelper.On("RotateRefreshToken", mock.Anything, oldRefreshToken).Return(payload, nil).Once()
 
 				// Mock user not found
 				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
@@ -758,37 +1055,21 @@ func Test_userService_UpdateUser(t *testing.T) {
 			},
 		},
 		{
-			name: "Update User Failed - Invalid Role",
+			name: "Refresh Token Failed - Session Revoked",
 			args: args{
 				ctx: ctx,
-				req: &model.UpdateUserRequest{
-					ID:   userID,
-					Role: &role,
+				req: &model.RefreshTokenRequest{
+					RefreshToken: oldRefreshToken,
 				},
 			},
 			want:    nil,
 			wantErr: true,
-			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper) {
-				// Mock permission check
-				userHelper.On("IsValidPermission", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), userID).Return(true).Once()
-
-				// Mock find user
-				user := &entity.User{
-					ID:       userID,
-					Username: username,
-					Fullname: fullname,
-					Role:     role,
-				}
-				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
-					return true
-				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
-					return filter.ID != nil && *filter.ID == userID
-				})).Return(user, nil).Once()
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				oauthHelper.On("RotateRefreshToken", mock.Anything, oldRefreshToken).Return(payload, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID}, nil).Once()
 
-				// Mock invalid role
-				userHelper.On("IsValidRole", role).Return(false).Once()
+				// Mock session revoked from another device
+				sessionRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
 			},
 		},
 	}
@@ -796,28 +1077,2610 @@ func Test_userService_UpdateUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Initialize mocks
+			sessionRepo := repo_mocks.NewISessionRepository(t)
 			repo := repo_mocks.NewIUserRepository(t)
-			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
 
 			// Setup mocks
-			tt.mock(repo, userHelper)
+			tt.mock(repo, oauthHelper, sessionRepo)
 
 			s := &userService{
 				postgresRepo: repository.RepositoryCollections{
-					UserRepo: repo,
+					UserRepo:    repo,
+					SessionRepo: sessionRepo,
 				},
 				helper: helper.HelperCollections{
-					UserHelper: userHelper,
+					OAuthHelper: oauthHelper,
 				},
 			}
 
-			got, err := s.UpdateUser(tt.args.ctx, tt.args.req)
+			got, err := s.RefreshToken(tt.args.ctx, tt.args.req)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("userService.UpdateUser() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("userService.RefreshToken() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if err == nil && got == nil {
-				t.Error("userService.UpdateUser() got nil response, want non-nil")
+				t.Error("userService.RefreshToken() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_userService_Logout(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.LogoutRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.LogoutResponse
+		wantErr bool
+		mock    func(oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository)
+	}
+
+	payload := &model.UserJWTPayload{
+		UserID:   userID,
+		FamilyID: uuid.NewString(),
+	}
+	ctx := context.WithValue(context.Background(), string(utils.TOKEN_CONTEXT_KEY), payload)
+	adminID := uuid.New()
+	impersonationPayload := &model.UserJWTPayload{
+		UserID:         userID,
+		FamilyID:       uuid.NewString(),
+		ImpersonatorID: &adminID,
+	}
+
+	tests := []testCase{
+		{
+			name: "Logout Success",
+			args: args{
+				ctx: ctx,
+				req: &model.LogoutRequest{},
+			},
+			want:    &model.LogoutResponse{},
+			wantErr: false,
+			mock: func(oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				// Mock revoke access token and session
+				oauthHelper.On("RevokeAccessToken", mock.Anything, payload).Return(nil).Once()
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindSessionByFilter) bool {
+					return filter.ID != nil && filter.ID.String() == payload.FamilyID && filter.UserID != nil && *filter.UserID == userID
+				})).Return(nil).Once()
+				oauthHelper.On("RevokeSession", mock.Anything, payload.FamilyID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Logout Impersonation Keeps Admin Session",
+			args: args{
+				ctx: context.WithValue(context.Background(), string(utils.TOKEN_CONTEXT_KEY), impersonationPayload),
+				req: &model.LogoutRequest{},
+			},
+			want:    &model.LogoutResponse{},
+			wantErr: false,
+			mock: func(oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				// Only the impersonation token is revoked
+				oauthHelper.On("RevokeAccessToken", mock.Anything, impersonationPayload).Return(nil).Once()
+			},
+		},
+		{
+			name: "Logout Failed - No Token Context",
+			args: args{
+				ctx: context.Background(),
+				req: &model.LogoutRequest{},
+			},
+			want:    nil,
+			wantErr: true,
+			mock:    func(oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(oauthHelper, sessionRepo)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			got, err := s.Logout(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.Logout() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got == nil {
+				t.Error("userService.Logout() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_userService_LogoutAll(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.LogoutAllRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.LogoutAllResponse
+		wantErr bool
+		mock    func(oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository)
+	}
+
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+
+	tests := []testCase{
+		{
+			name: "Logout All Success",
+			args: args{
+				ctx: ctx,
+				req: &model.LogoutAllRequest{},
+			},
+			want:    &model.LogoutAllResponse{},
+			wantErr: false,
+			mock: func(oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				// Mock revoke all tokens
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.AnythingOfType("*repository.FindSessionByFilter")).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Logout All Failed - Revoke Error",
+			args: args{
+				ctx: ctx,
+				req: &model.LogoutAllRequest{},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository) {
+				// Mock revoke error
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.AnythingOfType("*repository.FindSessionByFilter")).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, userID).Return(errors.New(errors.ErrCodeInternalServerError)).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(oauthHelper, sessionRepo)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			got, err := s.LogoutAll(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.LogoutAll() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got == nil {
+				t.Error("userService.LogoutAll() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_userService_RevokeSession(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.RevokeSessionRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.RevokeSessionResponse
+		wantErr bool
+		mock    func(sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper)
+	}
+
+	sessionID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+
+	tests := []testCase{
+		{
+			name: "Revoke Session Success",
+			args: args{
+				ctx: ctx,
+				req: &model.RevokeSessionRequest{ID: sessionID},
+			},
+			want:    &model.RevokeSessionResponse{},
+			wantErr: false,
+			mock: func(sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				// Only sessions of the request user are looked up
+				sessionRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindSessionByFilter) bool {
+					return *filter.ID == sessionID && *filter.UserID == userID
+				})).Return(&entity.Session{ID: sessionID, UserID: userID}, nil).Once()
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.AnythingOfType("*repository.FindSessionByFilter")).Return(nil).Once()
+				oauthHelper.On("RevokeSession", mock.Anything, sessionID.String()).Return(nil).Once()
+			},
+		},
+		{
+			name: "Revoke Session Failed - Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.RevokeSessionRequest{ID: sessionID},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				sessionRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(sessionRepo, oauthHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			got, err := s.RevokeSession(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.RevokeSession() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got == nil {
+				t.Error("userService.RevokeSession() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_userService_SendVerificationCode(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.SendVerificationCodeRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.SendVerificationCodeResponse
+		wantErr bool
+		mock    func(userRepo *repo_mocks.IUserRepository, verificationHelper *helper_mocks.IVerificationHelper, notificationHelper *helper_mocks.INotificationHelper)
+	}
+
+	email := "user@example.com"
+	code := "123456"
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+
+	tests := []testCase{
+		{
+			name: "Send Verification Code Success",
+			args: args{
+				ctx: ctx,
+				req: &model.SendVerificationCodeRequest{Channel: entity.VERIFICATION_CHANNEL_EMAIL},
+			},
+			want:    &model.SendVerificationCodeResponse{},
+			wantErr: false,
+			mock: func(userRepo *repo_mocks.IUserRepository, verificationHelper *helper_mocks.IVerificationHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:    userID,
+					Email: &email,
+				}, nil).Once()
+				verificationHelper.On("GenerateVerificationCode", mock.Anything, userID, entity.VERIFICATION_CHANNEL_EMAIL, email).Return(code, nil).Once()
+				notificationHelper.On("SendVerificationCode", mock.Anything, entity.VERIFICATION_CHANNEL_EMAIL, email, code).Return(nil).Once()
+			},
+		},
+		{
+			name: "Send Verification Code Failed - No Phone Number",
+			args: args{
+				ctx: ctx,
+				req: &model.SendVerificationCodeRequest{Channel: entity.VERIFICATION_CHANNEL_PHONE},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(userRepo *repo_mocks.IUserRepository, verificationHelper *helper_mocks.IVerificationHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:    userID,
+					Email: &email,
+				}, nil).Once()
+			},
+		},
+		{
+			name: "Send Verification Code Failed - Already Verified",
+			args: args{
+				ctx: ctx,
+				req: &model.SendVerificationCodeRequest{Channel: entity.VERIFICATION_CHANNEL_EMAIL},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(userRepo *repo_mocks.IUserRepository, verificationHelper *helper_mocks.IVerificationHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:            userID,
+					Email:         &email,
+					EmailVerified: true,
+				}, nil).Once()
+			},
+		},
+		{
+			name: "Send Verification Code Failed - Resend Too Soon",
+			args: args{
+				ctx: ctx,
+				req: &model.SendVerificationCodeRequest{Channel: entity.VERIFICATION_CHANNEL_EMAIL},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(userRepo *repo_mocks.IUserRepository, verificationHelper *helper_mocks.IVerificationHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:    userID,
+					Email: &email,
+				}, nil).Once()
+				verificationHelper.On("GenerateVerificationCode", mock.Anything, userID, entity.VERIFICATION_CHANNEL_EMAIL, email).Return("", errors.New(errors.ErrCodeRateLimitExceeded)).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			userRepo := repo_mocks.NewIUserRepository(t)
+			verificationHelper := helper_mocks.NewIVerificationHelper(t)
+			notificationHelper := helper_mocks.NewINotificationHelper(t)
+
+			// Setup mocks
+			tt.mock(userRepo, verificationHelper, notificationHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: userRepo,
+				},
+				helper: helper.HelperCollections{
+					VerificationHelper: verificationHelper,
+					NotificationHelper: notificationHelper,
+				},
+			}
+
+			got, err := s.SendVerificationCode(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.SendVerificationCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if (got == nil) != (tt.want == nil) {
+				t.Errorf("userService.SendVerificationCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_userService_ConfirmVerificationCode(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.ConfirmVerificationCodeRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		mock    func(userRepo *repo_mocks.IUserRepository, verificationHelper *helper_mocks.IVerificationHelper)
+	}
+
+	phone := "0912345678"
+	code := "123456"
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+
+	tests := []testCase{
+		{
+			name: "Confirm Verification Code Success",
+			args: args{
+				ctx: ctx,
+				req: &model.ConfirmVerificationCodeRequest{Channel: entity.VERIFICATION_CHANNEL_PHONE, Code: code},
+			},
+			wantErr: false,
+			mock: func(userRepo *repo_mocks.IUserRepository, verificationHelper *helper_mocks.IVerificationHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:    userID,
+					Phone: &phone,
+				}, nil).Once()
+				verificationHelper.On("VerifyVerificationCode", mock.Anything, userID, entity.VERIFICATION_CHANNEL_PHONE, phone, code).Return(nil).Once()
+				// Phone is marked as verified
+				userRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.PhoneVerified
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Confirm Verification Code Failed - Invalid Code",
+			args: args{
+				ctx: ctx,
+				req: &model.ConfirmVerificationCodeRequest{Channel: entity.VERIFICATION_CHANNEL_PHONE, Code: "000000"},
+			},
+			wantErr: true,
+			mock: func(userRepo *repo_mocks.IUserRepository, verificationHelper *helper_mocks.IVerificationHelper) {
+				userRepo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:    userID,
+					Phone: &phone,
+				}, nil).Once()
+				verificationHelper.On("VerifyVerificationCode", mock.Anything, userID, entity.VERIFICATION_CHANNEL_PHONE, phone, "000000").Return(errors.New(errors.ErrCodeInvalidVerificationCode)).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			userRepo := repo_mocks.NewIUserRepository(t)
+			verificationHelper := helper_mocks.NewIVerificationHelper(t)
+
+			// Setup mocks
+			tt.mock(userRepo, verificationHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: userRepo,
+				},
+				helper: helper.HelperCollections{
+					VerificationHelper: verificationHelper,
+				},
+			}
+
+			got, err := s.ConfirmVerificationCode(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.ConfirmVerificationCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !got.User.PhoneVerified {
+				t.Error("userService.ConfirmVerificationCode() phone is not verified")
+			}
+		})
+	}
+}
+
+func Test_userService_GetUsers(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.GetUsersRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.GetUsersResponse
+		wantErr bool
+		mock    func(repo *repo_mocks.IUserRepository)
+	}
+
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID:   userID,
+		Role: adminRole,
+	})
+
+	users := []entity.User{
+		{
+			ID:       userID,
+			Username: username,
+			Fullname: fullname,
+			Role:     role,
+		},
+	}
+
+	count := int64(1)
+	tests := []testCase{
+		{
+			name: "Get Users Success",
+			args: args{
+				ctx: ctx,
+				req: &model.GetUsersRequest{
+					Name:  &fullname,
+					Role:  &role,
+					Page:  &page,
+					Limit: &limit,
+				},
+			},
+			want: &model.GetUsersResponse{
+				Users: users,
+				Count: &count,
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository) {
+				// Mock find users
+				repo.On("FindManyByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Name != nil && *filter.Name == fullname &&
+						filter.Role != nil && *filter.Role == role &&
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
+				})).Return(users, nil).Once()
+
+				// Mock count users
+				repo.On("CountByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Name != nil && *filter.Name == fullname &&
+						filter.Role != nil && *filter.Role == role &&
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
+				})).Return(int64(1), nil).Once()
+			},
+		},
+		{
+			name: "Get Users Failed - Find Error",
+			args: args{
+				ctx: ctx,
+				req: &model.GetUsersRequest{
+					Name:  &fullname,
+					Role:  &role,
+					Page:  &page,
+					Limit: &limit,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository) {
+				// Mock find users error
+				repo.On("FindManyByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Name != nil && *filter.Name == fullname &&
+						filter.Role != nil && *filter.Role == role &&
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
+				})).Return(nil, errors.New(errors.ErrCodeInternalServerError)).Once()
+
+				// Mock count users error
+				repo.On("CountByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Name != nil && *filter.Name == fullname &&
+						filter.Role != nil && *filter.Role == role &&
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
+				})).Return(int64(0), errors.New(errors.ErrCodeInternalServerError)).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+
+			// Setup mocks
+			tt.mock(repo)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: repo,
+				},
+			}
+
+			got, err := s.GetUsers(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.GetUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got == nil {
+				t.Error("userService.GetUsers() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_userService_ForgetPassword(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.ForgetPasswordRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.ForgetPasswordResponse
+		wantErr bool
+		mock    func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, notificationHelper *helper_mocks.INotificationHelper)
+	}
+
+	ctx := context.Background()
+	resetToken := "test-reset-token"
+
+	tests := []testCase{
+		{
+			name: "Forget Password Success",
+			args: args{
+				ctx: ctx,
+				req: &model.ForgetPasswordRequest{
+					Username: username,
+				},
+			},
+			want:    &model.ForgetPasswordResponse{},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				// Mock find user
+				user := &entity.User{
+					ID:       userID,
+					Username: username,
+				}
+				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Username != nil && *filter.Username == username
+				})).Return(user, nil).Once()
+
+				// Mock reset token generation and delivery
+				oauthHelper.On("GeneratePasswordResetToken", mock.Anything, userID).Return(resetToken, nil).Once()
+				notificationHelper.On("SendPasswordResetToken", mock.Anything, *user, resetToken).Return(nil).Once()
+			},
+		},
+		{
+			name: "Forget Password Success - User Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.ForgetPasswordRequest{
+					Username: username,
+				},
+			},
+			want:    &model.ForgetPasswordResponse{},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				// Mock user not found, no token must be issued
+				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Username != nil && *filter.Username == username
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+		{
+			name: "Forget Password Failed - Notify Error",
+			args: args{
+				ctx: ctx,
+				req: &model.ForgetPasswordRequest{
+					Username: username,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				// Mock find user
+				user := &entity.User{
+					ID:       userID,
+					Username: username,
+				}
+				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Username != nil && *filter.Username == username
+				})).Return(user, nil).Once()
+
+				// Mock reset token generation and failed delivery
+				oauthHelper.On("GeneratePasswordResetToken", mock.Anything, userID).Return(resetToken, nil).Once()
+				notificationHelper.On("SendPasswordResetToken", mock.Anything, *user, resetToken).Return(errors.New(errors.ErrCodeInternalServerError)).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+			notificationHelper := helper_mocks.NewINotificationHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, oauthHelper, notificationHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: repo,
+				},
+				helper: helper.HelperCollections{
+					OAuthHelper:        oauthHelper,
+					NotificationHelper: notificationHelper,
+				},
+			}
+
+			got, err := s.ForgetPassword(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.ForgetPassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got == nil {
+				t.Error("userService.ForgetPassword() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_userService_ResetPassword(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.ResetPasswordRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.ResetPasswordResponse
+		wantErr bool
+		mock    func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper)
+	}
+
+	ctx := context.Background()
+	resetToken := "test-reset-token"
+
+	tests := []testCase{
+		{
+			name: "Reset Password Success",
+			args: args{
+				ctx: ctx,
+				req: &model.ResetPasswordRequest{
+					Token:       resetToken,
+					NewPassword: newPassword,
+				},
+			},
+			want:    &model.ResetPasswordResponse{},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				// Mock consume reset token
+				oauthHelper.On("ConsumePasswordResetToken", mock.Anything, resetToken).Return(userID, nil).Once()
+
+				// Mock find user
+				user := &entity.User{
+					ID:       userID,
+					Username: username,
+					Password: hashedPassword,
+				}
+				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(user, nil).Once()
+
+				// Mock password history
+				passwordHelper.On("CheckPasswordReuse", mock.Anything, user, newPassword).Return(nil).Once()
+				passwordHelper.On("RecordPassword", mock.Anything, mock.Anything, user).Return(nil).Once()
+
+				// Mock update user
+				repo.On("Update", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
+					return u.ID == userID && u.CheckPassword(newPassword) == nil
+				})).Return(nil).Once()
+
+				// Mock revoke all tokens
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.AnythingOfType("*repository.FindSessionByFilter")).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Reset Password Failed - Password Reused",
+			args: args{
+				ctx: ctx,
+				req: &model.ResetPasswordRequest{
+					Token:       resetToken,
+					NewPassword: password,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				// Mock consume reset token
+				oauthHelper.On("ConsumePasswordResetToken", mock.Anything, resetToken).Return(userID, nil).Once()
+
+				// Mock find user
+				user := &entity.User{
+					ID:       userID,
+					Username: username,
+					Password: hashedPassword,
+				}
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(user, nil).Once()
+
+				// Mock password is the current one
+				passwordHelper.On("CheckPasswordReuse", mock.Anything, user, password).Return(errors.New(errors.ErrCodePasswordReused)).Once()
+			},
+		},
+		{
+			name: "Reset Password Failed - Invalid Token",
+			args: args{
+				ctx: ctx,
+				req: &model.ResetPasswordRequest{
+					Token:       resetToken,
+					NewPassword: newPassword,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				// Mock invalid or already used token
+				oauthHelper.On("ConsumePasswordResetToken", mock.Anything, resetToken).Return(uuid.Nil, errors.New(errors.ErrCodeInvalidToken)).Once()
+			},
+		},
+		{
+			name: "Reset Password Failed - User Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.ResetPasswordRequest{
+					Token:       resetToken,
+					NewPassword: newPassword,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				// Mock consume reset token
+				oauthHelper.On("ConsumePasswordResetToken", mock.Anything, resetToken).Return(userID, nil).Once()
+
+				// Mock user not found
+				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			repo := repo_mocks.NewIUserRepository(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+			passwordHelper := helper_mocks.NewIPasswordHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, oauthHelper, sessionRepo, passwordHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:    repo,
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					OAuthHelper:    oauthHelper,
+					PasswordHelper: passwordHelper,
+				},
+			}
+
+			got, err := s.ResetPassword(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.ResetPassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got == nil {
+				t.Error("userService.ResetPassword() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_userService_ChangePassword(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.ChangeUserPasswordRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		want    *model.ChangeUserPasswordResponse
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper)
+	}
+
+	userID := uuid.New()
+	otherUserID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID: userID,
+	})
+
+	tests := []testCase{
+		{
+			name: "Change Password Success",
+			args: args{
+				ctx: ctx,
+				req: &model.ChangeUserPasswordRequest{
+					ID:          userID,
+					OldPassword: password,
+					NewPassword: newPassword,
+				},
+			},
+			want:    &model.ChangeUserPasswordResponse{},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				// Mock find user
+				user := &entity.User{
+					ID:       userID,
+					Password: hashedPassword,
+				}
+				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(user, nil).Once()
+
+				// Mock password history
+				passwordHelper.On("CheckPasswordReuse", mock.Anything, user, newPassword).Return(nil).Once()
+				passwordHelper.On("RecordPassword", mock.Anything, mock.Anything, user).Return(nil).Once()
+
+				// Mock update user
+				repo.On("Update", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
+					return u.ID == userID && u.CheckPassword(newPassword) == nil
+				})).Return(nil).Once()
+
+				// Mock revoke all tokens
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.AnythingOfType("*repository.FindSessionByFilter")).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Change Password Of Other User Success - Staff Skips Old Password",
+			args: args{
+				ctx: ctx,
+				req: &model.ChangeUserPasswordRequest{
+					ID:          otherUserID,
+					NewPassword: newPassword,
+				},
+			},
+			want:    &model.ChangeUserPasswordResponse{},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				// Mock permission check
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(true, nil).Once()
+
+				user := &entity.User{
+					ID:       otherUserID,
+					Password: hashedPassword,
+				}
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(user, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, user).Return([]string{}, nil).Once()
+				passwordHelper.On("CheckPasswordReuse", mock.Anything, user, newPassword).Return(nil).Once()
+				repo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				passwordHelper.On("RecordPassword", mock.Anything, mock.Anything, user).Return(nil).Once()
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, otherUserID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Change Password Failed - Staff Changes Password Of Admin",
+			args: args{
+				ctx: ctx,
+				req: &model.ChangeUserPasswordRequest{
+					ID:          otherUserID,
+					NewPassword: newPassword,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(true, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: otherUserID, Role: entity.ROLE_ADMIN}, nil).Once()
+			},
+		},
+		{
+			name: "Change Password Failed - Staff Changes Password Of Role Manager",
+			args: args{
+				ctx: ctx,
+				req: &model.ChangeUserPasswordRequest{
+					ID:          otherUserID,
+					NewPassword: newPassword,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(true, nil).Once()
+				user := &entity.User{ID: otherUserID, Role: entity.ROLE_USER}
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(user, nil).Once()
+
+				// The target can manage roles, the request user can't
+				userHelper.On("GetStaffPermissions", mock.Anything, user).Return([]string{entity.PERMISSION_ROLE_MANAGE}, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, mock.Anything).Return([]string{entity.PERMISSION_USER_WRITE}, nil).Once()
+			},
+		},
+		{
+			name: "Change Password Success - Admin Changes Password Of Admin",
+			args: args{
+				ctx: context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+					ID:   userID,
+					Role: entity.ROLE_ADMIN,
+				}),
+				req: &model.ChangeUserPasswordRequest{
+					ID:          otherUserID,
+					NewPassword: newPassword,
+				},
+			},
+			want:    &model.ChangeUserPasswordResponse{},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(true, nil).Once()
+				user := &entity.User{ID: otherUserID, Role: entity.ROLE_ADMIN, Password: hashedPassword}
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(user, nil).Once()
+				passwordHelper.On("CheckPasswordReuse", mock.Anything, user, newPassword).Return(nil).Once()
+				repo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				passwordHelper.On("RecordPassword", mock.Anything, mock.Anything, user).Return(nil).Once()
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, otherUserID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Change Password Failed - Other User Without Permission",
+			args: args{
+				ctx: ctx,
+				req: &model.ChangeUserPasswordRequest{
+					ID:          otherUserID,
+					OldPassword: password,
+					NewPassword: newPassword,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				// Mock missing permission
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(false, nil).Once()
+			},
+		},
+		{
+			name: "Change Password Failed - User Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.ChangeUserPasswordRequest{
+					ID:          userID,
+					OldPassword: password,
+					NewPassword: newPassword,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				// Mock user not found
+				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+		{
+			name: "Change Password Failed - Incorrect Old Password",
+			args: args{
+				ctx: ctx,
+				req: &model.ChangeUserPasswordRequest{
+					ID:          userID,
+					OldPassword: "wrongpass",
+					NewPassword: newPassword,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, sessionRepo *repo_mocks.ISessionRepository, passwordHelper *helper_mocks.IPasswordHelper) {
+				// Mock find user
+				user := &entity.User{
+					ID:       userID,
+					Password: hashedPassword,
+				}
+				repo.On("FindOneByFilter", mock.MatchedBy(func(c context.Context) bool {
+					return true
+				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(user, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			repo := repo_mocks.NewIUserRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+			passwordHelper := helper_mocks.NewIPasswordHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, userHelper, oauthHelper, sessionRepo, passwordHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:    repo,
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:     userHelper,
+					OAuthHelper:    oauthHelper,
+					PasswordHelper: passwordHelper,
+				},
+			}
+
+			got, err := s.ChangePassword(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errCode != 0 && err.(*errors.CustomError).Code != tt.errCode {
+				t.Errorf("userService.ChangePassword() error code = %v, want %v", err.(*errors.CustomError).Code, tt.errCode)
+			}
+			if err == nil && got == nil {
+				t.Error("userService.ChangePassword() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_userService_UpdateUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.UpdateUserRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper)
+	}
+
+	userID := uuid.New()
+	otherUserID := uuid.New()
+	newUsername := "newuser"
+	newFullname := "New User"
+	adminRole := entity.ROLE_ADMIN
+	userRole := entity.ROLE_USER
+	invalidRole := "superuser"
+
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{
+		ID:   userID,
+		Role: role,
+	})
+
+	tests := []testCase{
+		{
+			name: "Update User Success - Owner",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:       userID,
+					Username: &newUsername,
+					Fullname: &newFullname,
+				},
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				// Owners need no permission
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(&entity.User{ID: userID, Username: username, Fullname: fullname, Role: role}, nil).Once()
+
+				// Mock username check
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Username != nil && *filter.Username == newUsername
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
+					return u.ID == userID &&
+						u.Username == newUsername &&
+						u.Fullname == newFullname &&
+						u.Role == role
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Update User Failed - Staff Promotes Other User To Admin",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:   otherUserID,
+					Role: &adminRole,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeAdminRoleForbidden,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(true, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: otherUserID, Username: username, Role: role}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+
+				// Role managers change roles, only admins make admins
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_ROLE_MANAGE).Return(true, nil).Once()
+				userHelper.On("IsValidRole", adminRole).Return(true).Once()
+			},
+		},
+		{
+			name: "Update User Success - Role Manager Changes Role Of Other User",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:   otherUserID,
+					Role: &userRole,
+				},
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(true, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: otherUserID, Username: username, Role: "moderator"}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_ROLE_MANAGE).Return(true, nil).Once()
+				userHelper.On("IsValidRole", userRole).Return(true).Once()
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
+					return u.ID == otherUserID && u.Role == userRole
+				})).Return(nil).Once()
+
+				// Tokens issued with the old role are revoked
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, otherUserID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Update User Failed - Staff Without Role Management Changes Role",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:   otherUserID,
+					Role: &userRole,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeForbidden,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(true, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: otherUserID, Role: "moderator"}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_ROLE_MANAGE).Return(false, nil).Once()
+			},
+		},
+		{
+			name: "Update User Failed - Staff Edits Admin",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:       otherUserID,
+					Fullname: &newFullname,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(true, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: otherUserID, Role: entity.ROLE_ADMIN}, nil).Once()
+			},
+		},
+		{
+			name: "Update User Failed - Other User Without Permission",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:       otherUserID,
+					Fullname: &newFullname,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUnauthorized,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(false, nil).Once()
+			},
+		},
+		{
+			name: "Update User Failed - No User Context",
+			args: args{
+				ctx: context.Background(),
+				req: &model.UpdateUserRequest{
+					ID:       userID,
+					Username: &newUsername,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUnauthorized,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+			},
+		},
+		{
+			name: "Update User Failed - Owner Changes Own Role",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:   userID,
+					Role: &adminRole,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeCannotChangeOwnRole,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: userID, Role: role}, nil).Once()
+			},
+		},
+		{
+			name: "Update User Failed - Invalid Role",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:   otherUserID,
+					Role: &invalidRole,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeValidatorFormat,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_USER_WRITE).Return(true, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: otherUserID, Role: role}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+				userHelper.On("HasPermission", mock.Anything, mock.Anything, entity.PERMISSION_ROLE_MANAGE).Return(true, nil).Once()
+				userHelper.On("IsValidRole", invalidRole).Return(false).Once()
+			},
+		},
+		{
+			name: "Update User Failed - Username Existed",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:       userID,
+					Username: &newUsername,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserExisted,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil
+				})).Return(&entity.User{ID: userID, Username: username}, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Username != nil
+				})).Return(&entity.User{ID: otherUserID}, nil).Once()
+			},
+		},
+		{
+			name: "Update User Failed - User Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserRequest{
+					ID:       userID,
+					Username: &newUsername,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserNotFound,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, userHelper, sessionRepo, oauthHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:    repo,
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:  userHelper,
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			got, err := s.UpdateUser(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.UpdateUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.UpdateUser() error = %v, want code %v", err, tt.errCode)
+				}
+				return
+			}
+			if got == nil {
+				t.Error("userService.UpdateUser() got nil response, want non-nil")
+			}
+		})
+	}
+}
+
+func Test_userService_GetMe(t *testing.T) {
+	type testCase struct {
+		name            string
+		ctx             context.Context
+		wantErr         bool
+		wantPermissions []string
+		mock            func(userHelper *helper_mocks.IUserHelper)
+	}
+
+	user := &entity.User{ID: uuid.New(), Username: username, Role: role}
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), user)
+
+	tests := []testCase{
+		{
+			name:            "Get Me Success",
+			ctx:             ctx,
+			wantErr:         false,
+			wantPermissions: []string{entity.PERMISSION_USER_READ},
+			mock: func(userHelper *helper_mocks.IUserHelper) {
+				userHelper.On("GetPermissions", mock.Anything, user).Return([]string{entity.PERMISSION_USER_READ}, nil).Once()
+			},
+		},
+		{
+			name:    "Get Me Failed - No User Context",
+			ctx:     context.Background(),
+			wantErr: true,
+			mock: func(userHelper *helper_mocks.IUserHelper) {
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			userHelper := helper_mocks.NewIUserHelper(t)
+
+			// Setup mocks
+			tt.mock(userHelper)
+
+			s := &userService{
+				helper: helper.HelperCollections{
+					UserHelper: userHelper,
+				},
+			}
+
+			got, err := s.GetMe(tt.ctx, &model.GetMeRequest{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.GetMe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.User.ID != user.ID || !reflect.DeepEqual(got.Permissions, tt.wantPermissions)) {
+				t.Errorf("userService.GetMe() = %v, want user %v with permissions %v", got, user.ID, tt.wantPermissions)
+			}
+		})
+	}
+}
+
+func Test_userService_UpdateProfile(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.UpdateProfileRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository)
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{ID: userID})
+	newFullname := "New User"
+	oldEmail := "old@example.com"
+	newEmail := "new@example.com"
+
+	tests := []testCase{
+		{
+			name: "Update Profile Success - Changed Email Needs Verification",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateProfileRequest{Fullname: &newFullname, Email: &newEmail},
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == userID
+				})).Return(&entity.User{ID: userID, Role: role, Email: &oldEmail, EmailVerified: true}, nil).Once()
+
+				// Mock email check
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Email != nil && *filter.Email == newEmail
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+
+				// Role and username aren't self-service
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(u *entity.User) bool {
+					return u.Fullname == newFullname &&
+						*u.Email == newEmail &&
+						!u.EmailVerified &&
+						u.Role == role
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Update Profile Failed - Email Existed",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateProfileRequest{Email: &newEmail},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeEmailExisted,
+			mock: func(repo *repo_mocks.IUserRepository) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil
+				})).Return(&entity.User{ID: userID}, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Email != nil
+				})).Return(&entity.User{ID: uuid.New()}, nil).Once()
+			},
+		},
+		{
+			name: "Update Profile Failed - No User Context",
+			args: args{
+				ctx: context.Background(),
+				req: &model.UpdateProfileRequest{Fullname: &newFullname},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUnauthorized,
+			mock: func(repo *repo_mocks.IUserRepository) {
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+
+			// Setup mocks
+			tt.mock(repo)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: repo,
+				},
+			}
+
+			_, err := s.UpdateProfile(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.UpdateProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.UpdateProfile() error = %v, want code %v", err, tt.errCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_userService_UpdateUserStatus(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.UpdateUserStatusRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper)
+	}
+
+	admin := &entity.User{ID: uuid.New(), Role: entity.ROLE_ADMIN}
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), admin)
+	staffCtx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+	targetID := uuid.New()
+	reason := "Spam"
+	suspendedUntil := time.Now().Add(24 * time.Hour).Unix()
+	pastTime := time.Now().Add(-time.Hour).Unix()
+
+	mockRevokeAll := func(sessionRepo *repo_mocks.ISessionRepository, oauthHelper *helper_mocks.IOAuthHelper) {
+		sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		oauthHelper.On("RevokeAllUserTokens", mock.Anything, targetID).Return(nil).Once()
+	}
+
+	tests := []testCase{
+		{
+			name: "Suspend User Success",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserStatusRequest{
+					UserID:         targetID,
+					Status:         entity.USER_STATUS_SUSPENDED,
+					Reason:         &reason,
+					SuspendedUntil: &suspendedUntil,
+				},
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: targetID, Status: entity.USER_STATUS_ACTIVE}, nil).Once()
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Status == entity.USER_STATUS_SUSPENDED &&
+						user.StatusReason != nil && *user.StatusReason == reason &&
+						user.SuspendedUntil != nil && *user.SuspendedUntil == suspendedUntil &&
+						user.DeletedAt == nil
+				})).Return(nil).Once()
+				mockRevokeAll(sessionRepo, oauthHelper)
+			},
+		},
+		{
+			name: "Delete User Success",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserStatusRequest{
+					UserID: targetID,
+					Status: entity.USER_STATUS_DELETED,
+				},
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: targetID, Status: entity.USER_STATUS_ACTIVE}, nil).Once()
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Status == entity.USER_STATUS_DELETED && user.DeletedAt != nil
+				})).Return(nil).Once()
+				mockRevokeAll(sessionRepo, oauthHelper)
+			},
+		},
+		{
+			name: "Reactivate User Success",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserStatusRequest{
+					UserID: targetID,
+					Status: entity.USER_STATUS_ACTIVE,
+				},
+			},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:             targetID,
+					Status:         entity.USER_STATUS_SUSPENDED,
+					StatusReason:   &reason,
+					SuspendedUntil: &suspendedUntil,
+				}, nil).Once()
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Status == entity.USER_STATUS_ACTIVE && user.StatusReason == nil && user.SuspendedUntil == nil
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Update Status Failed - Suspension End In The Past",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserStatusRequest{
+					UserID:         targetID,
+					Status:         entity.USER_STATUS_SUSPENDED,
+					SuspendedUntil: &pastTime,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeSuspensionEndInvalid,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+			},
+		},
+		{
+			name: "Update Status Failed - Own Account",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserStatusRequest{
+					UserID: admin.ID,
+					Status: entity.USER_STATUS_DELETED,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeCannotChangeOwnStatus,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+			},
+		},
+		{
+			name: "Update Status Failed - Admin Suspended By Staff",
+			args: args{
+				ctx: staffCtx,
+				req: &model.UpdateUserStatusRequest{
+					UserID: targetID,
+					Status: entity.USER_STATUS_SUSPENDED,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: targetID, Role: entity.ROLE_ADMIN}, nil).Once()
+			},
+		},
+		{
+			name: "Update Status Failed - More Privileged Staff Deleted",
+			args: args{
+				ctx: staffCtx,
+				req: &model.UpdateUserStatusRequest{
+					UserID: targetID,
+					Status: entity.USER_STATUS_DELETED,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: targetID, Role: "manager"}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{"role:manage"}, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{"user:write"}, nil).Once()
+			},
+		},
+		{
+			name: "Update Status Failed - User Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.UpdateUserStatusRequest{
+					UserID: targetID,
+					Status: entity.USER_STATUS_SUSPENDED,
+				},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserNotFound,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, sessionRepo, userHelper, oauthHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:    repo,
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:  userHelper,
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			_, err := s.UpdateUserStatus(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.UpdateUserStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.UpdateUserStatus() error = %v, want code %v", err, tt.errCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_userService_RevokeUserSessions(t *testing.T) {
+	type testCase struct {
+		name    string
+		ctx     context.Context
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper)
+	}
+
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	staffCtx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+	targetID := uuid.New()
+
+	tests := []testCase{
+		{
+			name:    "Revoke User Sessions Success",
+			ctx:     staffCtx,
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: targetID, Role: entity.ROLE_USER}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, targetID).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Revoke User Sessions Failed - Admin Signed Out By Staff",
+			ctx:     staffCtx,
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: targetID, Role: entity.ROLE_ADMIN}, nil).Once()
+			},
+		},
+		{
+			name:    "Revoke User Sessions Failed - User Not Found",
+			ctx:     staffCtx,
+			wantErr: true,
+			errCode: errors.ErrCodeUserNotFound,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, sessionRepo, userHelper, oauthHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:    repo,
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:  userHelper,
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			_, err := s.RevokeUserSessions(tt.ctx, &model.RevokeUserSessionsRequest{UserID: targetID})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.RevokeUserSessions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.RevokeUserSessions() error = %v, want code %v", err, tt.errCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_userService_UnlockUser(t *testing.T) {
+	type testCase struct {
+		name    string
+		ctx     context.Context
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper)
+	}
+
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	staffCtx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+	targetID := uuid.New()
+
+	tests := []testCase{
+		{
+			name:    "Unlock User Success",
+			ctx:     staffCtx,
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: targetID, Username: username, Role: entity.ROLE_USER}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+				loginAttemptHelper.On("ResetLoginFailures", mock.Anything, username).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Unlock User Failed - More Privileged Staff",
+			ctx:     staffCtx,
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, loginAttemptHelper *helper_mocks.ILoginAttemptHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: targetID, Username: username, Role: "manager"}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{"role:manage"}, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{"user:write"}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			loginAttemptHelper := helper_mocks.NewILoginAttemptHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, userHelper, loginAttemptHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: repo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:         userHelper,
+					LoginAttemptHelper: loginAttemptHelper,
+				},
+			}
+
+			_, err := s.UnlockUser(tt.ctx, &model.UnlockUserRequest{ID: targetID})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.UnlockUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.UnlockUser() error = %v, want code %v", err, tt.errCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_userService_Impersonate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.ImpersonateUserRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, logRepo *repo_mocks.IImpersonationLogRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper)
+	}
+
+	admin := &entity.User{ID: uuid.New(), Username: "admin", Role: entity.ROLE_ADMIN}
+	targetID := uuid.New()
+	payload := &model.UserJWTPayload{UserID: admin.ID, FamilyID: uuid.NewString(), MfaVerified: true}
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), admin)
+	ctx = context.WithValue(ctx, string(utils.TOKEN_CONTEXT_KEY), payload)
+	req := &model.ImpersonateUserRequest{
+		UserID:     targetID,
+		ClientInfo: model.ClientInfo{ClientIP: "127.0.0.1", UserAgent: "test"},
+	}
+
+	tests := []testCase{
+		{
+			name:    "Impersonate Success",
+			args:    args{ctx: ctx, req: req},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, logRepo *repo_mocks.IImpersonationLogRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				target := &entity.User{ID: targetID, Username: username, Role: entity.ROLE_USER}
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.ID != nil && *filter.ID == targetID
+				})).Return(target, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, target).Return([]string{}, nil).Once()
+
+				// The token is bound to the session of the admin
+				oauthHelper.On("GenerateImpersonationToken", mock.Anything, admin.ID, *target, payload.Session()).Return("impersonation", nil).Once()
+				logRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(log *entity.ImpersonationLog) bool {
+					return log.ImpersonatorID == admin.ID &&
+						log.UserID == targetID &&
+						log.Action == entity.IMPERSONATION_ACTION_START &&
+						log.ClientIP == "127.0.0.1"
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Impersonate Failed - Target Is Admin",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeCannotImpersonate,
+			mock: func(repo *repo_mocks.IUserRepository, logRepo *repo_mocks.IImpersonationLogRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{ID: targetID, Role: entity.ROLE_ADMIN}, nil).Once()
+			},
+		},
+		{
+			name:    "Impersonate Failed - Target Can Impersonate",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeCannotImpersonate,
+			mock: func(repo *repo_mocks.IUserRepository, logRepo *repo_mocks.IImpersonationLogRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				target := &entity.User{ID: targetID, Role: entity.ROLE_USER}
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(target, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, target).Return([]string{entity.PERMISSION_USER_IMPERSONATE}, nil).Once()
+			},
+		},
+		{
+			name:    "Impersonate Failed - Target Is Staff",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeCannotImpersonate,
+			mock: func(repo *repo_mocks.IUserRepository, logRepo *repo_mocks.IImpersonationLogRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				// A customer account with a support role assigned
+				target := &entity.User{ID: targetID, Role: entity.ROLE_USER}
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(target, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, target).Return([]string{entity.PERMISSION_USER_WRITE, entity.PERMISSION_API_KEY_MANAGE}, nil).Once()
+			},
+		},
+		{
+			name:    "Impersonate Failed - User Not Found",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeUserNotFound,
+			mock: func(repo *repo_mocks.IUserRepository, logRepo *repo_mocks.IImpersonationLogRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+		},
+		{
+			name: "Impersonate Failed - Already Impersonating",
+			args: args{
+				ctx: context.WithValue(
+					context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), admin),
+					string(utils.TOKEN_CONTEXT_KEY),
+					&model.UserJWTPayload{UserID: admin.ID, ImpersonatorID: &targetID},
+				),
+				req: req,
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeImpersonationForbidden,
+			mock: func(repo *repo_mocks.IUserRepository, logRepo *repo_mocks.IImpersonationLogRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			logRepo := repo_mocks.NewIImpersonationLogRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, logRepo, userHelper, oauthHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:             repo,
+					ImpersonationLogRepo: logRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:  userHelper,
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			got, err := s.Impersonate(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.Impersonate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.Impersonate() error = %v, want code %v", err, tt.errCode)
+				}
+				return
+			}
+			if got.AccessToken != "impersonation" || got.ExpiresIn != utils.IMPERSONATION_TOKEN_IAT {
+				t.Errorf("userService.Impersonate() got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_userService_InviteUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.InviteUserRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, notificationHelper *helper_mocks.INotificationHelper)
+	}
+
+	// Staff with user:write, not an admin
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+	email := "staff@example.com"
+	req := &model.InviteUserRequest{
+		Username: "staff",
+		Fullname: "Staff",
+		Role:     entity.ROLE_USER,
+		Email:    email,
+	}
+
+	tests := []testCase{
+		{
+			name:    "Invite User Success",
+			args:    args{ctx: ctx, req: req},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_USER).Return(true).Once()
+
+				// Username and email are free
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Twice()
+
+				// The user can't sign in before activation
+				repo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Username == "staff" &&
+						user.IsInvited() &&
+						*user.Email == email &&
+						user.Password != ""
+				})).Return(nil).Once()
+
+				oauthHelper.On("GenerateInvitationToken", mock.Anything, mock.Anything).Return("invitation-token", nil).Once()
+				notificationHelper.On("SendInvitation", mock.Anything, mock.Anything, "invitation-token").Return(nil).Once()
+			},
+		},
+		{
+			name:    "Invite User Failed - Invalid Role",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeValidatorFormat,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_USER).Return(false).Once()
+			},
+		},
+		{
+			name: "Invite User Failed - Admin Role By Staff",
+			args: args{ctx: ctx, req: &model.InviteUserRequest{
+				Username: "staff",
+				Fullname: "Staff",
+				Role:     entity.ROLE_ADMIN,
+				Email:    email,
+			}},
+			wantErr: true,
+			errCode: errors.ErrCodeAdminRoleForbidden,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_ADMIN).Return(true).Once()
+			},
+		},
+		{
+			name:    "Invite User Failed - Email Existed",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeEmailExisted,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper, notificationHelper *helper_mocks.INotificationHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_USER).Return(true).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Username != nil
+				})).Return(nil, gorm.ErrRecordNotFound).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return filter.Email != nil
+				})).Return(&entity.User{ID: uuid.New()}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+			notificationHelper := helper_mocks.NewINotificationHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, userHelper, oauthHelper, notificationHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: repo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:         userHelper,
+					OAuthHelper:        oauthHelper,
+					NotificationHelper: notificationHelper,
+				},
+			}
+
+			_, err := s.InviteUser(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.InviteUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.InviteUser() error = %v, want code %v", err, tt.errCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_userService_ActivateUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.ActivateUserRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, passwordHelper *helper_mocks.IPasswordHelper)
+	}
+
+	ctx := context.Background()
+	userID := uuid.New()
+	req := &model.ActivateUserRequest{
+		Token:           "invitation-token",
+		NewPassword:     "NewPassword@123",
+		ConfirmPassword: "NewPassword@123",
+	}
+
+	tests := []testCase{
+		{
+			name:    "Activate User Success",
+			args:    args{ctx: ctx, req: req},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, passwordHelper *helper_mocks.IPasswordHelper) {
+				oauthHelper.On("ConsumeInvitationToken", mock.Anything, "invitation-token").Return(userID, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:     userID,
+					Status: entity.USER_STATUS_INVITED,
+				}, nil).Once()
+				repo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Status == entity.USER_STATUS_ACTIVE &&
+						user.EmailVerified &&
+						user.CheckPassword(req.NewPassword) == nil
+				})).Return(nil).Once()
+				passwordHelper.On("RecordPassword", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Activate User Failed - Invalid Token",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeInvalidToken,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, passwordHelper *helper_mocks.IPasswordHelper) {
+				oauthHelper.On("ConsumeInvitationToken", mock.Anything, "invitation-token").Return(uuid.Nil, errors.New(errors.ErrCodeInvalidToken)).Once()
+			},
+		},
+		{
+			name:    "Activate User Failed - Already Active",
+			args:    args{ctx: ctx, req: req},
+			wantErr: true,
+			errCode: errors.ErrCodeInvalidToken,
+			mock: func(repo *repo_mocks.IUserRepository, oauthHelper *helper_mocks.IOAuthHelper, passwordHelper *helper_mocks.IPasswordHelper) {
+				oauthHelper.On("ConsumeInvitationToken", mock.Anything, "invitation-token").Return(userID, nil).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(&entity.User{
+					ID:     userID,
+					Status: entity.USER_STATUS_ACTIVE,
+				}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+			passwordHelper := helper_mocks.NewIPasswordHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, oauthHelper, passwordHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: repo,
+				},
+				helper: helper.HelperCollections{
+					OAuthHelper:    oauthHelper,
+					PasswordHelper: passwordHelper,
+				},
+			}
+
+			_, err := s.ActivateUser(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.ActivateUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.ActivateUser() error = %v, want code %v", err, tt.errCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_userService_BulkUpdateUserRole(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.BulkUpdateUserRoleRequest
+	}
+
+	type testCase struct {
+		name        string
+		args        args
+		wantErr     bool
+		errCode     int
+		wantUpdated int
+		mock        func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper)
+	}
+
+	admin := &entity.User{ID: uuid.New(), Role: entity.ROLE_ADMIN}
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	ctx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), admin)
+	staffCtx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []testCase{
+		{
+			name: "Bulk Update Role Success",
+			args: args{
+				ctx: ctx,
+				req: &model.BulkUpdateUserRoleRequest{UserIDs: []uuid.UUID{userID, adminID}, Role: entity.ROLE_ADMIN},
+			},
+			wantErr:     false,
+			wantUpdated: 1,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_ADMIN).Return(true).Once()
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return len(filter.IDs) == 2
+				})).Return([]entity.User{
+					{ID: userID, Role: entity.ROLE_USER},
+					{ID: adminID, Role: entity.ROLE_ADMIN},
+				}, nil).Once()
+
+				// Users that already have the role are left alone
+				repo.On("UpdateRoleByFilter", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *repository.FindUserByFilter) bool {
+					return len(filter.IDs) == 1 && filter.IDs[0] == userID
+				}), entity.ROLE_ADMIN).Return(nil).Once()
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Bulk Update Role Failed - Promoted To Admin By Staff",
+			args: args{
+				ctx: context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}),
+				req: &model.BulkUpdateUserRoleRequest{UserIDs: []uuid.UUID{userID}, Role: entity.ROLE_ADMIN},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeAdminRoleForbidden,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_ADMIN).Return(true).Once()
+			},
+		},
+		{
+			name: "Bulk Update Role Success - By Staff",
+			args: args{
+				ctx: staffCtx,
+				req: &model.BulkUpdateUserRoleRequest{UserIDs: []uuid.UUID{userID}, Role: entity.ROLE_USER},
+			},
+			wantErr:     false,
+			wantUpdated: 1,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_USER).Return(true).Once()
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.User{
+					{ID: userID, Role: "moderator"},
+				}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{"review:write"}, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{"review:write", "user:write"}, nil).Once()
+				repo.On("UpdateRoleByFilter", mock.Anything, mock.Anything, mock.Anything, entity.ROLE_USER).Return(nil).Once()
+				sessionRepo.On("RevokeManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				oauthHelper.On("RevokeAllUserTokens", mock.Anything, userID).Return(nil).Once()
+			},
+		},
+		{
+			name: "Bulk Update Role Failed - Admin Demoted By Staff",
+			args: args{
+				ctx: staffCtx,
+				req: &model.BulkUpdateUserRoleRequest{UserIDs: []uuid.UUID{userID, adminID}, Role: entity.ROLE_USER},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_USER).Return(true).Once()
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.User{
+					{ID: userID, Role: entity.ROLE_USER},
+					{ID: adminID, Role: entity.ROLE_ADMIN},
+				}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{}, nil).Once()
+			},
+		},
+		{
+			name: "Bulk Update Role Failed - More Privileged Staff",
+			args: args{
+				ctx: staffCtx,
+				req: &model.BulkUpdateUserRoleRequest{UserIDs: []uuid.UUID{userID}, Role: entity.ROLE_USER},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserMorePrivileged,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_USER).Return(true).Once()
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.User{
+					{ID: userID, Role: "manager"},
+				}, nil).Once()
+				userHelper.On("GetStaffPermissions", mock.Anything, mock.Anything).Return([]string{"role:manage"}, nil).Once()
+				userHelper.On("GetPermissions", mock.Anything, staff).Return([]string{"user:write"}, nil).Once()
+			},
+		},
+		{
+			name: "Bulk Update Role Failed - Own Account",
+			args: args{
+				ctx: ctx,
+				req: &model.BulkUpdateUserRoleRequest{UserIDs: []uuid.UUID{userID, admin.ID}, Role: entity.ROLE_USER},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeCannotChangeOwnRole,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+			},
+		},
+		{
+			name: "Bulk Update Role Failed - User Not Found",
+			args: args{
+				ctx: ctx,
+				req: &model.BulkUpdateUserRoleRequest{UserIDs: []uuid.UUID{userID, adminID}, Role: entity.ROLE_USER},
+			},
+			wantErr: true,
+			errCode: errors.ErrCodeUserNotFound,
+			mock: func(repo *repo_mocks.IUserRepository, sessionRepo *repo_mocks.ISessionRepository, userHelper *helper_mocks.IUserHelper, oauthHelper *helper_mocks.IOAuthHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_USER).Return(true).Once()
+				repo.On("FindManyByFilter", mock.Anything, mock.Anything, mock.Anything).Return([]entity.User{
+					{ID: userID, Role: entity.ROLE_ADMIN},
+				}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			sessionRepo := repo_mocks.NewISessionRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			oauthHelper := helper_mocks.NewIOAuthHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, sessionRepo, userHelper, oauthHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo:    repo,
+					SessionRepo: sessionRepo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:  userHelper,
+					OAuthHelper: oauthHelper,
+				},
+			}
+
+			got, err := s.BulkUpdateUserRole(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.BulkUpdateUserRole() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.BulkUpdateUserRole() error = %v, want code %v", err, tt.errCode)
+				}
+				return
+			}
+			if got.Updated != tt.wantUpdated {
+				t.Errorf("userService.BulkUpdateUserRole() updated = %v, want %v", got.Updated, tt.wantUpdated)
+			}
+		})
+	}
+}
+
+func Test_userService_CreateUser(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *model.CreateUserRequest
+	}
+
+	type testCase struct {
+		name    string
+		args    args
+		wantErr bool
+		errCode int
+		mock    func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, passwordHelper *helper_mocks.IPasswordHelper)
+	}
+
+	admin := &entity.User{ID: uuid.New(), Role: entity.ROLE_ADMIN}
+	staff := &entity.User{ID: uuid.New(), Role: entity.ROLE_USER}
+	adminCtx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), admin)
+	staffCtx := context.WithValue(context.Background(), string(utils.USER_CONTEXT_KEY), staff)
+	newAdmin := &model.CreateUserRequest{
+		Username: "new-admin",
+		Password: password,
+		Fullname: fullname,
+		Role:     entity.ROLE_ADMIN,
+	}
+
+	tests := []testCase{
+		{
+			name:    "Create User Success - Admin By Admin",
+			args:    args{ctx: adminCtx, req: newAdmin},
+			wantErr: false,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, passwordHelper *helper_mocks.IPasswordHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_ADMIN).Return(true).Once()
+				repo.On("FindOneByFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+				repo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Username == "new-admin" && user.IsAdmin()
+				})).Return(nil).Once()
+				passwordHelper.On("RecordPassword", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Create User Failed - Admin By Staff",
+			args:    args{ctx: staffCtx, req: newAdmin},
+			wantErr: true,
+			errCode: errors.ErrCodeAdminRoleForbidden,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, passwordHelper *helper_mocks.IPasswordHelper) {
+				userHelper.On("IsValidRole", entity.ROLE_ADMIN).Return(true).Once()
+			},
+		},
+		{
+			name:    "Create User Failed - Unauthorized",
+			args:    args{ctx: context.Background(), req: newAdmin},
+			wantErr: true,
+			errCode: errors.ErrCodeUnauthorized,
+			mock: func(repo *repo_mocks.IUserRepository, userHelper *helper_mocks.IUserHelper, passwordHelper *helper_mocks.IPasswordHelper) {
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Initialize mocks
+			repo := repo_mocks.NewIUserRepository(t)
+			userHelper := helper_mocks.NewIUserHelper(t)
+			passwordHelper := helper_mocks.NewIPasswordHelper(t)
+
+			// Setup mocks
+			tt.mock(repo, userHelper, passwordHelper)
+
+			s := &userService{
+				postgresRepo: repository.RepositoryCollections{
+					UserRepo: repo,
+				},
+				helper: helper.HelperCollections{
+					UserHelper:     userHelper,
+					PasswordHelper: passwordHelper,
+				},
+			}
+
+			_, err := s.CreateUser(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userService.CreateUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if customErr, ok := err.(*errors.CustomError); !ok || customErr.Code != tt.errCode {
+					t.Errorf("userService.CreateUser() error = %v, want code %v", err, tt.errCode)
+				}
 			}
 		})
 	}
@@ -10,6 +10,7 @@ import (
 	"sondth-test_soa/package/errors"
 	"sondth-test_soa/utils"
 
+	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
@@ -103,11 +104,18 @@ func (s *wishlistService) GetWishlists(
 	if !ok {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
+	page, err := newListPage(req.CursorRequest, req.Sort, repository.WishlistSortFields, &req.Page, &req.Limit)
+	if err != nil {
+		return nil, err
+	}
 
 	filter := &repository.FindWishlistByFilter{
 		UserID: &user.ID,
-		Page:   &req.Page,
-		Limit:  &req.Limit,
+		Page:   page.Page,
+		Limit:  page.Limit,
+		Peek:   page.Peek,
+		Sort:   page.Sort,
+		Keyset: page.Keyset,
 	}
 
 	errGroup, errCtx := errgroup.WithContext(ctx)
@@ -117,28 +125,33 @@ func (s *wishlistService) GetWishlists(
 		var err error
 		wishlists, err = s.postgresRepo.WishlistRepo.FindManyByFilter(errCtx, nil, filter)
 		if err != nil {
-			return err
+			return keysetError(err)
 		}
 		return nil
 	})
 
-	var count int64
-	errGroup.Go(func() error {
-		var err error
-		count, err = s.postgresRepo.WishlistRepo.CountByFilter(errCtx, nil, filter)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
+	var count *int64
+	if !req.SkipCount {
+		errGroup.Go(func() error {
+			total, err := s.postgresRepo.WishlistRepo.CountByFilter(errCtx, nil, filter)
+			if err != nil {
+				return err
+			}
+			count = &total
+			return nil
+		})
+	}
 
 	if err := errGroup.Wait(); err != nil {
 		return nil, errors.New(errors.ErrCodeInternalServerError)
 	}
 
+	wishlists, cursors := cutPage(page, wishlists, func(wishlist entity.Wishlist) uuid.UUID { return wishlist.ID })
+
 	return &model.GetWishlistsResponse{
-		Wishlists: wishlists,
-		Count:     count,
+		Wishlists:      wishlists,
+		Count:          count,
+		CursorResponse: cursors,
 	}, nil
 }
 
@@ -295,6 +295,7 @@ func Test_wishlistService_GetWishlists(t *testing.T) {
 		},
 	}
 
+	count := int64(1)
 	tests := []testCase{
 		{
 			name: "Get Wishlists Success",
@@ -307,7 +308,7 @@ func Test_wishlistService_GetWishlists(t *testing.T) {
 			},
 			want: &model.GetWishlistsResponse{
 				Wishlists: wishlists,
-				Count:     1,
+				Count:     &count,
 			},
 			wantErr: false,
 			mock: func(repo *repo_mocks.IWishlistRepository) {
@@ -316,8 +317,8 @@ func Test_wishlistService_GetWishlists(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindWishlistByFilter) bool {
 					return filter.UserID != nil && *filter.UserID == userID &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(wishlists, nil).Once()
 
 				// Mock count wishlists
@@ -325,8 +326,8 @@ func Test_wishlistService_GetWishlists(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindWishlistByFilter) bool {
 					return filter.UserID != nil && *filter.UserID == userID &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(int64(1), nil).Once()
 			},
 		},
@@ -360,8 +361,8 @@ func Test_wishlistService_GetWishlists(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindWishlistByFilter) bool {
 					return filter.UserID != nil && *filter.UserID == userID &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(nil, errors.New(errors.ErrCodeInternalServerError)).Once()
 
 				// Mock count wishlists error
@@ -369,8 +370,8 @@ func Test_wishlistService_GetWishlists(t *testing.T) {
 					return true
 				}), mock.Anything, mock.MatchedBy(func(filter *repository.FindWishlistByFilter) bool {
 					return filter.UserID != nil && *filter.UserID == userID &&
-						filter.Page != nil && *filter.Page == page &&
-						filter.Limit != nil && *filter.Limit == limit
+						filter.Page == nil &&
+						filter.Limit != nil && *filter.Limit == limit && filter.Peek
 				})).Return(int64(0), errors.New(errors.ErrCodeInternalServerError)).Once()
 			},
 		},
@@ -7,10 +7,20 @@ import (
 )
 
 type Configuration struct {
-	PostgresDB PostgresDatabase `mapstructure:"postgres"`
-	Server     Server           `mapstructure:"server"`
-	Jwt        JWT              `mapstructure:"jwt"`
-	Redis      Redis            `mapstructure:"redis"`
+	PostgresDB     PostgresDatabase `mapstructure:"postgres"`
+	Server         Server           `mapstructure:"server"`
+	Jwt            JWT              `mapstructure:"jwt"`
+	Redis          Redis            `mapstructure:"redis"`
+	Notifier       Notifier         `mapstructure:"notifier"`
+	MFA            MFA              `mapstructure:"mfa"`
+	PasswordHash   PasswordHash     `mapstructure:"password_hash"`
+	PasswordPolicy PasswordPolicy   `mapstructure:"password_policy"`
+	OIDC           OIDC             `mapstructure:"oidc"`
+	Invitation     Invitation       `mapstructure:"invitation"`
+	Storage        Storage          `mapstructure:"storage"`
+	I18n           I18n             `mapstructure:"i18n"`
+	Worker         Worker           `mapstructure:"worker"`
+	Privacy        Privacy          `mapstructure:"privacy"`
 }
 
 // NewConfigClient creates a new configuration client
@@ -33,6 +43,48 @@ func NewConfigClient(filePath string) (*Configuration, error) {
 	if configuration.Server.Port == 0 {
 		configuration.Server.Port = 8080
 	}
+	if configuration.Notifier.Driver == "" {
+		configuration.Notifier.Driver = "log"
+	}
+	if configuration.Notifier.FilePath == "" {
+		configuration.Notifier.FilePath = "logs/notifications.log"
+	}
+	if configuration.Jwt.RetiredKeyGracePeriod == 0 {
+		configuration.Jwt.RetiredKeyGracePeriod = 24 * 60 * 60
+	}
+	if configuration.PasswordPolicy.MinLength == 0 {
+		configuration.PasswordPolicy.MinLength = 8
+	}
+	if configuration.PasswordPolicy.MaxLength == 0 {
+		configuration.PasswordPolicy.MaxLength = 72
+	}
+	if configuration.I18n.DefaultLanguage == "" {
+		configuration.I18n.DefaultLanguage = "en"
+	}
+	if configuration.Storage.Driver == "" {
+		configuration.Storage.Driver = "local"
+	}
+	if configuration.Storage.LocalDir == "" {
+		configuration.Storage.LocalDir = "uploads"
+	}
+	if configuration.Storage.BaseURL == "" {
+		configuration.Storage.BaseURL = "/uploads"
+	}
+	if configuration.Storage.AvatarMaxSize == 0 {
+		configuration.Storage.AvatarMaxSize = 2 << 20
+	}
+	if configuration.Invitation.TTL == 0 {
+		configuration.Invitation.TTL = 3 * 24 * 60 * 60
+	}
+	if configuration.Privacy.ExportDir == "" {
+		configuration.Privacy.ExportDir = "exports"
+	}
+	if configuration.Privacy.ExportTTL == 0 {
+		configuration.Privacy.ExportTTL = 7 * 24 * 60 * 60
+	}
+	if configuration.MFA.Issuer == "" {
+		configuration.MFA.Issuer = configuration.Jwt.Issuer
+	}
 
 	return &configuration, nil
 }
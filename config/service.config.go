@@ -4,6 +4,19 @@ type JWT struct {
 	Issuer              string `mapstructure:"issuer"`
 	UserAccessTokenKey  string `mapstructure:"user_access_token_key"`
 	UserRefreshTokenKey string `mapstructure:"user_refresh_token_key"`
+
+	// Asymmetric signing of access tokens, HS256 with user_access_token_key is used when empty
+	SigningKeyID          string   `mapstructure:"signing_key_id"`
+	Keys                  []JWTKey `mapstructure:"keys"`
+	RetiredKeyGracePeriod int      `mapstructure:"retired_key_grace_period"` // seconds
+}
+
+type JWTKey struct {
+	ID             string `mapstructure:"id"`
+	Algorithm      string `mapstructure:"algorithm"` // RS256 or EdDSA
+	PrivateKeyPath string `mapstructure:"private_key_path"`
+	PublicKeyPath  string `mapstructure:"public_key_path"`
+	RetiredAt      int64  `mapstructure:"retired_at"` // unix timestamp, the key only verifies during the grace period after it
 }
 
 type Server struct {
@@ -18,3 +31,79 @@ type Redis struct {
 	Password string `mapstructure:"password"`
 	DB       int    `mapstructure:"db"`
 }
+
+type Notifier struct {
+	Driver   string `mapstructure:"driver"`
+	FilePath string `mapstructure:"file_path"`
+}
+
+type MFA struct {
+	Issuer               string `mapstructure:"issuer"`
+	EnforceOnAdminRoutes bool   `mapstructure:"enforce_on_admin_routes"`
+}
+
+type PasswordHash struct {
+	Algorithm  string       `mapstructure:"algorithm"` // bcrypt or argon2id
+	BcryptCost int          `mapstructure:"bcrypt_cost"`
+	Argon2     Argon2Config `mapstructure:"argon2"`
+}
+
+type Argon2Config struct {
+	Memory      uint32 `mapstructure:"memory"` // KiB
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  uint32 `mapstructure:"salt_length"`
+	KeyLength   uint32 `mapstructure:"key_length"`
+}
+
+type PasswordPolicy struct {
+	MinLength          int    `mapstructure:"min_length"`
+	MaxLength          int    `mapstructure:"max_length"` // in bytes, bcrypt can't hash passwords over 72 bytes
+	RequireUppercase   bool   `mapstructure:"require_uppercase"`
+	RequireLowercase   bool   `mapstructure:"require_lowercase"`
+	RequireDigit       bool   `mapstructure:"require_digit"`
+	RequireSymbol      bool   `mapstructure:"require_symbol"`
+	HistorySize        int    `mapstructure:"history_size"`         // last N passwords that can't be reused, 0 to disable
+	BreachedHashesPath string `mapstructure:"breached_hashes_path"` // file of SHA-1 hashes, one per line, optionally "HASH:COUNT"
+}
+
+type OIDC struct {
+	Providers []OIDCProvider `mapstructure:"providers"`
+}
+
+type OIDCProvider struct {
+	Name         string   `mapstructure:"name"`
+	IssuerURL    string   `mapstructure:"issuer_url"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"` // openid, email and profile when empty
+}
+
+type I18n struct {
+	DefaultLanguage string `mapstructure:"default_language"` // language of errors when the request and the user don't tell, en when empty
+	CatalogDir      string `mapstructure:"catalog_dir"`      // directory of <language>.yaml or .json message files, added to the built-in vi and en messages
+}
+
+type Storage struct {
+	Driver   string `mapstructure:"driver"`    // local
+	LocalDir string `mapstructure:"local_dir"` // directory uploads are written to by the local driver
+	BaseURL  string `mapstructure:"base_url"`  // URL the stored files are served from, e.g: /uploads or a CDN
+
+	AvatarMaxSize int64 `mapstructure:"avatar_max_size"` // bytes, 2MB when empty
+}
+
+type Invitation struct {
+	ActivationURL string `mapstructure:"activation_url"` // page the activation link points to, the token is added as ?token=
+	TTL           int64  `mapstructure:"ttl"`            // seconds an activation link can be used
+}
+
+type Worker struct {
+	Size      int `mapstructure:"size"`       // goroutines running background jobs
+	QueueSize int `mapstructure:"queue_size"` // jobs waiting for a goroutine
+}
+
+type Privacy struct {
+	ExportDir string `mapstructure:"export_dir"` // directory personal data exports are written to
+	ExportTTL int64  `mapstructure:"export_ttl"` // seconds an export can be downloaded
+}
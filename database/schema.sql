@@ -8,8 +8,22 @@ CREATE TABLE users (
     password VARCHAR(255) NOT NULL,
     fullname VARCHAR(255) NOT NULL,
     role VARCHAR(255) NOT NULL,
+    email VARCHAR(255) UNIQUE,
+    phone_number VARCHAR(20) UNIQUE,
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    phone_verified BOOLEAN NOT NULL DEFAULT FALSE,
     created_at BIGINT NOT NULL,
-    updated_at BIGINT NOT NULL
+    updated_at BIGINT NOT NULL,
+    mfa_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    mfa_secret VARCHAR(255),
+    mfa_recovery_codes TEXT,
+    mfa_last_step BIGINT,
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    status_reason TEXT,
+    suspended_until BIGINT,
+    deleted_at BIGINT,
+    avatar_key VARCHAR(512),
+    avatar_url VARCHAR(1024)
 );
 
 -- Create categories table
@@ -30,12 +44,42 @@ CREATE TABLE products (
     description TEXT,
     image VARCHAR(255),
     price DECIMAL(10,2) NOT NULL,
-    quantity BIGINT NOT NULL,
+    quantity BIGINT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    reserved_quantity BIGINT NOT NULL DEFAULT 0 CHECK (reserved_quantity >= 0),
+    low_stock_threshold BIGINT NOT NULL DEFAULT 10 CHECK (low_stock_threshold >= 0),
     category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
     created_at BIGINT NOT NULL,
     updated_at BIGINT NOT NULL
 );
 
+-- Create product_variants table, the sizes, colors... a product is sold in
+CREATE TABLE product_variants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL UNIQUE,
+    options JSONB NOT NULL DEFAULT '{}',
+    price DECIMAL(10,2),
+    quantity BIGINT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    reserved_quantity BIGINT NOT NULL DEFAULT 0 CHECK (reserved_quantity >= 0),
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+-- Create stock_movements table, the ledger the quantities of products and variants are derived from
+CREATE TABLE stock_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id UUID REFERENCES product_variants(id) ON DELETE SET NULL,
+    type VARCHAR(16) NOT NULL,
+    quantity BIGINT NOT NULL,
+    balance_after BIGINT NOT NULL,
+    reserved_after BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    actor_type VARCHAR(16) NOT NULL,
+    actor_id UUID NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
 -- Create reviews table
 CREATE TABLE reviews (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -57,14 +101,168 @@ CREATE TABLE wishlists (
     UNIQUE(user_id, product_id)
 );
 
+-- Create roles table
+CREATE TABLE roles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+-- Create permissions table
+CREATE TABLE permissions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT
+);
+
+-- Create role_permissions table
+CREATE TABLE role_permissions (
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id UUID NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+-- Create user_roles table
+CREATE TABLE user_roles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (user_id, role_id)
+);
+
+-- Create user_sessions table
+CREATE TABLE user_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT,
+    ip_address VARCHAR(64),
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL,
+    last_seen_at BIGINT NOT NULL,
+    revoked_at BIGINT
+);
+
+-- Create password_histories table
+CREATE TABLE password_histories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password VARCHAR(255) NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+-- Create api_keys table
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    permissions TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at BIGINT,
+    last_used_at BIGINT,
+    revoked_at BIGINT,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+-- Create user_identities table, external OpenID Connect accounts linked to users
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(64) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    last_login_at BIGINT NOT NULL,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL,
+    UNIQUE (provider, subject)
+);
+
+-- Create impersonation_logs table, audit trail of requests made by admins acting as a user.
+-- No foreign keys so the trail outlives the accounts.
+CREATE TABLE impersonation_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    impersonator_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    method VARCHAR(16),
+    path VARCHAR(255),
+    status_code INT,
+    client_ip VARCHAR(64),
+    user_agent VARCHAR(512),
+    created_at BIGINT NOT NULL
+);
+
+-- Create user_data_jobs table, background exports and erasures of personal data
+CREATE TABLE user_data_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    format VARCHAR(8),
+    file_path VARCHAR(512),
+    error TEXT,
+    expires_at BIGINT,
+    completed_at BIGINT,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+-- Create user_addresses table, the address book of a user
+CREATE TABLE user_addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label VARCHAR(64),
+    recipient VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(20) NOT NULL,
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255),
+    city VARCHAR(128) NOT NULL,
+    state VARCHAR(128),
+    postal_code VARCHAR(32),
+    country VARCHAR(2) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+-- Create user_preferences table, one row per user once the defaults are changed
+CREATE TABLE user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    language VARCHAR(8) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    notify_email BOOLEAN NOT NULL DEFAULT TRUE,
+    notify_sms BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_marketing BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
 -- Create indexes for better query performance
 CREATE INDEX idx_categories_name_slug ON categories(name_slug);
 CREATE INDEX idx_products_name_slug ON products(name_slug);
 CREATE INDEX idx_products_category_id ON products(category_id);
+CREATE INDEX idx_product_variants_product_id ON product_variants(product_id);
+CREATE INDEX idx_stock_movements_product_id ON stock_movements(product_id, created_at);
 CREATE INDEX idx_reviews_product_id ON reviews(product_id);
 CREATE INDEX idx_reviews_user_id ON reviews(user_id);
 CREATE INDEX idx_wishlists_user_id ON wishlists(user_id);
 CREATE INDEX idx_wishlists_product_id ON wishlists(product_id);
+CREATE INDEX idx_users_status ON users(status);
+CREATE INDEX idx_users_created_at ON users(created_at);
+CREATE INDEX idx_user_roles_role_id ON user_roles(role_id);
+CREATE INDEX idx_user_sessions_user_id ON user_sessions(user_id);
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+CREATE INDEX idx_password_histories_user_id ON password_histories(user_id, created_at);
+CREATE INDEX idx_impersonation_logs_impersonator_id ON impersonation_logs(impersonator_id, created_at);
+CREATE INDEX idx_user_data_jobs_user_id ON user_data_jobs(user_id, type);
+CREATE INDEX idx_user_data_jobs_status ON user_data_jobs(status);
+CREATE INDEX idx_impersonation_logs_user_id ON impersonation_logs(user_id, created_at);
+CREATE INDEX idx_user_addresses_user_id ON user_addresses(user_id);
+-- Only one default shipping address per user
+CREATE UNIQUE INDEX idx_user_addresses_default ON user_addresses(user_id) WHERE is_default;
 
 -- Insert default admin user (password: admin123)
 INSERT INTO users (id, username, password, fullname, role, created_at, updated_at)
@@ -77,3 +275,24 @@ VALUES (
     EXTRACT(EPOCH FROM NOW())::BIGINT,
     EXTRACT(EPOCH FROM NOW())::BIGINT
 );
+
+-- Insert default permissions
+INSERT INTO permissions (code, description) VALUES
+    ('product:write', 'Create, update and delete products'),
+    ('category:write', 'Create categories and view category summary'),
+    ('user:read', 'List users'),
+    ('user:write', 'Update other users'),
+    ('review:moderate', 'Moderate reviews'),
+    ('role:manage', 'Manage roles, permissions and role assignments'),
+    ('api_key:manage', 'Create, list and revoke API keys'),
+    ('user:impersonate', 'Act as another user for support'),
+    ('inventory:manage', 'Post stock movements and view the stock ledger');
+
+-- Insert default roles, admin is granted every permission
+INSERT INTO roles (name, description, created_at, updated_at)
+VALUES
+    ('admin', 'Administrator', EXTRACT(EPOCH FROM NOW())::BIGINT, EXTRACT(EPOCH FROM NOW())::BIGINT),
+    ('user', 'Customer', EXTRACT(EPOCH FROM NOW())::BIGINT, EXTRACT(EPOCH FROM NOW())::BIGINT);
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles CROSS JOIN permissions WHERE roles.name = 'admin';
@@ -21,8 +21,14 @@ import (
 	"sondth-test_soa/app/service"
 	"sondth-test_soa/config"
 	"sondth-test_soa/package/database"
+	"sondth-test_soa/package/errors"
+	"sondth-test_soa/package/jwks"
+	"sondth-test_soa/package/notifier"
+	"sondth-test_soa/package/password"
 	"sondth-test_soa/package/redis"
+	"sondth-test_soa/package/storage"
 	_validator "sondth-test_soa/package/validator"
+	"sondth-test_soa/package/worker"
 	"sondth-test_soa/utils"
 )
 
@@ -36,6 +42,28 @@ func main() {
 	}
 	conf := configClient.Get()
 
+	// Register error messages
+	if conf.I18n.CatalogDir != "" {
+		if err := errors.LoadCatalog(conf.I18n.CatalogDir); err != nil {
+			log.Fatalf("Failed to load message catalog: %v", err)
+		}
+	}
+	if err := errors.SetDefaultLanguage(conf.I18n.DefaultLanguage); err != nil {
+		log.Fatalf("Failed to set default language: %v", err)
+	}
+
+	// Register password hashing
+	passwordHashParams, err := password.NewParams(conf.PasswordHash)
+	if err != nil {
+		log.Fatalf("Failed to initialize password hashing: %v", err)
+	}
+	utils.SetPasswordHashParams(passwordHashParams)
+	passwordPolicy, err := _validator.NewPasswordPolicy(conf.PasswordPolicy)
+	if err != nil {
+		log.Fatalf("Failed to initialize password policy: %v", err)
+	}
+	_validator.SetPasswordPolicy(passwordPolicy)
+
 	// Register repositories
 	postgresDB, err := database.NewPostgresClient(conf)
 	if err != nil {
@@ -49,10 +77,34 @@ func main() {
 		log.Fatalf("Failed to initialize Redis client: %v", err)
 	}
 
+	// Register notifier
+	notifierClient, err := notifier.NewNotifier(conf)
+	if err != nil {
+		log.Fatalf("Failed to initialize notifier: %v", err)
+	}
+
+	// Register token signing keys
+	keySet, err := jwks.NewKeySet(conf.Jwt)
+	if err != nil {
+		log.Fatalf("Failed to initialize JWT keys: %v", err)
+	}
+
+	// Register file storage
+	fileStorage, err := storage.NewStorage(conf)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// Register background worker
+	jobWorker := worker.NewWorker(conf.Worker.Size, conf.Worker.QueueSize)
+
 	// Register Others
-	helpers := helper.RegisterHelpers(postgresRepo, conf)
+	helpers := helper.RegisterHelpers(postgresRepo, redisClient, notifierClient, keySet, jobWorker, fileStorage, conf)
 	services := service.RegisterServices(helpers, postgresRepo)
-	mws := middleware.RegisterMiddleware(redisClient, postgresRepo, helpers)
+	if err := services.PrivacySvc.ResumeDataJobs(context.Background()); err != nil {
+		log.Fatalf("Failed to resume data jobs: %v", err)
+	}
+	mws := middleware.RegisterMiddleware(redisClient, postgresRepo, helpers, conf)
 
 	// Start HTTP Server
 	srv := initHTTPServer(conf, services, mws)
@@ -72,6 +124,9 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server Shutdown:", err)
 	}
+	if err := jobWorker.Shutdown(ctx); err != nil {
+		log.Println("Worker Shutdown:", err)
+	}
 
 	// catching ctx.Done(). timeout of 5 seconds.
 	select {
@@ -92,14 +147,20 @@ func initHTTPServer(conf config.Configuration, services service.ServiceCollectio
 	_validator.RegisterCustomValidators(v)
 
 	// Register middleware
+	app.Use(mws.LanguageMw.Handler())
 	app.Use(mws.RateLimitMw.Handler())
+
+	// Uploaded files are public, they are served before authentication
+	if conf.Storage.Driver == storage.DRIVER_LOCAL {
+		app.Static(conf.Storage.BaseURL, conf.Storage.LocalDir)
+	}
 	app.Use(mws.AuthMw.Handler())
 
 	// Register controllers
 	app.GET("/health-check", func(c *gin.Context) {
 		c.String(200, "OK")
 	})
-	controller.RegisterControllers(app, services, mws)
+	controller.RegisterControllers(app, services, mws, conf)
 
 	// Start server
 	srv := &http.Server{
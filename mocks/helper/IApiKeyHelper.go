@@ -0,0 +1,87 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IApiKeyHelper is an autogenerated mock type for the IApiKeyHelper type
+type IApiKeyHelper struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, key
+func (_m *IApiKeyHelper) Authenticate(ctx context.Context, key string) (*entity.ServicePrincipal, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 *entity.ServicePrincipal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.ServicePrincipal, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.ServicePrincipal); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ServicePrincipal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateApiKey provides a mock function with no fields
+func (_m *IApiKeyHelper) GenerateApiKey() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateApiKey")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIApiKeyHelper creates a new instance of IApiKeyHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIApiKeyHelper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IApiKeyHelper {
+	mock := &IApiKeyHelper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,83 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// IAvatarHelper is an autogenerated mock type for the IAvatarHelper type
+type IAvatarHelper struct {
+	mock.Mock
+}
+
+// Remove provides a mock function with given fields: ctx, key
+func (_m *IAvatarHelper) Remove(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Upload provides a mock function with given fields: ctx, userID, content
+func (_m *IAvatarHelper) Upload(ctx context.Context, userID uuid.UUID, content []byte) (string, string, error) {
+	ret := _m.Called(ctx, userID, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upload")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) (string, string, error)); ok {
+		return rf(ctx, userID, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) string); ok {
+		r0 = rf(ctx, userID, content)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, []byte) string); ok {
+		r1 = rf(ctx, userID, content)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, []byte) error); ok {
+		r2 = rf(ctx, userID, content)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewIAvatarHelper creates a new instance of IAvatarHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIAvatarHelper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IAvatarHelper {
+	mock := &IAvatarHelper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,67 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "sondth-test_soa/app/model"
+)
+
+// IDataExportHelper is an autogenerated mock type for the IDataExportHelper type
+type IDataExportHelper struct {
+	mock.Mock
+}
+
+// RemoveExport provides a mock function with given fields: ctx, path
+func (_m *IDataExportHelper) RemoveExport(ctx context.Context, path string) error {
+	ret := _m.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveExport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WriteExport provides a mock function with given fields: ctx, job, data
+func (_m *IDataExportHelper) WriteExport(ctx context.Context, job *entity.DataJob, data *model.UserDataExport) error {
+	ret := _m.Called(ctx, job, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteExport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.DataJob, *model.UserDataExport) error); ok {
+		r0 = rf(ctx, job, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIDataExportHelper creates a new instance of IDataExportHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIDataExportHelper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IDataExportHelper {
+	mock := &IDataExportHelper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
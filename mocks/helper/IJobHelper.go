@@ -0,0 +1,48 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	worker "sondth-test_soa/package/worker"
+)
+
+// IJobHelper is an autogenerated mock type for the IJobHelper type
+type IJobHelper struct {
+	mock.Mock
+}
+
+// Enqueue provides a mock function with given fields: ctx, name, task
+func (_m *IJobHelper) Enqueue(ctx context.Context, name string, task worker.Task) error {
+	ret := _m.Called(ctx, name, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, worker.Task) error); ok {
+		r0 = rf(ctx, name, task)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIJobHelper creates a new instance of IJobHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIJobHelper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IJobHelper {
+	mock := &IJobHelper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
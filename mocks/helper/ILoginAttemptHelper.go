@@ -0,0 +1,82 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ILoginAttemptHelper is an autogenerated mock type for the ILoginAttemptHelper type
+type ILoginAttemptHelper struct {
+	mock.Mock
+}
+
+// CheckLoginLocked provides a mock function with given fields: ctx, username, ip
+func (_m *ILoginAttemptHelper) CheckLoginLocked(ctx context.Context, username string, ip string) error {
+	ret := _m.Called(ctx, username, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckLoginLocked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, username, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordLoginFailure provides a mock function with given fields: ctx, username, ip
+func (_m *ILoginAttemptHelper) RecordLoginFailure(ctx context.Context, username string, ip string) error {
+	ret := _m.Called(ctx, username, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, username, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetLoginFailures provides a mock function with given fields: ctx, username
+func (_m *ILoginAttemptHelper) ResetLoginFailures(ctx context.Context, username string) error {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetLoginFailures")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewILoginAttemptHelper creates a new instance of ILoginAttemptHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewILoginAttemptHelper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ILoginAttemptHelper {
+	mock := &ILoginAttemptHelper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,83 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// INotificationHelper is an autogenerated mock type for the INotificationHelper type
+type INotificationHelper struct {
+	mock.Mock
+}
+
+// SendInvitation provides a mock function with given fields: ctx, user, token
+func (_m *INotificationHelper) SendInvitation(ctx context.Context, user entity.User, token string) error {
+	ret := _m.Called(ctx, user, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User, string) error); ok {
+		r0 = rf(ctx, user, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendPasswordResetToken provides a mock function with given fields: ctx, user, token
+func (_m *INotificationHelper) SendPasswordResetToken(ctx context.Context, user entity.User, token string) error {
+	ret := _m.Called(ctx, user, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendPasswordResetToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User, string) error); ok {
+		r0 = rf(ctx, user, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendVerificationCode provides a mock function with given fields: ctx, channel, target, code
+func (_m *INotificationHelper) SendVerificationCode(ctx context.Context, channel string, target string, code string) error {
+	ret := _m.Called(ctx, channel, target, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendVerificationCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, channel, target, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewINotificationHelper creates a new instance of INotificationHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewINotificationHelper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *INotificationHelper {
+	mock := &INotificationHelper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,13 +3,18 @@
 package mocks
 
 import (
+	context "context"
 	entity "sondth-test_soa/app/entity"
 
+	jwks "sondth-test_soa/package/jwks"
+
 	jwt "github.com/golang-jwt/jwt/v5"
 
 	mock "github.com/stretchr/testify/mock"
 
 	model "sondth-test_soa/app/model"
+
+	uuid "github.com/google/uuid"
 )
 
 // IOAuthHelper is an autogenerated mock type for the IOAuthHelper type
@@ -17,9 +22,69 @@ type IOAuthHelper struct {
 	mock.Mock
 }
 
-// GenerateAccessToken provides a mock function with given fields: user
-func (_m *IOAuthHelper) GenerateAccessToken(user entity.User) (string, error) {
-	ret := _m.Called(user)
+// ConsumeInvitationToken provides a mock function with given fields: ctx, token
+func (_m *IOAuthHelper) ConsumeInvitationToken(ctx context.Context, token string) (uuid.UUID, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeInvitationToken")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConsumePasswordResetToken provides a mock function with given fields: ctx, token
+func (_m *IOAuthHelper) ConsumePasswordResetToken(ctx context.Context, token string) (uuid.UUID, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumePasswordResetToken")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateAccessToken provides a mock function with given fields: ctx, user, session
+func (_m *IOAuthHelper) GenerateAccessToken(ctx context.Context, user entity.User, session model.TokenSession) (string, error) {
+	ret := _m.Called(ctx, user, session)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateAccessToken")
@@ -27,17 +92,101 @@ func (_m *IOAuthHelper) GenerateAccessToken(user entity.User) (string, error) {
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(entity.User) (string, error)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User, model.TokenSession) (string, error)); ok {
+		return rf(ctx, user, session)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User, model.TokenSession) string); ok {
+		r0 = rf(ctx, user, session)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.User, model.TokenSession) error); ok {
+		r1 = rf(ctx, user, session)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateImpersonationToken provides a mock function with given fields: ctx, impersonatorID, user, session
+func (_m *IOAuthHelper) GenerateImpersonationToken(ctx context.Context, impersonatorID uuid.UUID, user entity.User, session model.TokenSession) (string, error) {
+	ret := _m.Called(ctx, impersonatorID, user, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateImpersonationToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, entity.User, model.TokenSession) (string, error)); ok {
+		return rf(ctx, impersonatorID, user, session)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, entity.User, model.TokenSession) string); ok {
+		r0 = rf(ctx, impersonatorID, user, session)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, entity.User, model.TokenSession) error); ok {
+		r1 = rf(ctx, impersonatorID, user, session)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateInvitationToken provides a mock function with given fields: ctx, userID
+func (_m *IOAuthHelper) GenerateInvitationToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateInvitationToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateMfaChallengeToken provides a mock function with given fields: ctx, userID
+func (_m *IOAuthHelper) GenerateMfaChallengeToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateMfaChallengeToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (string, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(entity.User) string); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) string); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(entity.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -45,9 +194,37 @@ func (_m *IOAuthHelper) GenerateAccessToken(user entity.User) (string, error) {
 	return r0, r1
 }
 
-// GenerateRefreshToken provides a mock function with given fields: user
-func (_m *IOAuthHelper) GenerateRefreshToken(user entity.User) (string, error) {
-	ret := _m.Called(user)
+// GeneratePasswordResetToken provides a mock function with given fields: ctx, userID
+func (_m *IOAuthHelper) GeneratePasswordResetToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GeneratePasswordResetToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateRefreshToken provides a mock function with given fields: ctx, user, session
+func (_m *IOAuthHelper) GenerateRefreshToken(ctx context.Context, user entity.User, session model.TokenSession) (string, error) {
+	ret := _m.Called(ctx, user, session)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateRefreshToken")
@@ -55,17 +232,17 @@ func (_m *IOAuthHelper) GenerateRefreshToken(user entity.User) (string, error) {
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(entity.User) (string, error)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User, model.TokenSession) (string, error)); ok {
+		return rf(ctx, user, session)
 	}
-	if rf, ok := ret.Get(0).(func(entity.User) string); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User, model.TokenSession) string); ok {
+		r0 = rf(ctx, user, session)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(entity.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, entity.User, model.TokenSession) error); ok {
+		r1 = rf(ctx, user, session)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -101,6 +278,192 @@ func (_m *IOAuthHelper) GenerateToken(claims jwt.Claims, key string) (string, er
 	return r0, r1
 }
 
+// GetJSONWebKeys provides a mock function with no fields
+func (_m *IOAuthHelper) GetJSONWebKeys() []jwks.JSONWebKey {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJSONWebKeys")
+	}
+
+	var r0 []jwks.JSONWebKey
+	if rf, ok := ret.Get(0).(func() []jwks.JSONWebKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]jwks.JSONWebKey)
+		}
+	}
+
+	return r0
+}
+
+// GetMfaProvisioningURI provides a mock function with given fields: account, secret
+func (_m *IOAuthHelper) GetMfaProvisioningURI(account string, secret string) string {
+	ret := _m.Called(account, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMfaProvisioningURI")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(account, secret)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// IsAccessTokenRevoked provides a mock function with given fields: ctx, payload
+func (_m *IOAuthHelper) IsAccessTokenRevoked(ctx context.Context, payload *model.UserJWTPayload) (bool, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAccessTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.UserJWTPayload) (bool, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.UserJWTPayload) bool); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.UserJWTPayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAccessToken provides a mock function with given fields: ctx, payload
+func (_m *IOAuthHelper) RevokeAccessToken(ctx context.Context, payload *model.UserJWTPayload) error {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.UserJWTPayload) error); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAllUserTokens provides a mock function with given fields: ctx, userID
+func (_m *IOAuthHelper) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllUserTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeMfaChallengeToken provides a mock function with given fields: ctx, token
+func (_m *IOAuthHelper) RevokeMfaChallengeToken(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeMfaChallengeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRefreshTokenFamily provides a mock function with given fields: ctx, familyID
+func (_m *IOAuthHelper) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	ret := _m.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokenFamily")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: ctx, sessionID
+func (_m *IOAuthHelper) RevokeSession(ctx context.Context, sessionID string) error {
+	ret := _m.Called(ctx, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateRefreshToken provides a mock function with given fields: ctx, tokenString
+func (_m *IOAuthHelper) RotateRefreshToken(ctx context.Context, tokenString string) (*model.UserJWTPayload, error) {
+	ret := _m.Called(ctx, tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 *model.UserJWTPayload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.UserJWTPayload, error)); ok {
+		return rf(ctx, tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.UserJWTPayload); ok {
+		r0 = rf(ctx, tokenString)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserJWTPayload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // VerifyAccessToken provides a mock function with given fields: tokenString
 func (_m *IOAuthHelper) VerifyAccessToken(tokenString string) (*model.UserJWTPayload, error) {
 	ret := _m.Called(tokenString)
@@ -131,6 +494,36 @@ func (_m *IOAuthHelper) VerifyAccessToken(tokenString string) (*model.UserJWTPay
 	return r0, r1
 }
 
+// VerifyMfaChallengeToken provides a mock function with given fields: ctx, token
+func (_m *IOAuthHelper) VerifyMfaChallengeToken(ctx context.Context, token string) (uuid.UUID, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyMfaChallengeToken")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // VerifyRefreshToken provides a mock function with given fields: tokenString
 func (_m *IOAuthHelper) VerifyRefreshToken(tokenString string) (*model.UserJWTPayload, error) {
 	ret := _m.Called(tokenString)
@@ -0,0 +1,88 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "sondth-test_soa/app/model"
+)
+
+// IOIDCHelper is an autogenerated mock type for the IOIDCHelper type
+type IOIDCHelper struct {
+	mock.Mock
+}
+
+// ExchangeCode provides a mock function with given fields: ctx, state, code
+func (_m *IOIDCHelper) ExchangeCode(ctx context.Context, state string, code string) (*model.OidcUserInfo, error) {
+	ret := _m.Called(ctx, state, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExchangeCode")
+	}
+
+	var r0 *model.OidcUserInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.OidcUserInfo, error)); ok {
+		return rf(ctx, state, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.OidcUserInfo); ok {
+		r0 = rf(ctx, state, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OidcUserInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, state, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAuthorizationURL provides a mock function with given fields: ctx, provider
+func (_m *IOIDCHelper) GetAuthorizationURL(ctx context.Context, provider string) (string, error) {
+	ret := _m.Called(ctx, provider)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuthorizationURL")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, provider)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, provider)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, provider)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIOIDCHelper creates a new instance of IOIDCHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIOIDCHelper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IOIDCHelper {
+	mock := &IOIDCHelper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,67 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	gorm "gorm.io/gorm"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IPasswordHelper is an autogenerated mock type for the IPasswordHelper type
+type IPasswordHelper struct {
+	mock.Mock
+}
+
+// CheckPasswordReuse provides a mock function with given fields: ctx, user, password
+func (_m *IPasswordHelper) CheckPasswordReuse(ctx context.Context, user *entity.User, password string) error {
+	ret := _m.Called(ctx, user, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckPasswordReuse")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User, string) error); ok {
+		r0 = rf(ctx, user, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordPassword provides a mock function with given fields: ctx, tx, user
+func (_m *IPasswordHelper) RecordPassword(ctx context.Context, tx *gorm.DB, user *entity.User) error {
+	ret := _m.Called(ctx, tx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.User) error); ok {
+		r0 = rf(ctx, tx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIPasswordHelper creates a new instance of IPasswordHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIPasswordHelper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IPasswordHelper {
+	mock := &IPasswordHelper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,10 +4,9 @@ package mocks
 
 import (
 	context "context"
+	entity "sondth-test_soa/app/entity"
 
 	mock "github.com/stretchr/testify/mock"
-
-	uuid "github.com/google/uuid"
 )
 
 // IUserHelper is an autogenerated mock type for the IUserHelper type
@@ -15,22 +14,92 @@ type IUserHelper struct {
 	mock.Mock
 }
 
-// IsValidPermission provides a mock function with given fields: ctx, userID
-func (_m *IUserHelper) IsValidPermission(ctx context.Context, userID uuid.UUID) bool {
-	ret := _m.Called(ctx, userID)
+// GetPermissions provides a mock function with given fields: ctx, user
+func (_m *IUserHelper) GetPermissions(ctx context.Context, user *entity.User) ([]string, error) {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPermissions")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) ([]string, error)); ok {
+		return rf(ctx, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) []string); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStaffPermissions provides a mock function with given fields: ctx, user
+func (_m *IUserHelper) GetStaffPermissions(ctx context.Context, user *entity.User) ([]string, error) {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStaffPermissions")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) ([]string, error)); ok {
+		return rf(ctx, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) []string); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasPermission provides a mock function with given fields: ctx, user, permission
+func (_m *IUserHelper) HasPermission(ctx context.Context, user *entity.User, permission string) (bool, error) {
+	ret := _m.Called(ctx, user, permission)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsValidPermission")
+		panic("no return value specified for HasPermission")
 	}
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) bool); ok {
-		r0 = rf(ctx, userID)
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User, string) (bool, error)); ok {
+		return rf(ctx, user, permission)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User, string) bool); ok {
+		r0 = rf(ctx, user, permission)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.User, string) error); ok {
+		r1 = rf(ctx, user, permission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // IsValidRole provides a mock function with given fields: role
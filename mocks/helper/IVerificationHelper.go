@@ -0,0 +1,76 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// IVerificationHelper is an autogenerated mock type for the IVerificationHelper type
+type IVerificationHelper struct {
+	mock.Mock
+}
+
+// GenerateVerificationCode provides a mock function with given fields: ctx, userID, channel, target
+func (_m *IVerificationHelper) GenerateVerificationCode(ctx context.Context, userID uuid.UUID, channel string, target string) (string, error) {
+	ret := _m.Called(ctx, userID, channel, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateVerificationCode")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) (string, error)); ok {
+		return rf(ctx, userID, channel, target)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) string); ok {
+		r0 = rf(ctx, userID, channel, target)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, string) error); ok {
+		r1 = rf(ctx, userID, channel, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyVerificationCode provides a mock function with given fields: ctx, userID, channel, target, code
+func (_m *IVerificationHelper) VerifyVerificationCode(ctx context.Context, userID uuid.UUID, channel string, target string, code string) error {
+	ret := _m.Called(ctx, userID, channel, target, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyVerificationCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string, string) error); ok {
+		r0 = rf(ctx, userID, channel, target, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIVerificationHelper creates a new instance of IVerificationHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIVerificationHelper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IVerificationHelper {
+	mock := &IVerificationHelper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -8,9 +8,18 @@ import (
 
 func InitMockHelper(t *testing.T) helper.HelperCollections {
 	return helper.HelperCollections{
-		CategoryHelper: NewICategoryHelper(t),
-		ProductHelper:  NewIProductHelper(t),
-		UserHelper:     NewIUserHelper(t),
-		OAuthHelper:    NewIOAuthHelper(t),
+		CategoryHelper:     NewICategoryHelper(t),
+		ProductHelper:      NewIProductHelper(t),
+		UserHelper:         NewIUserHelper(t),
+		OAuthHelper:        NewIOAuthHelper(t),
+		NotificationHelper: NewINotificationHelper(t),
+		LoginAttemptHelper: NewILoginAttemptHelper(t),
+		VerificationHelper: NewIVerificationHelper(t),
+		PasswordHelper:     NewIPasswordHelper(t),
+		ApiKeyHelper:       NewIApiKeyHelper(t),
+		OIDCHelper:         NewIOIDCHelper(t),
+		JobHelper:          NewIJobHelper(t),
+		DataExportHelper:   NewIDataExportHelper(t),
+		AvatarHelper:       NewIAvatarHelper(t),
 	}
 }
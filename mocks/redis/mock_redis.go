@@ -64,6 +64,20 @@ func (mr *MockIRedisClientMockRecorder) Exists(ctx, key interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockIRedisClient)(nil).Exists), ctx, key)
 }
 
+// Expire mocks base method.
+func (m *MockIRedisClient) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, key, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockIRedisClientMockRecorder) Expire(ctx, key, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockIRedisClient)(nil).Expire), ctx, key, expiration)
+}
+
 // Get mocks base method.
 func (m *MockIRedisClient) Get(ctx context.Context, key string) (string, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +93,21 @@ func (mr *MockIRedisClientMockRecorder) Get(ctx, key interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIRedisClient)(nil).Get), ctx, key)
 }
 
+// GetDel mocks base method.
+func (m *MockIRedisClient) GetDel(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDel", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDel indicates an expected call of GetDel.
+func (mr *MockIRedisClientMockRecorder) GetDel(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDel", reflect.TypeOf((*MockIRedisClient)(nil).GetDel), ctx, key)
+}
+
 // Incr mocks base method.
 func (m *MockIRedisClient) Incr(ctx context.Context, key string) (int64, error) {
 	m.ctrl.T.Helper()
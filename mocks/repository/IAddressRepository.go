@@ -0,0 +1,211 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	gorm "gorm.io/gorm"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "sondth-test_soa/app/repository"
+)
+
+// IAddressRepository is an autogenerated mock type for the IAddressRepository type
+type IAddressRepository struct {
+	mock.Mock
+}
+
+// CountByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IAddressRepository) CountByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindAddressByFilter) (int64, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByFilter")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) (int64, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) int64); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, tx, data
+func (_m *IAddressRepository) Create(ctx context.Context, tx *gorm.DB, data *entity.Address) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.Address) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, tx, data
+func (_m *IAddressRepository) Delete(ctx context.Context, tx *gorm.DB, data *entity.Address) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.Address) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IAddressRepository) DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindAddressByFilter) error {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteManyByFilter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) error); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IAddressRepository) FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindAddressByFilter) ([]entity.Address, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindManyByFilter")
+	}
+
+	var r0 []entity.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) ([]entity.Address, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) []entity.Address); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOneByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IAddressRepository) FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindAddressByFilter) (*entity.Address, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneByFilter")
+	}
+
+	var r0 *entity.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) (*entity.Address, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) *entity.Address); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnsetDefaultByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IAddressRepository) UnsetDefaultByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindAddressByFilter) error {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnsetDefaultByFilter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindAddressByFilter) error); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, tx, data
+func (_m *IAddressRepository) Update(ctx context.Context, tx *gorm.DB, data *entity.Address) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.Address) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIAddressRepository creates a new instance of IAddressRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIAddressRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IAddressRepository {
+	mock := &IAddressRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
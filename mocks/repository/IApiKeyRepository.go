@@ -0,0 +1,175 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	gorm "gorm.io/gorm"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "sondth-test_soa/app/repository"
+)
+
+// IApiKeyRepository is an autogenerated mock type for the IApiKeyRepository type
+type IApiKeyRepository struct {
+	mock.Mock
+}
+
+// CountByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IApiKeyRepository) CountByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindApiKeyByFilter) (int64, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByFilter")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindApiKeyByFilter) (int64, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindApiKeyByFilter) int64); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindApiKeyByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, tx, data
+func (_m *IApiKeyRepository) Create(ctx context.Context, tx *gorm.DB, data *entity.ApiKey) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.ApiKey) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IApiKeyRepository) FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindApiKeyByFilter) ([]entity.ApiKey, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindManyByFilter")
+	}
+
+	var r0 []entity.ApiKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindApiKeyByFilter) ([]entity.ApiKey, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindApiKeyByFilter) []entity.ApiKey); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ApiKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindApiKeyByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOneByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IApiKeyRepository) FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindApiKeyByFilter) (*entity.ApiKey, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneByFilter")
+	}
+
+	var r0 *entity.ApiKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindApiKeyByFilter) (*entity.ApiKey, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindApiKeyByFilter) *entity.ApiKey); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ApiKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindApiKeyByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, tx, data
+func (_m *IApiKeyRepository) Update(ctx context.Context, tx *gorm.DB, data *entity.ApiKey) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.ApiKey) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLastUsedAt provides a mock function with given fields: ctx, tx, data
+func (_m *IApiKeyRepository) UpdateLastUsedAt(ctx context.Context, tx *gorm.DB, data *entity.ApiKey) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLastUsedAt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.ApiKey) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIApiKeyRepository creates a new instance of IApiKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIApiKeyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IApiKeyRepository {
+	mock := &IApiKeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
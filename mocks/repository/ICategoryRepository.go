@@ -18,6 +18,34 @@ type ICategoryRepository struct {
 	mock.Mock
 }
 
+// CountByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *ICategoryRepository) CountByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindCategoryByFilter) (int64, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByFilter")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindCategoryByFilter) (int64, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindCategoryByFilter) int64); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindCategoryByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, tx, data
 func (_m *ICategoryRepository) Create(ctx context.Context, tx *gorm.DB, data *entity.Category) error {
 	ret := _m.Called(ctx, tx, data)
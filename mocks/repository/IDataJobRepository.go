@@ -0,0 +1,129 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	gorm "gorm.io/gorm"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "sondth-test_soa/app/repository"
+)
+
+// IDataJobRepository is an autogenerated mock type for the IDataJobRepository type
+type IDataJobRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, tx, data
+func (_m *IDataJobRepository) Create(ctx context.Context, tx *gorm.DB, data *entity.DataJob) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.DataJob) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IDataJobRepository) FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindDataJobByFilter) ([]entity.DataJob, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindManyByFilter")
+	}
+
+	var r0 []entity.DataJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindDataJobByFilter) ([]entity.DataJob, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindDataJobByFilter) []entity.DataJob); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.DataJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindDataJobByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOneByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IDataJobRepository) FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindDataJobByFilter) (*entity.DataJob, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneByFilter")
+	}
+
+	var r0 *entity.DataJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindDataJobByFilter) (*entity.DataJob, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindDataJobByFilter) *entity.DataJob); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.DataJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindDataJobByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, tx, data
+func (_m *IDataJobRepository) Update(ctx context.Context, tx *gorm.DB, data *entity.DataJob) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.DataJob) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIDataJobRepository creates a new instance of IDataJobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIDataJobRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IDataJobRepository {
+	mock := &IDataJobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
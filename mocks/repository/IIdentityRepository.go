@@ -0,0 +1,117 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	gorm "gorm.io/gorm"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "sondth-test_soa/app/repository"
+)
+
+// IIdentityRepository is an autogenerated mock type for the IIdentityRepository type
+type IIdentityRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, tx, data
+func (_m *IIdentityRepository) Create(ctx context.Context, tx *gorm.DB, data *entity.Identity) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.Identity) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IIdentityRepository) DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindIdentityByFilter) error {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteManyByFilter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindIdentityByFilter) error); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindOneByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IIdentityRepository) FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindIdentityByFilter) (*entity.Identity, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneByFilter")
+	}
+
+	var r0 *entity.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindIdentityByFilter) (*entity.Identity, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindIdentityByFilter) *entity.Identity); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindIdentityByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, tx, data
+func (_m *IIdentityRepository) Update(ctx context.Context, tx *gorm.DB, data *entity.Identity) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.Identity) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIIdentityRepository creates a new instance of IIdentityRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIIdentityRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IIdentityRepository {
+	mock := &IIdentityRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	gorm "gorm.io/gorm"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "sondth-test_soa/app/repository"
+)
+
+// IPasswordHistoryRepository is an autogenerated mock type for the IPasswordHistoryRepository type
+type IPasswordHistoryRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, tx, data
+func (_m *IPasswordHistoryRepository) Create(ctx context.Context, tx *gorm.DB, data *entity.PasswordHistory) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.PasswordHistory) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IPasswordHistoryRepository) DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindPasswordHistoryByFilter) error {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteManyByFilter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindPasswordHistoryByFilter) error); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IPasswordHistoryRepository) FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindPasswordHistoryByFilter) ([]entity.PasswordHistory, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindManyByFilter")
+	}
+
+	var r0 []entity.PasswordHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindPasswordHistoryByFilter) ([]entity.PasswordHistory, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindPasswordHistoryByFilter) []entity.PasswordHistory); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PasswordHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindPasswordHistoryByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIPasswordHistoryRepository creates a new instance of IPasswordHistoryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIPasswordHistoryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IPasswordHistoryRepository {
+	mock := &IPasswordHistoryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,63 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	gorm "gorm.io/gorm"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "sondth-test_soa/app/repository"
+)
+
+// IPermissionRepository is an autogenerated mock type for the IPermissionRepository type
+type IPermissionRepository struct {
+	mock.Mock
+}
+
+// FindManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IPermissionRepository) FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindPermissionByFilter) ([]entity.Permission, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindManyByFilter")
+	}
+
+	var r0 []entity.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindPermissionByFilter) ([]entity.Permission, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindPermissionByFilter) []entity.Permission); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindPermissionByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIPermissionRepository creates a new instance of IPermissionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIPermissionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IPermissionRepository {
+	mock := &IPermissionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
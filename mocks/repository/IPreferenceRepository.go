@@ -0,0 +1,99 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	gorm "gorm.io/gorm"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "sondth-test_soa/app/repository"
+)
+
+// IPreferenceRepository is an autogenerated mock type for the IPreferenceRepository type
+type IPreferenceRepository struct {
+	mock.Mock
+}
+
+// DeleteManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IPreferenceRepository) DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindPreferenceByFilter) error {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteManyByFilter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindPreferenceByFilter) error); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindOneByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IPreferenceRepository) FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindPreferenceByFilter) (*entity.Preference, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneByFilter")
+	}
+
+	var r0 *entity.Preference
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindPreferenceByFilter) (*entity.Preference, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindPreferenceByFilter) *entity.Preference); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Preference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindPreferenceByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, tx, data
+func (_m *IPreferenceRepository) Save(ctx context.Context, tx *gorm.DB, data *entity.Preference) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.Preference) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIPreferenceRepository creates a new instance of IPreferenceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIPreferenceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IPreferenceRepository {
+	mock := &IPreferenceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
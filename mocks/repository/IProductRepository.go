@@ -142,6 +142,24 @@ func (_m *IProductRepository) FindOneByFilter(ctx context.Context, tx *gorm.DB,
 	return r0, r1
 }
 
+// Transaction provides a mock function with given fields: ctx, fn
+func (_m *IProductRepository) Transaction(ctx context.Context, fn func(*gorm.DB) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(*gorm.DB) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, tx, data
 func (_m *IProductRepository) Update(ctx context.Context, tx *gorm.DB, data *entity.Product) error {
 	ret := _m.Called(ctx, tx, data)
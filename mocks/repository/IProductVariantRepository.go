@@ -0,0 +1,179 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "sondth-test_soa/app/entity"
+
+	gorm "gorm.io/gorm"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "sondth-test_soa/app/repository"
+
+	uuid "github.com/google/uuid"
+)
+
+// IProductVariantRepository is an autogenerated mock type for the IProductVariantRepository type
+type IProductVariantRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, tx, data
+func (_m *IProductVariantRepository) Create(ctx context.Context, tx *gorm.DB, data *entity.ProductVariant) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.ProductVariant) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, tx, data
+func (_m *IProductVariantRepository) Delete(ctx context.Context, tx *gorm.DB, data *entity.ProductVariant) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.ProductVariant) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IProductVariantRepository) FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindProductVariantByFilter) ([]entity.ProductVariant, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindManyByFilter")
+	}
+
+	var r0 []entity.ProductVariant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindProductVariantByFilter) ([]entity.ProductVariant, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindProductVariantByFilter) []entity.ProductVariant); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ProductVariant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindProductVariantByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOneByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IProductVariantRepository) FindOneByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindProductVariantByFilter) (*entity.ProductVariant, error) {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneByFilter")
+	}
+
+	var r0 *entity.ProductVariant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindProductVariantByFilter) (*entity.ProductVariant, error)); ok {
+		return rf(ctx, tx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindProductVariantByFilter) *entity.ProductVariant); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ProductVariant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.FindProductVariantByFilter) error); ok {
+		r1 = rf(ctx, tx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVariantSummaries provides a mock function with given fields: ctx, tx, productIDs
+func (_m *IProductVariantRepository) GetVariantSummaries(ctx context.Context, tx *gorm.DB, productIDs []uuid.UUID) ([]entity.ProductVariantSummary, error) {
+	ret := _m.Called(ctx, tx, productIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVariantSummaries")
+	}
+
+	var r0 []entity.ProductVariantSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, []uuid.UUID) ([]entity.ProductVariantSummary, error)); ok {
+		return rf(ctx, tx, productIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, []uuid.UUID) []entity.ProductVariantSummary); ok {
+		r0 = rf(ctx, tx, productIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ProductVariantSummary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, []uuid.UUID) error); ok {
+		r1 = rf(ctx, tx, productIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, tx, data
+func (_m *IProductVariantRepository) Update(ctx context.Context, tx *gorm.DB, data *entity.ProductVariant) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.ProductVariant) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIProductVariantRepository creates a new instance of IProductVariantRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIProductVariantRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IProductVariantRepository {
+	mock := &IProductVariantRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
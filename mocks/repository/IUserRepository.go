@@ -142,6 +142,42 @@ func (_m *IUserRepository) Update(ctx context.Context, tx *gorm.DB, data *entity
 	return r0
 }
 
+// UpdatePassword provides a mock function with given fields: ctx, tx, data
+func (_m *IUserRepository) UpdatePassword(ctx context.Context, tx *gorm.DB, data *entity.User) error {
+	ret := _m.Called(ctx, tx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *entity.User) error); ok {
+		r0 = rf(ctx, tx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRoleByFilter provides a mock function with given fields: ctx, tx, filter, role
+func (_m *IUserRepository) UpdateRoleByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindUserByFilter, role string) error {
+	ret := _m.Called(ctx, tx, filter, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRoleByFilter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindUserByFilter, string) error); ok {
+		r0 = rf(ctx, tx, filter, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewIUserRepository creates a new instance of IUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIUserRepository(t interface {
@@ -82,6 +82,24 @@ func (_m *IWishlistRepository) Delete(ctx context.Context, tx *gorm.DB, data *en
 	return r0
 }
 
+// DeleteManyByFilter provides a mock function with given fields: ctx, tx, filter
+func (_m *IWishlistRepository) DeleteManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindWishlistByFilter) error {
+	ret := _m.Called(ctx, tx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteManyByFilter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.FindWishlistByFilter) error); ok {
+		r0 = rf(ctx, tx, filter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindManyByFilter provides a mock function with given fields: ctx, tx, filter
 func (_m *IWishlistRepository) FindManyByFilter(ctx context.Context, tx *gorm.DB, filter *repository.FindWishlistByFilter) ([]entity.Wishlist, error) {
 	ret := _m.Called(ctx, tx, filter)
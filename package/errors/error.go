@@ -16,6 +16,9 @@ import (
 type CustomError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
+
+	args     []any // arguments of the message, kept to write it again in another language
+	verbatim bool  // the message isn't from the catalog and is never translated
 }
 
 const (
@@ -28,14 +31,37 @@ const (
 	ErrCodeValidatorFormat       = 2
 	ErrCodeValidatorVerifiedData = 3
 
+	// Password Policy Error
+	ErrCodePasswordTooShort         = 4
+	ErrCodePasswordMissingUppercase = 5
+	ErrCodePasswordMissingLowercase = 6
+	ErrCodePasswordMissingDigit     = 7
+	ErrCodePasswordMissingSymbol    = 8
+	ErrCodePasswordBreached         = 9
+
 	// User Error
-	ErrCodeUserNotFound     = 10
-	ErrCodeUserExisted      = 11
-	ErrCodeWishlistNotFound = 12
+	ErrCodeUserNotFound              = 10
+	ErrCodeUserExisted               = 11
+	ErrCodeWishlistNotFound          = 12
+	ErrCodeEmailExisted              = 13
+	ErrCodePhoneExisted              = 14
+	ErrCodeInvalidVerificationCode   = 15
+	ErrCodeVerificationTargetMissing = 16
+	ErrCodeAlreadyVerified           = 17
+	ErrCodeEmailNotVerified          = 18
+	ErrCodePhoneNotVerified          = 19
 
 	// OAuth Error
 	ErrCodeTokenExpired      = 20
 	ErrCodeIncorrectPassword = 21
+	ErrCodeInvalidToken      = 22
+	ErrCodeTokenReused       = 23
+	ErrCodeTokenRevoked      = 24
+	ErrCodeMfaRequired       = 25
+	ErrCodeInvalidMfaCode    = 26
+	ErrCodeMfaAlreadyEnabled = 27
+	ErrCodeMfaNotEnrolled    = 28
+	ErrCodeAccountLocked     = 29
 
 	// Category Error
 	ErrCodeCategoryExisted  = 30
@@ -47,6 +73,74 @@ const (
 	ErrCodeProductAlreadyInWishlist = 42
 	ErrCodeReviewNotFound          = 43
 	ErrCodeReviewAlreadyExists     = 44
+	ErrCodeVariantNotFound          = 45
+	ErrCodeVariantSkuExisted        = 46
+	ErrCodeVariantOptionsExisted    = 47
+
+	// Role Error
+	ErrCodeRoleNotFound          = 50
+	ErrCodeRoleExisted           = 51
+	ErrCodePermissionNotFound    = 52
+	ErrCodeRoleBuiltIn           = 53
+	ErrCodeRoleAlreadyAssigned   = 54
+	ErrCodeRolePermissionNotHeld = 55
+	ErrCodeAdminRoleChangeDenied = 56
+
+	// Session Error
+	ErrCodeSessionNotFound = 60
+
+	// Password Error
+	ErrCodePasswordReused  = 70
+	ErrCodePasswordTooLong = 71
+
+	// API Key Error
+	ErrCodeApiKeyNotFound          = 80
+	ErrCodeInvalidApiKey           = 81
+	ErrCodeApiKeyExpiryInvalid     = 82
+	ErrCodeApiKeyPermissionNotHeld = 83
+
+	// OIDC Error
+	ErrCodeOidcProviderNotFound = 90
+	ErrCodeOidcInvalidState     = 91
+	ErrCodeOidcLoginFailed      = 92
+
+	// Impersonation Error
+	ErrCodeImpersonationForbidden = 100
+	ErrCodeCannotImpersonate      = 101
+
+	// User Status Error
+	ErrCodeUserSuspended         = 110
+	ErrCodeUserDeleted           = 111
+	ErrCodeSuspensionEndInvalid  = 112
+	ErrCodeCannotChangeOwnStatus = 113
+	ErrCodeCannotChangeOwnRole   = 114
+	ErrCodeUserNotActivated      = 115
+	ErrCodeAdminRoleForbidden    = 116
+	ErrCodeUserMorePrivileged    = 117
+
+	// Personal Data Error
+	ErrCodeDataJobNotFound    = 120
+	ErrCodeDataJobInProgress  = 121
+	ErrCodeDataExportNotReady = 122
+
+	// Profile Error
+	ErrCodeAddressNotFound     = 130
+	ErrCodeAddressLimitReached = 131
+	ErrCodeAvatarInvalid       = 132
+	ErrCodeAvatarTooLarge      = 133
+
+	// Inventory Error
+	ErrCodeInsufficientStock    = 140
+	ErrCodeStockQuantityInvalid = 141
+	ErrCodeVariantHasStock      = 142
+	ErrCodeVariantRequired      = 143
+	ErrCodeProductHasStock      = 144
+
+	// List Error
+	ErrCodeSortFieldInvalid     = 150
+	ErrCodeSortDirectionInvalid = 151
+	ErrCodeTooManySortKeys      = 152
+	ErrCodeCursorInvalid        = 153
 
 	// System Error
 	ErrCodeInternalServerError = 500
@@ -71,6 +165,32 @@ var messages = map[int]map[string]string{
 		LangEN: "%s is incorrect. Please check again",
 	},
 
+	// Password Policy
+	ErrCodePasswordTooShort: {
+		LangVN: "%s phải có ít nhất %d ký tự",
+		LangEN: "%s must be at least %d characters long",
+	},
+	ErrCodePasswordMissingUppercase: {
+		LangVN: "%s phải có ít nhất một chữ in hoa",
+		LangEN: "%s must contain at least one uppercase letter",
+	},
+	ErrCodePasswordMissingLowercase: {
+		LangVN: "%s phải có ít nhất một chữ thường",
+		LangEN: "%s must contain at least one lowercase letter",
+	},
+	ErrCodePasswordMissingDigit: {
+		LangVN: "%s phải có ít nhất một chữ số",
+		LangEN: "%s must contain at least one digit",
+	},
+	ErrCodePasswordMissingSymbol: {
+		LangVN: "%s phải có ít nhất một ký tự đặc biệt",
+		LangEN: "%s must contain at least one special character",
+	},
+	ErrCodePasswordBreached: {
+		LangVN: "%s đã bị lộ trong một vụ rò rỉ dữ liệu. Vui lòng chọn mật khẩu khác",
+		LangEN: "%s has appeared in a data breach. Please choose another password",
+	},
+
 	// System Error
 	ErrCodeInternalServerError: {
 		LangVN: "Lỗi hệ thống",
@@ -106,6 +226,34 @@ var messages = map[int]map[string]string{
 		LangVN: "Không tìm thấy danh sách yêu thích. Vui lòng kiểm tra lại",
 		LangEN: "Wishlist not found. Please check again",
 	},
+	ErrCodeEmailExisted: {
+		LangVN: "Email đã được sử dụng",
+		LangEN: "Email is already in use",
+	},
+	ErrCodePhoneExisted: {
+		LangVN: "Số điện thoại đã được sử dụng",
+		LangEN: "Phone number is already in use",
+	},
+	ErrCodeInvalidVerificationCode: {
+		LangVN: "Mã xác minh không chính xác hoặc đã hết hạn",
+		LangEN: "Verification code is incorrect or has expired",
+	},
+	ErrCodeVerificationTargetMissing: {
+		LangVN: "Chưa có email hoặc số điện thoại để xác minh",
+		LangEN: "There is no email or phone number to verify",
+	},
+	ErrCodeAlreadyVerified: {
+		LangVN: "Thông tin đã được xác minh",
+		LangEN: "Already verified",
+	},
+	ErrCodeEmailNotVerified: {
+		LangVN: "Email chưa được xác minh",
+		LangEN: "Email is not verified",
+	},
+	ErrCodePhoneNotVerified: {
+		LangVN: "Số điện thoại chưa được xác minh",
+		LangEN: "Phone number is not verified",
+	},
 
 	// OAuth Error
 	ErrCodeTokenExpired: {
@@ -116,6 +264,38 @@ var messages = map[int]map[string]string{
 		LangVN: "Mật khẩu không chính xác",
 		LangEN: "Password is incorrect",
 	},
+	ErrCodeInvalidToken: {
+		LangVN: "Token không hợp lệ",
+		LangEN: "Token is invalid",
+	},
+	ErrCodeTokenReused: {
+		LangVN: "Token đã được sử dụng. Vui lòng đăng nhập lại",
+		LangEN: "Token has already been used. Please login again",
+	},
+	ErrCodeTokenRevoked: {
+		LangVN: "Token đã bị thu hồi. Vui lòng đăng nhập lại",
+		LangEN: "Token has been revoked. Please login again",
+	},
+	ErrCodeMfaRequired: {
+		LangVN: "Yêu cầu xác thực hai lớp. Vui lòng đăng nhập bằng mã xác thực",
+		LangEN: "Two-factor authentication is required. Please login with your authentication code",
+	},
+	ErrCodeInvalidMfaCode: {
+		LangVN: "Mã xác thực không chính xác",
+		LangEN: "Authentication code is incorrect",
+	},
+	ErrCodeMfaAlreadyEnabled: {
+		LangVN: "Xác thực hai lớp đã được bật",
+		LangEN: "Two-factor authentication is already enabled",
+	},
+	ErrCodeMfaNotEnrolled: {
+		LangVN: "Chưa đăng ký xác thực hai lớp",
+		LangEN: "Two-factor authentication is not enrolled",
+	},
+	ErrCodeAccountLocked: {
+		LangVN: "Tài khoản tạm thời bị khóa do đăng nhập sai quá nhiều lần. Vui lòng thử lại sau",
+		LangEN: "Account is temporarily locked due to too many failed login attempts. Please try again later",
+	},
 
 	// Category Error
 	ErrCodeCategoryExisted: {
@@ -140,6 +320,18 @@ var messages = map[int]map[string]string{
 		LangVN: "Sản phẩm đã tồn tại trong danh sách yêu thích. Vui lòng kiểm tra lại",
 		LangEN: "Product already exists in wishlist. Please check again",
 	},
+	ErrCodeVariantNotFound: {
+		LangVN: "Không tìm thấy phiên bản sản phẩm. Vui lòng kiểm tra lại",
+		LangEN: "Product variant not found. Please check again",
+	},
+	ErrCodeVariantSkuExisted: {
+		LangVN: "Mã SKU đã tồn tại. Vui lòng kiểm tra lại",
+		LangEN: "SKU already exists. Please check again",
+	},
+	ErrCodeVariantOptionsExisted: {
+		LangVN: "Sản phẩm đã có phiên bản với các tùy chọn này",
+		LangEN: "The product already has a variant with these options",
+	},
 	ErrCodeReviewNotFound: {
 		LangVN: "Không tìm thấy đánh giá. Vui lòng kiểm tra lại",
 		LangEN: "Review not found. Please check again",
@@ -148,6 +340,186 @@ var messages = map[int]map[string]string{
 		LangVN: "Bạn đã đánh giá sản phẩm này. Vui lòng kiểm tra lại",
 		LangEN: "You have already reviewed this product. Please check again",
 	},
+
+	// Role Error
+	ErrCodeRoleNotFound: {
+		LangVN: "Vai trò không tồn tại. Vui lòng kiểm tra lại",
+		LangEN: "Role not found. Please check again",
+	},
+	ErrCodeRoleExisted: {
+		LangVN: "Vai trò đã tồn tại. Vui lòng kiểm tra lại",
+		LangEN: "Role already exists. Please check again",
+	},
+	ErrCodePermissionNotFound: {
+		LangVN: "Quyền không tồn tại. Vui lòng kiểm tra lại",
+		LangEN: "Permission not found. Please check again",
+	},
+	ErrCodeRoleBuiltIn: {
+		LangVN: "Không thể xóa vai trò mặc định",
+		LangEN: "Default roles cannot be deleted",
+	},
+	ErrCodeRoleAlreadyAssigned: {
+		LangVN: "Người dùng đã có vai trò này. Vui lòng kiểm tra lại",
+		LangEN: "User already has this role. Please check again",
+	},
+	ErrCodeRolePermissionNotHeld: {
+		LangVN: "Không thể cấp quyền %s qua vai trò khi bạn không có quyền này",
+		LangEN: "Can't grant the %s permission through a role, you don't have it",
+	},
+	ErrCodeAdminRoleChangeDenied: {
+		LangVN: "Chỉ quản trị viên mới có thể thay đổi vai trò quản trị",
+		LangEN: "Only admins can change the admin role",
+	},
+	ErrCodeSessionNotFound: {
+		LangVN: "Không tìm thấy phiên đăng nhập",
+		LangEN: "Session not found",
+	},
+
+	// Password Error
+	ErrCodePasswordReused: {
+		LangVN: "Mật khẩu mới không được trùng với các mật khẩu gần đây",
+		LangEN: "New password must not be one of your recent passwords",
+	},
+	ErrCodePasswordTooLong: {
+		LangVN: "%s không được dài quá %d byte",
+		LangEN: "%s must be at most %d bytes long",
+	},
+
+	// API Key Error
+	ErrCodeApiKeyNotFound: {
+		LangVN: "Không tìm thấy API key",
+		LangEN: "API key not found",
+	},
+	ErrCodeInvalidApiKey: {
+		LangVN: "API key không hợp lệ, đã hết hạn hoặc đã bị thu hồi",
+		LangEN: "API key is invalid, expired or revoked",
+	},
+	ErrCodeApiKeyExpiryInvalid: {
+		LangVN: "Thời gian hết hạn của API key phải ở tương lai",
+		LangEN: "API key expiry must be in the future",
+	},
+	ErrCodeApiKeyPermissionNotHeld: {
+		LangVN: "Không thể cấp quyền %s cho API key khi bạn không có quyền này",
+		LangEN: "Can't grant the %s permission to an API key, you don't have it",
+	},
+
+	// OIDC Error
+	ErrCodeOidcProviderNotFound: {
+		LangVN: "Nhà cung cấp đăng nhập không tồn tại",
+		LangEN: "Identity provider not found",
+	},
+	ErrCodeOidcInvalidState: {
+		LangVN: "Phiên đăng nhập không hợp lệ hoặc đã hết hạn. Vui lòng thử lại",
+		LangEN: "Login request is invalid or has expired. Please try again",
+	},
+	ErrCodeOidcLoginFailed: {
+		LangVN: "Đăng nhập bằng nhà cung cấp bên ngoài thất bại",
+		LangEN: "Login with the identity provider failed",
+	},
+	ErrCodeImpersonationForbidden: {
+		LangVN: "Không thể thực hiện thao tác này khi đang đăng nhập thay người dùng",
+		LangEN: "This action is not allowed while impersonating a user",
+	},
+	ErrCodeCannotImpersonate: {
+		LangVN: "Không thể đăng nhập thay người dùng này",
+		LangEN: "This user can't be impersonated",
+	},
+	ErrCodeUserSuspended: {
+		LangVN: "Tài khoản đã bị tạm khóa",
+		LangEN: "The account is suspended",
+	},
+	ErrCodeUserDeleted: {
+		LangVN: "Tài khoản đã bị xóa",
+		LangEN: "The account has been deleted",
+	},
+	ErrCodeSuspensionEndInvalid: {
+		LangVN: "Thời gian kết thúc tạm khóa phải ở tương lai",
+		LangEN: "The suspension end time must be in the future",
+	},
+	ErrCodeCannotChangeOwnStatus: {
+		LangVN: "Không thể thay đổi trạng thái tài khoản của chính mình",
+		LangEN: "You can't change the status of your own account",
+	},
+	ErrCodeCannotChangeOwnRole: {
+		LangVN: "Bạn không thể thay đổi vai trò của chính mình",
+		LangEN: "You can't change the role of your own account",
+	},
+	ErrCodeUserNotActivated: {
+		LangVN: "Tài khoản chưa được kích hoạt",
+		LangEN: "The account has not been activated yet",
+	},
+	ErrCodeAdminRoleForbidden: {
+		LangVN: "Chỉ quản trị viên mới có thể cấp vai trò quản trị",
+		LangEN: "Only admins can give the admin role",
+	},
+	ErrCodeUserMorePrivileged: {
+		LangVN: "Người dùng này có nhiều quyền hơn bạn",
+		LangEN: "This user has more privileges than you",
+	},
+	ErrCodeDataJobNotFound: {
+		LangVN: "Không tìm thấy yêu cầu dữ liệu",
+		LangEN: "Data request not found",
+	},
+	ErrCodeDataJobInProgress: {
+		LangVN: "Yêu cầu dữ liệu trước đó đang được xử lý",
+		LangEN: "A previous data request is still being processed",
+	},
+	ErrCodeDataExportNotReady: {
+		LangVN: "Dữ liệu xuất chưa sẵn sàng hoặc đã hết hạn",
+		LangEN: "The data export is not ready or has expired",
+	},
+	ErrCodeAddressNotFound: {
+		LangVN: "Không tìm thấy địa chỉ",
+		LangEN: "Address not found",
+	},
+	ErrCodeAddressLimitReached: {
+		LangVN: "Sổ địa chỉ chỉ chứa tối đa %d địa chỉ",
+		LangEN: "The address book can hold at most %d addresses",
+	},
+	ErrCodeAvatarInvalid: {
+		LangVN: "Ảnh đại diện phải là ảnh PNG, JPEG hoặc WEBP",
+		LangEN: "The avatar must be a PNG, JPEG or WEBP image",
+	},
+	ErrCodeAvatarTooLarge: {
+		LangVN: "Ảnh đại diện không được vượt quá %d KB",
+		LangEN: "The avatar can't be larger than %d KB",
+	},
+	ErrCodeInsufficientStock: {
+		LangVN: "Không đủ hàng trong kho",
+		LangEN: "There isn't enough stock",
+	},
+	ErrCodeStockQuantityInvalid: {
+		LangVN: "Số lượng không hợp lệ với loại biến động kho %s",
+		LangEN: "The quantity is invalid for the %s stock movement",
+	},
+	ErrCodeVariantHasStock: {
+		LangVN: "Không thể xóa biến thể còn hàng trong kho hoặc đang được giữ",
+		LangEN: "Can't delete a variant that still has stock or reserved stock",
+	},
+	ErrCodeVariantRequired: {
+		LangVN: "Sản phẩm có biến thể, cần chọn biến thể cho biến động kho",
+		LangEN: "The product has variants, the stock movement needs a variant",
+	},
+	ErrCodeProductHasStock: {
+		LangVN: "Không thể xóa sản phẩm còn hàng trong kho hoặc đang được giữ",
+		LangEN: "Can't delete a product that still has stock or reserved stock",
+	},
+	ErrCodeSortFieldInvalid: {
+		LangVN: "Không thể sắp xếp theo %s, chỉ hỗ trợ: %s",
+		LangEN: "Can't sort by %s, the supported fields are: %s",
+	},
+	ErrCodeSortDirectionInvalid: {
+		LangVN: "Chiều sắp xếp %s không hợp lệ, chỉ hỗ trợ asc hoặc desc",
+		LangEN: "The sort direction %s is invalid, use asc or desc",
+	},
+	ErrCodeTooManySortKeys: {
+		LangVN: "Chỉ có thể sắp xếp theo tối đa %d trường",
+		LangEN: "A list can be sorted by at most %d fields",
+	},
+	ErrCodeCursorInvalid: {
+		LangVN: "Con trỏ phân trang không hợp lệ",
+		LangEN: "The page cursor is invalid",
+	},
 }
 
 func New(code int) *CustomError {
@@ -157,10 +529,21 @@ func New(code int) *CustomError {
 	}
 }
 
-func NewCustomError(code int, message string) *CustomError {
+// Newf creates an error whose message of the catalog is formatted with args, e.g: the name of the invalid field
+func Newf(code int, args ...any) *CustomError {
 	return &CustomError{
 		Code:    code,
-		Message: message,
+		Message: GetCustomMessage(code, args...),
+		args:    args,
+	}
+}
+
+// NewCustomError creates an error with a message of its own, it's not translated
+func NewCustomError(code int, message string) *CustomError {
+	return &CustomError{
+		Code:     code,
+		Message:  message,
+		verbatim: true,
 	}
 }
 
@@ -173,46 +556,40 @@ func NewValidatorError(err error) *CustomError {
 		field := errDetail.Field()
 		tag := errDetail.Tag()
 
-		code := convertValidatorTag(tag)
-		return &CustomError{
-			Code:    code,
-			Message: GetCustomMessage(code, field),
+		if tag == _validator.PASSWORD {
+			return newPasswordPolicyError(field, errDetail.Value())
 		}
 
+		return Newf(convertValidatorTag(tag), field)
+
 	}
 
 	return New(ErrCodeInternalServerError)
 }
 
+// GetCustomMessage returns the message of code in the default language, formatted with args
 func GetCustomMessage(code int, args ...any) string {
-	msg, ok := messages[code][LangEN]
-	if !ok {
-		return messages[ErrCodeInternalServerError][LangEN]
+	if _, ok := messages[code]; !ok {
+		return GetLocalizedMessage(defaultLanguage, ErrCodeInternalServerError)
 	}
 
-	return fmt.Sprintf(msg, args...)
+	return fmt.Sprintf(GetLocalizedMessage(defaultLanguage, code), args...)
 }
 
+// GetMessage returns the message of code in the default language
 func GetMessage(code int) string {
-	msg, ok := messages[code][LangEN]
-	if !ok {
-		return messages[ErrCodeInternalServerError][LangEN]
-	}
-
-	return msg
+	return GetLocalizedMessage(defaultLanguage, code)
 }
 
-func FormatErrorResponse(err error) utils.HttpResponse {
-	if e, ok := err.(*CustomError); ok {
-		return utils.HttpResponse{
-			Success: false,
-			Data:    e,
-		}
+// FormatErrorResponse writes err in the language of the request
+func FormatErrorResponse(ctx context.Context, err error) utils.HttpResponse {
+	if _, ok := err.(*CustomError); !ok {
+		err = New(ErrCodeInternalServerError)
 	}
 
 	return utils.HttpResponse{
 		Success: false,
-		Data:    New(ErrCodeInternalServerError),
+		Data:    Localize(ctx, err),
 	}
 }
 
@@ -225,9 +602,37 @@ func (err *CustomError) GetCode() int {
 }
 
 // --------------------------------------
+// newPasswordPolicyError explains which rule of the password policy the value violates
+func newPasswordPolicyError(field string, value any) *CustomError {
+	password, _ := value.(string)
+	policy := _validator.GetPasswordPolicy()
+
+	var code int
+	switch policy.Check(password) {
+	case _validator.PASSWORD_RULE_MIN_LENGTH:
+		return Newf(ErrCodePasswordTooShort, field, policy.MinLength)
+	case _validator.PASSWORD_RULE_MAX_LENGTH:
+		return Newf(ErrCodePasswordTooLong, field, policy.MaxLength)
+	case _validator.PASSWORD_RULE_UPPERCASE:
+		code = ErrCodePasswordMissingUppercase
+	case _validator.PASSWORD_RULE_LOWERCASE:
+		code = ErrCodePasswordMissingLowercase
+	case _validator.PASSWORD_RULE_DIGIT:
+		code = ErrCodePasswordMissingDigit
+	case _validator.PASSWORD_RULE_SYMBOL:
+		code = ErrCodePasswordMissingSymbol
+	case _validator.PASSWORD_RULE_BREACHED:
+		code = ErrCodePasswordBreached
+	default:
+		code = ErrCodeValidatorFormat
+	}
+
+	return Newf(code, field)
+}
+
 func convertValidatorTag(tag string) int {
 	switch tag {
-	case _validator.EMAIL, _validator.PHONE_NUMBER:
+	case _validator.EMAIL, _validator.PHONE_NUMBER, _validator.ONE_OF, _validator.COUNTRY, _validator.CURRENCY:
 		return ErrCodeValidatorFormat
 	case _validator.EQUAL_FIELD:
 		return ErrCodeValidatorVerifiedData
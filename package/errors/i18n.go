@@ -0,0 +1,214 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"sondth-test_soa/utils"
+)
+
+// defaultLanguage is used when the request doesn't tell which language the client reads
+var defaultLanguage = LangEN
+
+// SetDefaultLanguage changes the language of errors of requests without a language, unknown languages are rejected
+func SetDefaultLanguage(lang string) error {
+	lang = normalizeLanguage(lang)
+	if !IsSupportedLanguage(lang) {
+		return fmt.Errorf("unsupported default language: %q", lang)
+	}
+
+	defaultLanguage = lang
+	return nil
+}
+
+func GetDefaultLanguage() string {
+	return defaultLanguage
+}
+
+// LoadCatalog adds the messages of every <language>.yaml, <language>.yml or <language>.json file of dir,
+// each file maps an error code to its message, e.g: `10: "Product not found"`.
+// Messages of a file replace the built-in ones of the same language and code.
+// The catalog is read at startup, it must not be loaded while requests are served.
+func LoadCatalog(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error reading message catalog: %v", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := filepath.Ext(entry.Name())
+		lang := normalizeLanguage(strings.TrimSuffix(entry.Name(), ext))
+
+		var unmarshal func([]byte, any) error
+		switch ext {
+		case ".yaml", ".yml":
+			unmarshal = yaml.Unmarshal
+		case ".json":
+			unmarshal = json.Unmarshal
+		default:
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("error reading message catalog %s: %v", entry.Name(), err)
+		}
+		var catalog map[string]string
+		if err := unmarshal(content, &catalog); err != nil {
+			return fmt.Errorf("error parsing message catalog %s: %v", entry.Name(), err)
+		}
+
+		for key, message := range catalog {
+			code, err := strconv.Atoi(key)
+			if err != nil {
+				return fmt.Errorf("invalid error code %q in message catalog %s", key, entry.Name())
+			}
+			if _, ok := messages[code]; !ok {
+				messages[code] = map[string]string{}
+			}
+			messages[code][lang] = message
+		}
+	}
+
+	return nil
+}
+
+// SupportedLanguages lists the languages of the catalog, sorted
+func SupportedLanguages() []string {
+	seen := map[string]bool{}
+	for _, translations := range messages {
+		for lang := range translations {
+			seen[lang] = true
+		}
+	}
+
+	languages := make([]string, 0, len(seen))
+	for lang := range seen {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	return languages
+}
+
+func IsSupportedLanguage(lang string) bool {
+	// Every language has the message of internal errors
+	_, ok := messages[ErrCodeInternalServerError][normalizeLanguage(lang)]
+	return ok
+}
+
+// MatchLanguage picks the supported language the client prefers the most from an Accept-Language header,
+// e.g: "vi-VN,vi;q=0.9,en;q=0.8" is "vi". It's empty when none of them is supported.
+func MatchLanguage(acceptLanguage string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{lang: normalizeLanguage(tag), quality: quality})
+	}
+
+	// Stable, so languages of the same quality keep the order of the header
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	for _, c := range candidates {
+		if IsSupportedLanguage(c.lang) {
+			return c.lang
+		}
+	}
+
+	return ""
+}
+
+// LanguageFromContext returns the language of the request, or the default language
+func LanguageFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if lang, ok := ctx.Value(string(utils.LANGUAGE_CONTEXT_KEY)).(string); ok && lang != "" {
+			return lang
+		}
+	}
+
+	return defaultLanguage
+}
+
+// WithLanguage returns a context whose errors are written in lang
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, string(utils.LANGUAGE_CONTEXT_KEY), lang)
+}
+
+// Localize returns err with its message in the language of the request, other errors are returned as is
+func Localize(ctx context.Context, err error) error {
+	e, ok := err.(*CustomError)
+	if !ok || e == nil || e.verbatim {
+		return err
+	}
+
+	localized := *e
+	localized.Message = GetLocalizedMessage(LanguageFromContext(ctx), e.Code, e.args...)
+	return &localized
+}
+
+// GetLocalizedMessage returns the message of code in lang, falling back to the default language and then English
+func GetLocalizedMessage(lang string, code int, args ...any) string {
+	translations, ok := messages[code]
+	if !ok {
+		translations = messages[ErrCodeInternalServerError]
+		args = nil
+	}
+
+	for _, l := range []string{normalizeLanguage(lang), defaultLanguage, LangEN} {
+		if msg, ok := translations[l]; ok {
+			if len(args) == 0 {
+				return msg
+			}
+			return fmt.Sprintf(msg, args...)
+		}
+	}
+
+	return messages[ErrCodeInternalServerError][LangEN]
+}
+
+// normalizeLanguage keeps the primary subtag, e.g: "en-US" is "en"
+func normalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if primary, _, found := strings.Cut(lang, "-"); found {
+		return primary
+	}
+	if primary, _, found := strings.Cut(lang, "_"); found {
+		return primary
+	}
+
+	return lang
+}
@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchLanguage(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "empty", acceptLanguage: "", want: ""},
+		{name: "region", acceptLanguage: "vi-VN", want: LangVN},
+		{name: "quality", acceptLanguage: "vi;q=0.5, en;q=0.8", want: LangEN},
+		{name: "unsupported first", acceptLanguage: "ja, en-US;q=0.7, vi;q=0.3", want: LangEN},
+		{name: "excluded", acceptLanguage: "en;q=0, vi;q=0.1", want: LangVN},
+		{name: "unsupported", acceptLanguage: "ja, *", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchLanguage(tt.acceptLanguage); got != tt.want {
+				t.Errorf("MatchLanguage(%v) = %v, want %v", tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	ctx := WithLanguage(context.Background(), LangVN)
+
+	err := Localize(ctx, Newf(ErrCodeValidatorRequired, "Name")).(*CustomError)
+	if want := "Name không được bỏ trống. Vui lòng kiểm tra lại"; err.Message != want {
+		t.Errorf("Localize() message = %v, want %v", err.Message, want)
+	}
+
+	// Messages of their own are kept as they are
+	custom := NewCustomError(ErrCodeValidatorRequired, "custom")
+	if got := Localize(ctx, custom).(*CustomError).Message; got != "custom" {
+		t.Errorf("Localize() message = %v, want custom", got)
+	}
+
+	// Without a language the default one is used
+	if got := Localize(context.Background(), New(ErrCodeUnauthorized)).(*CustomError).Message; got != messages[ErrCodeUnauthorized][LangEN] {
+		t.Errorf("Localize() message = %v, want %v", got, messages[ErrCodeUnauthorized][LangEN])
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "fr.yaml"), []byte("500: \"Erreur interne\"\n402: \"Non autorisé\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "de.json"), []byte(`{"500": "Interner Fehler"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadCatalog(dir); err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+	if !IsSupportedLanguage("fr") || !IsSupportedLanguage("de-DE") {
+		t.Errorf("LoadCatalog() languages = %v, want fr and de", SupportedLanguages())
+	}
+	if got := GetLocalizedMessage("fr", ErrCodeUnauthorized); got != "Non autorisé" {
+		t.Errorf("GetLocalizedMessage() = %v, want Non autorisé", got)
+	}
+
+	// Codes missing from a catalog fall back to the default language
+	if got := GetLocalizedMessage("de", ErrCodeUnauthorized); got != messages[ErrCodeUnauthorized][LangEN] {
+		t.Errorf("GetLocalizedMessage() = %v, want %v", got, messages[ErrCodeUnauthorized][LangEN])
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "it.json"), []byte(`{"abc": "x"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadCatalog(dir); err == nil {
+		t.Error("LoadCatalog() error = nil, want error for an invalid code")
+	}
+}
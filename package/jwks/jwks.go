@@ -0,0 +1,305 @@
+package jwks
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"sondth-test_soa/config"
+)
+
+const (
+	ALG_HS256 = "HS256"
+	ALG_RS256 = "RS256"
+	ALG_EDDSA = "EdDSA"
+)
+
+// Key is a key able to verify tokens, and to sign them when its private key is loaded
+type Key struct {
+	ID          string
+	Method      jwt.SigningMethod
+	signKey     interface{}
+	verifyKey   interface{}
+	public      bool
+	verifyUntil time.Time
+}
+
+// JSONWebKey is the RFC 7517 representation of a public key
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// KeySet holds the signing key and every key still accepted for verification
+type KeySet struct {
+	signingKey *Key
+	keys       map[string]*Key
+}
+
+// NewKeySet loads the access token keys from the JWT configuration.
+// Without configured keys, tokens are signed with HS256 and user_access_token_key.
+func NewKeySet(conf config.JWT) (*KeySet, error) {
+	if len(conf.Keys) == 0 {
+		key := &Key{
+			Method:    jwt.SigningMethodHS256,
+			signKey:   []byte(conf.UserAccessTokenKey),
+			verifyKey: []byte(conf.UserAccessTokenKey),
+		}
+		return &KeySet{signingKey: key, keys: map[string]*Key{"": key}}, nil
+	}
+
+	keySet := &KeySet{keys: make(map[string]*Key, len(conf.Keys))}
+	for _, keyConf := range conf.Keys {
+		key, err := loadKey(keyConf, time.Duration(conf.RetiredKeyGracePeriod)*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt key %s: %v", keyConf.ID, err)
+		}
+		if _, ok := keySet.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicated jwt key %s", key.ID)
+		}
+		keySet.keys[key.ID] = key
+
+		if key.ID == conf.SigningKeyID {
+			if key.signKey == nil {
+				return nil, fmt.Errorf("jwt signing key %s has no private key", key.ID)
+			}
+			if !key.verifyUntil.IsZero() {
+				return nil, fmt.Errorf("jwt signing key %s is retired", key.ID)
+			}
+			keySet.signingKey = key
+		}
+	}
+	if keySet.signingKey == nil {
+		return nil, fmt.Errorf("jwt signing key %s not found", conf.SigningKeyID)
+	}
+
+	return keySet, nil
+}
+
+// Sign signs the claims with the current signing key and sets the kid header
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signingKey.Method, claims)
+	if k.signingKey.ID != "" {
+		token.Header["kid"] = k.signingKey.ID
+	}
+
+	return token.SignedString(k.signingKey.signKey)
+}
+
+// Parse verifies the token with the key matching its kid header
+func (k *KeySet) Parse(tokenString string) (*jwt.Token, error) {
+	token, err := jwt.Parse(tokenString, k.keyFunc, jwt.WithValidMethods(k.methods()))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, jwt.ErrTokenMalformed
+	}
+
+	return token, nil
+}
+
+// Keys returns the public keys that are still accepted, for the JWKS endpoint
+func (k *KeySet) Keys() []JSONWebKey {
+	keys := make([]JSONWebKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		if !key.public || key.isExpired() {
+			continue
+		}
+		keys = append(keys, key.toJSONWebKey())
+	}
+
+	return keys
+}
+
+// PublicKey decodes the key and the signing method it verifies, e.g: keys of an external JWKS endpoint
+func (j JSONWebKey) PublicKey() (interface{}, jwt.SigningMethod, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid rsa modulus: %v", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid rsa exponent: %v", err)
+		}
+
+		method := jwt.GetSigningMethod(j.Alg)
+		if method == nil {
+			method = jwt.SigningMethodRS256
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, method, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, nil, fmt.Errorf("unsupported curve %s", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, nil, fmt.Errorf("invalid ed25519 key")
+		}
+
+		return ed25519.PublicKey(x), jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported key type %s", j.Kty)
+	}
+}
+
+// -------------------------------------------------------------------------------
+func (k *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown jwt key %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+	// Retired keys are only accepted until the grace period is over
+	if key.isExpired() {
+		return nil, fmt.Errorf("jwt key %q is retired", kid)
+	}
+
+	return key.verifyKey, nil
+}
+
+func (k *KeySet) methods() []string {
+	methods := make([]string, 0, len(k.keys))
+	for _, key := range k.keys {
+		methods = append(methods, key.Method.Alg())
+	}
+
+	return methods
+}
+
+func (k *Key) isExpired() bool {
+	return !k.verifyUntil.IsZero() && time.Now().After(k.verifyUntil)
+}
+
+func (k *Key) toJSONWebKey() JSONWebKey {
+	jwk := JSONWebKey{
+		Kid: k.ID,
+		Use: "sig",
+		Alg: k.Method.Alg(),
+	}
+
+	switch publicKey := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+
+	return jwk
+}
+
+func loadKey(conf config.JWTKey, gracePeriod time.Duration) (*Key, error) {
+	if conf.ID == "" {
+		return nil, fmt.Errorf("missing key id")
+	}
+
+	key := &Key{ID: conf.ID, public: true}
+	switch conf.Algorithm {
+	case ALG_RS256:
+		key.Method = jwt.SigningMethodRS256
+	case ALG_EDDSA:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", conf.Algorithm)
+	}
+	if conf.RetiredAt > 0 {
+		key.verifyUntil = time.Unix(conf.RetiredAt, 0).Add(gracePeriod)
+	}
+
+	// Retired keys may only keep their public key around
+	if conf.PrivateKeyPath != "" {
+		block, err := readPEM(conf.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, err
+		}
+		key.signKey = privateKey
+		key.verifyKey = privateKey.Public()
+	} else if conf.PublicKeyPath != "" {
+		block, err := readPEM(conf.PublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		key.verifyKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf("missing private_key_path or public_key_path")
+	}
+
+	switch key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		if key.Method != jwt.SigningMethodRS256 {
+			return nil, fmt.Errorf("RSA key used with %s", conf.Algorithm)
+		}
+	case ed25519.PublicKey:
+		if key.Method != jwt.SigningMethodEdDSA {
+			return nil, fmt.Errorf("Ed25519 key used with %s", conf.Algorithm)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key.verifyKey)
+	}
+
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+
+	return block, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+
+	return signer, nil
+}
@@ -0,0 +1,194 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"sondth-test_soa/config"
+)
+
+func writeKey(t *testing.T, dir string, name string, privateKey interface{}) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return path
+}
+
+func TestKeySet(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	rsaPath := writeKey(t, dir, "rsa.pem", rsaKey)
+	edPath := writeKey(t, dir, "ed.pem", edKey)
+
+	claims := jwt.MapClaims{"sub": "user"}
+
+	tests := []struct {
+		name       string
+		conf       config.JWT
+		token      func(t *testing.T) string
+		wantErr    bool
+		wantKeyIDs []string
+	}{
+		{
+			name: "HS256 Without Keys",
+			conf: config.JWT{UserAccessTokenKey: "secret"},
+			token: func(t *testing.T) string {
+				token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+				return token
+			},
+			wantErr:    false,
+			wantKeyIDs: []string{},
+		},
+		{
+			name: "Token Signed By Rotated Key Within Grace Period",
+			conf: config.JWT{
+				SigningKeyID:          "new",
+				RetiredKeyGracePeriod: 3600,
+				Keys: []config.JWTKey{
+					{ID: "new", Algorithm: ALG_EDDSA, PrivateKeyPath: edPath},
+					{ID: "old", Algorithm: ALG_RS256, PrivateKeyPath: rsaPath, RetiredAt: time.Now().Unix()},
+				},
+			},
+			token: func(t *testing.T) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+				token.Header["kid"] = "old"
+				signed, _ := token.SignedString(rsaKey)
+				return signed
+			},
+			wantErr:    false,
+			wantKeyIDs: []string{"new", "old"},
+		},
+		{
+			name: "Token Signed By Rotated Key After Grace Period",
+			conf: config.JWT{
+				SigningKeyID:          "new",
+				RetiredKeyGracePeriod: 3600,
+				Keys: []config.JWTKey{
+					{ID: "new", Algorithm: ALG_EDDSA, PrivateKeyPath: edPath},
+					{ID: "old", Algorithm: ALG_RS256, PrivateKeyPath: rsaPath, RetiredAt: time.Now().Add(-2 * time.Hour).Unix()},
+				},
+			},
+			token: func(t *testing.T) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+				token.Header["kid"] = "old"
+				signed, _ := token.SignedString(rsaKey)
+				return signed
+			},
+			wantErr:    true,
+			wantKeyIDs: []string{"new"},
+		},
+		{
+			name: "Token With Mismatched Algorithm",
+			conf: config.JWT{
+				SigningKeyID: "rsa",
+				Keys: []config.JWTKey{
+					{ID: "rsa", Algorithm: ALG_RS256, PrivateKeyPath: rsaPath},
+				},
+			},
+			token: func(t *testing.T) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+				token.Header["kid"] = "rsa"
+				signed, _ := token.SignedString([]byte("secret"))
+				return signed
+			},
+			wantErr:    true,
+			wantKeyIDs: []string{"rsa"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keySet, err := NewKeySet(tt.conf)
+			if err != nil {
+				t.Fatalf("NewKeySet() error = %v", err)
+			}
+
+			// Tokens signed by the key set always verify
+			signed, err := keySet.Sign(claims)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if _, err := keySet.Parse(signed); err != nil {
+				t.Errorf("Parse() of own token error = %v", err)
+			}
+
+			_, err = keySet.Parse(tt.token(t))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			keys := keySet.Keys()
+			if len(keys) != len(tt.wantKeyIDs) {
+				t.Fatalf("Keys() = %v, want ids %v", keys, tt.wantKeyIDs)
+			}
+			for _, id := range tt.wantKeyIDs {
+				found := false
+				for _, key := range keys {
+					found = found || key.Kid == id
+				}
+				if !found {
+					t.Errorf("Keys() = %v, missing id %s", keys, id)
+				}
+			}
+		})
+	}
+}
+
+func TestNewKeySet_InvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaPath := writeKey(t, dir, "rsa.pem", rsaKey)
+
+	tests := []struct {
+		name string
+		conf config.JWT
+	}{
+		{
+			name: "Signing Key Not Found",
+			conf: config.JWT{
+				SigningKeyID: "missing",
+				Keys:         []config.JWTKey{{ID: "rsa", Algorithm: ALG_RS256, PrivateKeyPath: rsaPath}},
+			},
+		},
+		{
+			name: "Algorithm Mismatch",
+			conf: config.JWT{
+				SigningKeyID: "rsa",
+				Keys:         []config.JWTKey{{ID: "rsa", Algorithm: ALG_EDDSA, PrivateKeyPath: rsaPath}},
+			},
+		},
+		{
+			name: "Retired Signing Key",
+			conf: config.JWT{
+				SigningKeyID: "rsa",
+				Keys:         []config.JWTKey{{ID: "rsa", Algorithm: ALG_RS256, PrivateKeyPath: rsaPath, RetiredAt: time.Now().Unix()}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKeySet(tt.conf); err == nil {
+				t.Error("NewKeySet() error = nil, want error")
+			}
+		})
+	}
+}
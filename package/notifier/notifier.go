@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"sondth-test_soa/config"
+	logger "sondth-test_soa/package/log"
+)
+
+const (
+	DRIVER_LOG  = "log"
+	DRIVER_FILE = "file"
+)
+
+const (
+	CHANNEL_EMAIL = "email"
+	CHANNEL_SMS   = "sms"
+)
+
+// Message is a notification delivered to a single recipient
+type Message struct {
+	Channel string `json:"channel,omitempty"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// INotifier defines the interface for delivering notifications to users
+type INotifier interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewNotifier creates the notifier configured by notifier.driver
+func NewNotifier(conf config.Configuration) (INotifier, error) {
+	switch conf.Notifier.Driver {
+	case DRIVER_FILE:
+		return NewFileNotifier(conf.Notifier.FilePath)
+	case DRIVER_LOG, "":
+		return NewLogNotifier(), nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier driver: %s", conf.Notifier.Driver)
+	}
+}
+
+// logNotifier writes notifications to the application log, for local development
+type logNotifier struct{}
+
+// NewLogNotifier creates a notifier writing to the application log
+func NewLogNotifier() INotifier {
+	return &logNotifier{}
+}
+
+// Send implements INotifier
+func (n *logNotifier) Send(ctx context.Context, message Message) error {
+	logger.WithCtx(ctx).Info(
+		"Notification",
+		slog.String("channel", message.Channel),
+		slog.String("to", message.To),
+		slog.String("subject", message.Subject),
+		slog.String("body", message.Body),
+	)
+	return nil
+}
+
+// fileNotifier appends notifications as JSON lines to a file, for local development
+type fileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileNotifier creates a notifier appending to the given file
+func NewFileNotifier(path string) (INotifier, error) {
+	if path == "" {
+		return nil, fmt.Errorf("notifier file path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating notifier directory: %v", err)
+	}
+
+	return &fileNotifier{path: path}, nil
+}
+
+// Send implements INotifier
+func (n *fileNotifier) Send(ctx context.Context, message Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt int64 `json:"sent_at"`
+	}{message, time.Now().Unix()})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
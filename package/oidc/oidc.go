@@ -0,0 +1,303 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"sondth-test_soa/config"
+	"sondth-test_soa/package/jwks"
+)
+
+const (
+	CODE_CHALLENGE_METHOD = "S256"
+	DISCOVERY_PATH        = "/.well-known/openid-configuration"
+
+	// Tokens with an unknown kid refetch the keys at most once per interval
+	KEYS_REFRESH_INTERVAL = time.Minute
+)
+
+var DEFAULT_SCOPES = []string{"openid", "email", "profile"}
+
+// Claims are the ID token claims used to identify and create users
+type Claims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Nonce         string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// Token is the token endpoint response of the authorization code exchange
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// Provider is an OpenID Connect provider, its discovery document and keys are fetched lazily
+type Provider struct {
+	conf       config.OIDCProvider
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]jwks.JSONWebKey
+	keysFetchedAt time.Time
+}
+
+func NewProvider(conf config.OIDCProvider) *Provider {
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = DEFAULT_SCOPES
+	}
+
+	return &Provider{
+		conf:       conf,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the configured provider name
+func (p *Provider) Name() string {
+	return p.conf.Name
+}
+
+// AuthCodeURL returns the URL users are redirected to, to sign in at the provider
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.conf.ClientID},
+		"redirect_uri":          {p.conf.RedirectURL},
+		"scope":                 {strings.Join(p.conf.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {CODE_CHALLENGE_METHOD},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange trades the authorization code and its PKCE verifier for tokens
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string) (*Token, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.conf.RedirectURL},
+		"client_id":     {p.conf.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	if p.conf.ClientSecret != "" {
+		form.Set("client_secret", p.conf.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token Token
+	if err := p.do(req, &token); err != nil {
+		return nil, fmt.Errorf("exchange code: %v", err)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("exchange code: missing id_token")
+	}
+
+	return &token, nil
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of an ID token
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken string, nonce string) (*Claims, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	_, err = jwt.ParseWithClaims(
+		rawIDToken,
+		&claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			key, method, err := p.getKey(ctx, kid)
+			if err != nil {
+				return nil, err
+			}
+			if token.Method.Alg() != method.Alg() {
+				return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+			}
+			return key, nil
+		},
+		jwt.WithValidMethods([]string{jwks.ALG_RS256, "RS384", "RS512", jwks.ALG_EDDSA}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.conf.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("id token has no subject")
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("id token nonce mismatch")
+	}
+
+	return &claims, nil
+}
+
+// GenerateCodeVerifier returns a random PKCE code verifier
+func GenerateCodeVerifier() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// CodeChallengeS256 returns the S256 PKCE code challenge of a verifier
+func CodeChallengeS256(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// -------------------------------------------------------------------------------
+func (p *Provider) getDiscovery(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.conf.IssuerURL, "/")+DISCOVERY_PATH, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var discovery discoveryDocument
+	if err := p.do(req, &discovery); err != nil {
+		return nil, fmt.Errorf("fetch discovery document: %v", err)
+	}
+	// The issuer of the document has to be the configured one, see OpenID Connect Discovery 4.3
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.conf.IssuerURL, "/") {
+		return nil, fmt.Errorf("issuer mismatch: %s", discovery.Issuer)
+	}
+	p.discovery = &discovery
+
+	return p.discovery, nil
+}
+
+// getKey returns the key of kid, the keys are fetched again once for unknown kids after a rotation
+func (p *Provider) getKey(ctx context.Context, kid string) (interface{}, jwt.SigningMethod, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	canRefresh := time.Since(p.keysFetchedAt) >= KEYS_REFRESH_INTERVAL
+	p.mu.Unlock()
+	if !ok {
+		if !canRefresh {
+			return nil, nil, fmt.Errorf("unknown id token key %q", kid)
+		}
+		if err := p.refreshKeys(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		p.mu.Lock()
+		key, ok = p.keys[kid]
+		p.mu.Unlock()
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown id token key %q", kid)
+		}
+	}
+
+	return key.PublicKey()
+}
+
+func (p *Provider) refreshKeys(ctx context.Context) error {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.JwksURI, nil)
+	if err != nil {
+		return err
+	}
+
+	var keySet struct {
+		Keys []jwks.JSONWebKey `json:"keys"`
+	}
+	if err := p.do(req, &keySet); err != nil {
+		return fmt.Errorf("fetch jwks: %v", err)
+	}
+
+	keys := make(map[string]jwks.JSONWebKey, len(keySet.Keys))
+	for _, key := range keySet.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		keys[key.Kid] = key
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *Provider) do(req *http.Request, result interface{}) error {
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, string(body))
+	}
+
+	return json.Unmarshal(body, result)
+}
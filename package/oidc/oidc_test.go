@@ -0,0 +1,166 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"sondth-test_soa/config"
+	"sondth-test_soa/package/oidc"
+	"sondth-test_soa/package/oidc/oidctest"
+)
+
+const (
+	testClientID    = "test-client"
+	testRedirectURL = "http://localhost:8080/oidc/callback"
+)
+
+// authorize follows the provider login and returns the code sent back to the redirect URL
+func authorize(t *testing.T, authURL string) (string, string) {
+	t.Helper()
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize error = %v", err)
+	}
+	defer res.Body.Close()
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	if err != nil || res.StatusCode != http.StatusFound {
+		t.Fatalf("authorize status = %d, location = %v", res.StatusCode, res.Header.Get("Location"))
+	}
+
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func newTestProvider(t *testing.T) (*oidc.Provider, *oidctest.Server) {
+	t.Helper()
+
+	server, err := oidctest.NewServer(testClientID, oidctest.User{
+		Subject:       "user-1",
+		Email:         "user@example.com",
+		EmailVerified: true,
+		Name:          "Test User",
+	})
+	if err != nil {
+		t.Fatalf("oidctest.NewServer() error = %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	return oidc.NewProvider(config.OIDCProvider{
+		Name:        "test",
+		IssuerURL:   server.Issuer(),
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+	}), server
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	provider, _ := newTestProvider(t)
+	ctx := context.Background()
+
+	codeVerifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.CodeChallengeS256(codeVerifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+
+	code, state := authorize(t, authURL)
+	if state != "state-1" {
+		t.Errorf("state = %v, want state-1", state)
+	}
+
+	token, err := provider.Exchange(ctx, code, codeVerifier)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken() error = %v", err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "user@example.com" || !claims.EmailVerified {
+		t.Errorf("VerifyIDToken() claims = %+v", claims)
+	}
+
+	// Codes can only be exchanged once
+	if _, err := provider.Exchange(ctx, code, codeVerifier); err == nil {
+		t.Error("Exchange() reused code, want error")
+	}
+}
+
+func TestProvider_Exchange_WrongCodeVerifier(t *testing.T) {
+	provider, _ := newTestProvider(t)
+	ctx := context.Background()
+
+	codeVerifier, _ := oidc.GenerateCodeVerifier()
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.CodeChallengeS256(codeVerifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	code, _ := authorize(t, authURL)
+
+	otherVerifier, _ := oidc.GenerateCodeVerifier()
+	if _, err := provider.Exchange(ctx, code, otherVerifier); err == nil {
+		t.Error("Exchange() with another code verifier, want error")
+	}
+}
+
+func TestProvider_VerifyIDToken_NonceMismatch(t *testing.T) {
+	provider, _ := newTestProvider(t)
+	ctx := context.Background()
+
+	codeVerifier, _ := oidc.GenerateCodeVerifier()
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.CodeChallengeS256(codeVerifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	code, _ := authorize(t, authURL)
+
+	token, err := provider.Exchange(ctx, code, codeVerifier)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, token.IDToken, "another-nonce"); err == nil {
+		t.Error("VerifyIDToken() with another nonce, want error")
+	}
+}
+
+func TestProvider_VerifyIDToken_WrongAudience(t *testing.T) {
+	_, server := newTestProvider(t)
+	ctx := context.Background()
+
+	// A provider of another client must not accept tokens issued to testClientID
+	other := oidc.NewProvider(config.OIDCProvider{
+		Name:        "other",
+		IssuerURL:   server.Issuer(),
+		ClientID:    "other-client",
+		RedirectURL: testRedirectURL,
+	})
+	provider := oidc.NewProvider(config.OIDCProvider{
+		Name:        "test",
+		IssuerURL:   server.Issuer(),
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+	})
+
+	codeVerifier, _ := oidc.GenerateCodeVerifier()
+	authURL, _ := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.CodeChallengeS256(codeVerifier))
+	code, _ := authorize(t, authURL)
+	token, err := provider.Exchange(ctx, code, codeVerifier)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	if _, err := other.VerifyIDToken(ctx, token.IDToken, "nonce-1"); err == nil {
+		t.Error("VerifyIDToken() for another audience, want error")
+	}
+}
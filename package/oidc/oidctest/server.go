@@ -0,0 +1,197 @@
+// Package oidctest is a local OpenID Connect provider for tests and development.
+// It signs in a fixed user without a login page: /authorize redirects back with a code right away.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"sondth-test_soa/package/jwks"
+	"sondth-test_soa/package/oidc"
+)
+
+const KEY_ID = "oidctest"
+
+// User is the account signed in by the server
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type authRequest struct {
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// Server is a mock OpenID Connect provider serving discovery, authorize, token and jwks endpoints
+type Server struct {
+	*httptest.Server
+	ClientID string
+	User     User
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]authRequest
+}
+
+// NewServer starts a provider for clientID, close it with Close
+func NewServer(clientID string, user User) (*Server, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		ClientID: clientID,
+		User:     user,
+		key:      key,
+		codes:    make(map[string]authRequest),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidc.DISCOVERY_PATH, s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+	s.Server = httptest.NewServer(mux)
+
+	return s, nil
+}
+
+// Issuer returns the issuer URL to configure the provider with
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != s.ClientID || query.Get("response_type") != "code" {
+		writeError(w, "unauthorized_client")
+		return
+	}
+	if query.Get("code_challenge") == "" || query.Get("code_challenge_method") != oidc.CODE_CHALLENGE_METHOD {
+		writeError(w, "invalid_request")
+		return
+	}
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.String() == "" {
+		writeError(w, "invalid_request")
+		return
+	}
+
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = authRequest{
+		redirectURI:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	s.mu.Unlock()
+
+	values := redirectURI.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirectURI.RawQuery = values.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeError(w, "invalid_request")
+		return
+	}
+
+	// Codes are single use
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	request, ok := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+	if !ok || r.PostForm.Get("client_id") != s.ClientID || r.PostForm.Get("redirect_uri") != request.redirectURI {
+		writeError(w, "invalid_grant")
+		return
+	}
+	if oidc.CodeChallengeS256(r.PostForm.Get("code_verifier")) != request.codeChallenge {
+		writeError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, oidc.Claims{
+		Email:         s.User.Email,
+		EmailVerified: s.User.EmailVerified,
+		Name:          s.User.Name,
+		Nonce:         request.nonce,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.URL,
+			Subject:   s.User.Subject,
+			Audience:  jwt.ClaimStrings{s.ClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		},
+	})
+	idToken.Header["kid"] = KEY_ID
+	signed, err := idToken.SignedString(s.key)
+	if err != nil {
+		writeError(w, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, oidc.Token{
+		AccessToken: randomString(),
+		TokenType:   "Bearer",
+		IDToken:     signed,
+		ExpiresIn:   300,
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]jwks.JSONWebKey{
+		"keys": {{
+			Kty: "RSA",
+			Kid: KEY_ID,
+			Use: "sig",
+			Alg: jwks.ALG_RS256,
+			N:   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func randomString() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
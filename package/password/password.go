@@ -0,0 +1,219 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"sondth-test_soa/config"
+)
+
+const (
+	ALG_BCRYPT   = "bcrypt"
+	ALG_ARGON2ID = "argon2id"
+)
+
+const (
+	DEFAULT_BCRYPT_COST        = 14
+	DEFAULT_ARGON2_MEMORY      = 64 * 1024 // KiB
+	DEFAULT_ARGON2_ITERATIONS  = 3
+	DEFAULT_ARGON2_PARALLELISM = 2
+	DEFAULT_ARGON2_SALT_LENGTH = 16
+	DEFAULT_ARGON2_KEY_LENGTH  = 32
+)
+
+var (
+	ErrMismatchedPassword = errors.New("password does not match the hash")
+	ErrUnsupportedHash    = errors.New("unsupported password hash")
+)
+
+// Params are the algorithm and its parameters used for new password hashes
+type Params struct {
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// Argon2Params are the argon2id parameters, memory is in KiB
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultParams returns bcrypt with the cost used before hashing was configurable
+func DefaultParams() Params {
+	return Params{
+		Algorithm:  ALG_BCRYPT,
+		BcryptCost: DEFAULT_BCRYPT_COST,
+		Argon2: Argon2Params{
+			Memory:      DEFAULT_ARGON2_MEMORY,
+			Iterations:  DEFAULT_ARGON2_ITERATIONS,
+			Parallelism: DEFAULT_ARGON2_PARALLELISM,
+			SaltLength:  DEFAULT_ARGON2_SALT_LENGTH,
+			KeyLength:   DEFAULT_ARGON2_KEY_LENGTH,
+		},
+	}
+}
+
+// NewParams validates the password hashing config, unset values fall back to the defaults
+func NewParams(conf config.PasswordHash) (Params, error) {
+	params := DefaultParams()
+	if conf.Algorithm != "" {
+		params.Algorithm = conf.Algorithm
+	}
+	if conf.BcryptCost != 0 {
+		params.BcryptCost = conf.BcryptCost
+	}
+	if conf.Argon2.Memory != 0 {
+		params.Argon2.Memory = conf.Argon2.Memory
+	}
+	if conf.Argon2.Iterations != 0 {
+		params.Argon2.Iterations = conf.Argon2.Iterations
+	}
+	if conf.Argon2.Parallelism != 0 {
+		params.Argon2.Parallelism = conf.Argon2.Parallelism
+	}
+	if conf.Argon2.SaltLength != 0 {
+		params.Argon2.SaltLength = conf.Argon2.SaltLength
+	}
+	if conf.Argon2.KeyLength != 0 {
+		params.Argon2.KeyLength = conf.Argon2.KeyLength
+	}
+
+	switch params.Algorithm {
+	case ALG_BCRYPT:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return params, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case ALG_ARGON2ID:
+		if params.Argon2.SaltLength < 8 || params.Argon2.KeyLength < 16 {
+			return params, fmt.Errorf("argon2id salt length must be at least 8 and key length at least 16")
+		}
+	default:
+		return params, fmt.Errorf("unsupported password hashing algorithm: %s", params.Algorithm)
+	}
+
+	return params, nil
+}
+
+// Hash hashes password with params. The algorithm and its parameters are stored in the hash:
+// bcrypt uses its own "$2a$<cost>$..." format, argon2id the PHC string format
+// "$argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>"
+func Hash(password string, params Params) (string, error) {
+	switch params.Algorithm {
+	case ALG_ARGON2ID:
+		return hashArgon2id(password, params.Argon2)
+	default:
+		bytes, err := bcrypt.GenerateFromPassword([]byte(password), params.BcryptCost)
+		return string(bytes), err
+	}
+}
+
+// Verify checks password against a hash created by Hash with any params
+func Verify(password, hash string) error {
+	if strings.HasPrefix(hash, "$"+ALG_ARGON2ID+"$") {
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return err
+		}
+
+		other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+		if subtle.ConstantTimeCompare(key, other) != 1 {
+			return ErrMismatchedPassword
+		}
+		return nil
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatchedPassword
+		}
+		return err
+	}
+	return nil
+}
+
+// NeedsRehash reports whether hash was created with another algorithm or parameters than params
+func NeedsRehash(hash string, params Params) bool {
+	if strings.HasPrefix(hash, "$"+ALG_ARGON2ID+"$") {
+		if params.Algorithm != ALG_ARGON2ID {
+			return true
+		}
+
+		current, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return true
+		}
+		return current.Memory != params.Argon2.Memory ||
+			current.Iterations != params.Argon2.Iterations ||
+			current.Parallelism != params.Argon2.Parallelism ||
+			uint32(len(salt)) != params.Argon2.SaltLength ||
+			uint32(len(key)) != params.Argon2.KeyLength
+	}
+
+	if params.Algorithm != ALG_BCRYPT {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != params.BcryptCost
+}
+
+// --------------------------------------
+func hashArgon2id(password string, params Argon2Params) (string, error) {
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return fmt.Sprintf(
+		"$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		ALG_ARGON2ID,
+		argon2.Version,
+		params.Memory,
+		params.Iterations,
+		params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func decodeArgon2id(hash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != ALG_ARGON2ID {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,103 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"sondth-test_soa/config"
+)
+
+func testParams(algorithm string) Params {
+	params := DefaultParams()
+	params.Algorithm = algorithm
+	params.BcryptCost = bcrypt.MinCost
+	params.Argon2.Memory = 1024
+	params.Argon2.Iterations = 1
+	return params
+}
+
+func TestHashAndVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		prefix string
+	}{
+		{name: "bcrypt", params: testParams(ALG_BCRYPT), prefix: "$2a$04$"},
+		{name: "argon2id", params: testParams(ALG_ARGON2ID), prefix: "$argon2id$v=19$m=1024,t=1,p=2$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := Hash("secret", tt.params)
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+			if !strings.HasPrefix(hash, tt.prefix) {
+				t.Errorf("Hash() = %v, want prefix %v", hash, tt.prefix)
+			}
+			if err := Verify("secret", hash); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+			if err := Verify("wrong", hash); err != ErrMismatchedPassword {
+				t.Errorf("Verify() error = %v, want %v", err, ErrMismatchedPassword)
+			}
+			if NeedsRehash(hash, tt.params) {
+				t.Error("NeedsRehash() = true with the same params")
+			}
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	bcryptHash, _ := Hash("secret", testParams(ALG_BCRYPT))
+	argon2Hash, _ := Hash("secret", testParams(ALG_ARGON2ID))
+
+	higherCost := testParams(ALG_BCRYPT)
+	higherCost.BcryptCost++
+	moreMemory := testParams(ALG_ARGON2ID)
+	moreMemory.Argon2.Memory *= 2
+
+	tests := []struct {
+		name   string
+		hash   string
+		params Params
+		want   bool
+	}{
+		{name: "bcrypt cost changed", hash: bcryptHash, params: higherCost, want: true},
+		{name: "bcrypt to argon2id", hash: bcryptHash, params: testParams(ALG_ARGON2ID), want: true},
+		{name: "argon2id memory changed", hash: argon2Hash, params: moreMemory, want: true},
+		{name: "argon2id to bcrypt", hash: argon2Hash, params: testParams(ALG_BCRYPT), want: true},
+		{name: "argon2id unchanged", hash: argon2Hash, params: testParams(ALG_ARGON2ID), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsRehash(tt.hash, tt.params); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    config.PasswordHash
+		wantErr bool
+	}{
+		{name: "defaults", conf: config.PasswordHash{}, wantErr: false},
+		{name: "argon2id", conf: config.PasswordHash{Algorithm: ALG_ARGON2ID}, wantErr: false},
+		{name: "bcrypt cost too high", conf: config.PasswordHash{BcryptCost: 40}, wantErr: true},
+		{name: "unsupported algorithm", conf: config.PasswordHash{Algorithm: "md5"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewParams(tt.conf); (err != nil) != tt.wantErr {
+				t.Errorf("NewParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -11,13 +11,18 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// Nil is returned by Get when the key does not exist
+const Nil = redis.Nil
+
 // IRedisClient defines the interface for Redis operations
 type IRedisClient interface {
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
+	GetDel(ctx context.Context, key string) (string, error)
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
 	Incr(ctx context.Context, key string) (int64, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
 	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
 }
 
@@ -56,6 +61,11 @@ func (r *RedisClient) Get(ctx context.Context, key string) (string, error) {
 	return r.client.Get(ctx, key).Result()
 }
 
+// GetDel implements IRedisClient
+func (r *RedisClient) GetDel(ctx context.Context, key string) (string, error) {
+	return r.client.GetDel(ctx, key).Result()
+}
+
 // Delete implements IRedisClient
 func (r *RedisClient) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
@@ -72,6 +82,11 @@ func (r *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
 	return r.client.Incr(ctx, key).Result()
 }
 
+// Expire implements IRedisClient
+func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return r.client.Expire(ctx, key, expiration).Err()
+}
+
 // SetNX implements IRedisClient
 func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
 	return r.client.SetNX(ctx, key, value, expiration).Result()
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sondth-test_soa/config"
+)
+
+const (
+	DRIVER_LOCAL = "local"
+)
+
+// IStorage stores uploaded files under a key, e.g: avatars/<user id>/<name>.png
+type IStorage interface {
+	Put(ctx context.Context, key string, content io.Reader, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// NewStorage creates the storage configured by storage.driver
+func NewStorage(conf config.Configuration) (IStorage, error) {
+	switch conf.Storage.Driver {
+	case DRIVER_LOCAL, "":
+		return NewLocalStorage(conf.Storage.LocalDir, conf.Storage.BaseURL)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", conf.Storage.Driver)
+	}
+}
+
+// localStorage keeps files on the local disk, they are served by the HTTP server under baseURL
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage creates a storage writing to dir
+func NewLocalStorage(dir string, baseURL string) (IStorage, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("storage directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating storage directory: %v", err)
+	}
+
+	return &localStorage{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+// Put implements IStorage
+func (s *localStorage) Put(ctx context.Context, key string, content io.Reader, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a failed upload never leaves half a file behind
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Delete implements IStorage, a missing file is not an error
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// URL implements IStorage
+func (s *localStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// path resolves key inside the storage directory, keys can't escape it
+func (s *localStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+
+	return filepath.Join(s.dir, cleaned), nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStorage(dir, "/uploads/")
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "avatars/user/a.png", strings.NewReader("image"), "image/png"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "avatars", "user", "a.png"))
+	if err != nil || string(content) != "image" {
+		t.Fatalf("Put() wrote %q, %v", content, err)
+	}
+	if got := s.URL("avatars/user/a.png"); got != "/uploads/avatars/user/a.png" {
+		t.Errorf("URL() = %v, want /uploads/avatars/user/a.png", got)
+	}
+
+	if err := s.Delete(ctx, "avatars/user/a.png"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "avatars", "user", "a.png")); !os.IsNotExist(err) {
+		t.Errorf("Delete() left the file, stat error = %v", err)
+	}
+	if err := s.Delete(ctx, "avatars/user/a.png"); err != nil {
+		t.Errorf("Delete() of a missing file error = %v", err)
+	}
+}
+
+func TestLocalStorage_KeyCantEscapeDir(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "uploads")
+	s, err := NewLocalStorage(dir, "/uploads")
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	if err := s.Put(context.Background(), "../escaped.txt", strings.NewReader("x"), "text/plain"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "escaped.txt")); !os.IsNotExist(err) {
+		t.Errorf("Put() wrote outside the storage directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped.txt")); err != nil {
+		t.Errorf("Put() didn't write inside the storage directory: %v", err)
+	}
+}
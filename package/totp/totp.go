@@ -0,0 +1,116 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	PERIOD      = 30 // seconds
+	DIGITS      = 6
+	SKEW        = 1 // accepted steps before and after the current one
+	SECRET_SIZE = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random base32 encoded secret
+func GenerateSecret() (string, error) {
+	secret := make([]byte, SECRET_SIZE)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI returns the otpauth:// URI rendered as a QR code by authenticator apps
+func ProvisioningURI(issuer string, account string, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(DIGITS))
+	params.Set("period", fmt.Sprint(PERIOD))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+// GenerateCode returns the code of the time step containing t
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return "", err
+	}
+
+	return hotp(key, uint64(t.Unix()/PERIOD)), nil
+}
+
+// Validate reports whether code is valid for secret at time t, allowing for clock skew
+func Validate(secret string, code string, t time.Time) bool {
+	_, ok := ValidateStep(secret, code, t)
+	return ok
+}
+
+// ValidateStep is Validate that also returns the time step the code belongs to, so a used code can be refused
+func ValidateStep(secret string, code string, t time.Time) (int64, bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil || len(code) != DIGITS {
+		return 0, false
+	}
+
+	counter := t.Unix() / PERIOD
+	for i := -SKEW; i <= SKEW; i++ {
+		step := counter + int64(i)
+		expected := hotp(key, uint64(step))
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// GenerateRecoveryCodes returns n random single-use recovery codes (e.g: "a1b2c-3d4e5")
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		bytes := make([]byte, 5)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, err
+		}
+		code := fmt.Sprintf("%x", bytes)
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+
+	return codes, nil
+}
+
+// --------------------------------------
+// hotp implements RFC 4226 with SHA1 and DIGITS digits
+func hotp(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < DIGITS; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", DIGITS, value%mod)
+}
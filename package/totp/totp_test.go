@@ -0,0 +1,92 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// RFC 6238 test vectors for SHA1, truncated to 6 digits
+func TestGenerateCode(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		name string
+		time int64
+		want string
+	}{
+		{name: "59", time: 59, want: "287082"},
+		{name: "1111111109", time: 1111111109, want: "081804"},
+		{name: "1111111111", time: 1111111111, want: "050471"},
+		{name: "1234567890", time: 1234567890, want: "005924"},
+		{name: "2000000000", time: 2000000000, want: "279037"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateCode(secret, time.Unix(tt.time, 0))
+			if err != nil {
+				t.Errorf("GenerateCode() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GenerateCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	now := time.Now()
+	code, _ := GenerateCode(secret, now)
+	previousCode, _ := GenerateCode(secret, now.Add(-PERIOD*time.Second))
+	oldCode, _ := GenerateCode(secret, now.Add(-5*PERIOD*time.Second))
+
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{name: "Current Code", code: code, want: true},
+		{name: "Previous Step Code", code: previousCode, want: true},
+		{name: "Expired Code", code: oldCode, want: oldCode == code || oldCode == previousCode},
+		{name: "Malformed Code", code: "12ab", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Validate(secret, tt.code, now); got != tt.want {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStep(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	now := time.Now()
+	step := now.Unix() / PERIOD
+	nextCode, _ := GenerateCode(secret, now.Add(PERIOD*time.Second))
+
+	got, ok := ValidateStep(secret, nextCode, now)
+	if !ok {
+		t.Fatalf("ValidateStep() ok = false, want true")
+	}
+	// A code of an earlier step may collide with the next one
+	if got != step+1 {
+		if code, _ := GenerateCode(secret, time.Unix(got*PERIOD, 0)); code != nextCode {
+			t.Errorf("ValidateStep() step = %v, want %v", got, step+1)
+		}
+	}
+
+	if _, ok := ValidateStep(secret, "12ab", now); ok {
+		t.Errorf("ValidateStep() ok = true, want false for a malformed code")
+	}
+}
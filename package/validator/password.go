@@ -0,0 +1,162 @@
+package validator
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+
+	"sondth-test_soa/config"
+)
+
+const (
+	PASSWORD_RULE_MIN_LENGTH = "min_length"
+	PASSWORD_RULE_MAX_LENGTH = "max_length"
+	PASSWORD_RULE_UPPERCASE  = "uppercase"
+	PASSWORD_RULE_LOWERCASE  = "lowercase"
+	PASSWORD_RULE_DIGIT      = "digit"
+	PASSWORD_RULE_SYMBOL     = "symbol"
+	PASSWORD_RULE_BREACHED   = "breached"
+)
+
+// PasswordPolicy are the rules checked by the password tag
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int // in bytes, 0 for no limit
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+
+	// Uppercase hex SHA-1 hashes of known breached passwords
+	breachedHashes map[string]struct{}
+}
+
+// passwordPolicy is the policy of the password tag, configured once on startup
+var passwordPolicy = &PasswordPolicy{}
+
+// NewPasswordPolicy creates the policy from config and loads the breached password hashes file if set
+func NewPasswordPolicy(conf config.PasswordPolicy) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{
+		MinLength:        conf.MinLength,
+		MaxLength:        conf.MaxLength,
+		RequireUppercase: conf.RequireUppercase,
+		RequireLowercase: conf.RequireLowercase,
+		RequireDigit:     conf.RequireDigit,
+		RequireSymbol:    conf.RequireSymbol,
+	}
+
+	if conf.BreachedHashesPath != "" {
+		hashes, err := loadBreachedHashes(conf.BreachedHashesPath)
+		if err != nil {
+			return nil, err
+		}
+		policy.breachedHashes = hashes
+	}
+
+	return policy, nil
+}
+
+// SetPasswordPolicy sets the policy checked by the password tag
+func SetPasswordPolicy(policy *PasswordPolicy) {
+	passwordPolicy = policy
+}
+
+// GetPasswordPolicy returns the policy checked by the password tag
+func GetPasswordPolicy() *PasswordPolicy {
+	return passwordPolicy
+}
+
+// Check returns the first rule password violates, or an empty string when it satisfies the policy
+func (p *PasswordPolicy) Check(password string) string {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return PASSWORD_RULE_MIN_LENGTH
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return PASSWORD_RULE_MAX_LENGTH
+	}
+
+	var hasUppercase, hasLowercase, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUppercase = true
+		case unicode.IsLower(r):
+			hasLowercase = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	switch {
+	case p.RequireUppercase && !hasUppercase:
+		return PASSWORD_RULE_UPPERCASE
+	case p.RequireLowercase && !hasLowercase:
+		return PASSWORD_RULE_LOWERCASE
+	case p.RequireDigit && !hasDigit:
+		return PASSWORD_RULE_DIGIT
+	case p.RequireSymbol && !hasSymbol:
+		return PASSWORD_RULE_SYMBOL
+	}
+
+	if p.IsBreached(password) {
+		return PASSWORD_RULE_BREACHED
+	}
+
+	return ""
+}
+
+// IsBreached reports whether password is in the breached password hashes file
+func (p *PasswordPolicy) IsBreached(password string) bool {
+	if len(p.breachedHashes) == 0 {
+		return false
+	}
+
+	sum := sha1.Sum([]byte(password))
+	_, ok := p.breachedHashes[strings.ToUpper(hex.EncodeToString(sum[:]))]
+	return ok
+}
+
+var validatePassword validator.Func = func(fl validator.FieldLevel) bool {
+	password, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	return passwordPolicy.Check(password) == ""
+}
+
+// --------------------------------------
+// loadBreachedHashes reads SHA-1 hashes, one per line, the "HASH:COUNT" format of downloaded breach lists is accepted
+func loadBreachedHashes(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening breached password hashes file: %v", err)
+	}
+	defer f.Close()
+
+	hashes := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if hash, _, found := strings.Cut(line, ":"); found {
+			line = hash
+		}
+		if len(line) != sha1.Size*2 {
+			continue
+		}
+		hashes[strings.ToUpper(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading breached password hashes file: %v", err)
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sondth-test_soa/config"
+)
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	// SHA-1 of "Password1!", in the "HASH:COUNT" format
+	breachedFile := filepath.Join(t.TempDir(), "breached.txt")
+	content := "0000000000000000000000000000000000000000:1\n32ca9fc1a0f5b6330e3f4c8c1bbecde9bedb9573:42\n"
+	if err := os.WriteFile(breachedFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := NewPasswordPolicy(config.PasswordPolicy{
+		MinLength:          8,
+		MaxLength:          72,
+		RequireUppercase:   true,
+		RequireLowercase:   true,
+		RequireDigit:       true,
+		RequireSymbol:      true,
+		BreachedHashesPath: breachedFile,
+	})
+	if err != nil {
+		t.Fatalf("NewPasswordPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		want     string
+	}{
+		{name: "too short", password: "Ab1!", want: PASSWORD_RULE_MIN_LENGTH},
+		{name: "too long", password: "Ab1!" + strings.Repeat("a", 69), want: PASSWORD_RULE_MAX_LENGTH},
+		{name: "too long in bytes", password: "Ab1!" + strings.Repeat("é", 35), want: PASSWORD_RULE_MAX_LENGTH},
+		{name: "longest", password: "Ab1!" + strings.Repeat("a", 68), want: ""},
+		{name: "missing uppercase", password: "abcdefg1!", want: PASSWORD_RULE_UPPERCASE},
+		{name: "missing lowercase", password: "ABCDEFG1!", want: PASSWORD_RULE_LOWERCASE},
+		{name: "missing digit", password: "Abcdefgh!", want: PASSWORD_RULE_DIGIT},
+		{name: "missing symbol", password: "Abcdefgh1", want: PASSWORD_RULE_SYMBOL},
+		{name: "breached", password: "Password1!", want: PASSWORD_RULE_BREACHED},
+		{name: "valid", password: "Correct-Horse-9", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Check(tt.password); got != tt.want {
+				t.Errorf("PasswordPolicy.Check(%v) = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPasswordPolicy_MissingFile(t *testing.T) {
+	_, err := NewPasswordPolicy(config.PasswordPolicy{
+		BreachedHashesPath: filepath.Join(t.TempDir(), "missing.txt"),
+	})
+	if err == nil {
+		t.Error("NewPasswordPolicy() error = nil, want error for a missing file")
+	}
+}
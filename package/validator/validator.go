@@ -11,8 +11,21 @@ const (
 	EMAIL        = "email"
 	PHONE_NUMBER = "phone_number"
 	EQUAL_FIELD  = "eqfield"
+	ONE_OF       = "oneof"
+	PASSWORD     = "password"
+	COUNTRY      = "iso3166_1_alpha2"
+	CURRENCY     = "iso4217"
 )
 
+var validateEmail validator.Func = func(fl validator.FieldLevel) bool {
+	email, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	return IsValidEmail(email)
+}
+
 var validatePhoneNumber validator.Func = func(fl validator.FieldLevel) bool {
 	phoneNumber, ok := fl.Field().Interface().(string)
 	if !ok {
@@ -23,7 +36,9 @@ var validatePhoneNumber validator.Func = func(fl validator.FieldLevel) bool {
 }
 
 func RegisterCustomValidators(v *validator.Validate) {
-	v.RegisterValidation("phone_number", validatePhoneNumber)
+	v.RegisterValidation(PHONE_NUMBER, validatePhoneNumber)
+	v.RegisterValidation(EMAIL, validateEmail)
+	v.RegisterValidation(PASSWORD, validatePassword)
 }
 
 func IsValidPhoneNumber(phoneNumber string) bool {
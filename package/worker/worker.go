@@ -0,0 +1,130 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+
+	logger "sondth-test_soa/package/log"
+)
+
+const (
+	DEFAULT_WORKERS    = 2
+	DEFAULT_QUEUE_SIZE = 100
+)
+
+// ErrQueueFull is returned when a task is submitted while every slot of the queue is taken
+var ErrQueueFull = fmt.Errorf("worker queue is full")
+
+// ErrStopped is returned when a task is submitted after Shutdown
+var ErrStopped = fmt.Errorf("worker is stopped")
+
+// Task is a unit of background work, the context is cancelled when the worker shuts down
+type Task func(ctx context.Context)
+
+// IWorker runs tasks in the background on a fixed number of goroutines
+type IWorker interface {
+	Submit(task Task) error
+	Shutdown(ctx context.Context) error
+}
+
+type worker struct {
+	mu      sync.RWMutex
+	stopped bool
+	tasks   chan Task
+	wg      sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// NewWorker starts size goroutines reading from a queue of queueSize tasks
+func NewWorker(size int, queueSize int) IWorker {
+	if size <= 0 {
+		size = DEFAULT_WORKERS
+	}
+	if queueSize <= 0 {
+		queueSize = DEFAULT_QUEUE_SIZE
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &worker{
+		tasks:  make(chan Task, queueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for i := 0; i < size; i++ {
+		w.wg.Add(1)
+		go w.run()
+	}
+
+	return w
+}
+
+// Submit queues the task without blocking
+func (w *worker) Submit(task Task) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.stopped {
+		return ErrStopped
+	}
+
+	select {
+	case w.tasks <- task:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Shutdown stops accepting tasks and waits for the queued ones until ctx is done,
+// running tasks are then cancelled
+func (w *worker) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.stopped {
+		w.stopped = true
+		close(w.tasks)
+	}
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		w.cancel()
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		return ctx.Err()
+	}
+}
+
+// -------------------------------------------------------------------------------
+func (w *worker) run() {
+	defer w.wg.Done()
+
+	for task := range w.tasks {
+		w.execute(task)
+	}
+}
+
+func (w *worker) execute(task Task) {
+	// A panicking task must not take the worker down with it
+	defer func() {
+		if r := recover(); r != nil {
+			logger.WithCtx(w.ctx).Error(
+				"Worker: task panicked",
+				slog.Any("panic", r),
+				slog.String("stack", string(debug.Stack())),
+			)
+		}
+	}()
+
+	task(w.ctx)
+}
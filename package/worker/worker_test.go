@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorker_RunsSubmittedTasks(t *testing.T) {
+	w := NewWorker(2, 10)
+
+	var count int32
+	for i := 0; i < 5; i++ {
+		if err := w.Submit(func(ctx context.Context) {
+			atomic.AddInt32(&count, 1)
+		}); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+
+	// Queued tasks are drained before shutdown returns
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := w.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&count); got != 5 {
+		t.Errorf("tasks run = %d, want 5", got)
+	}
+
+	if err := w.Submit(func(ctx context.Context) {}); err != ErrStopped {
+		t.Errorf("Submit() after shutdown error = %v, want %v", err, ErrStopped)
+	}
+}
+
+func TestWorker_QueueFull(t *testing.T) {
+	w := NewWorker(1, 1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	// Block the only goroutine, then fill the queue
+	_ = w.Submit(func(ctx context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+	if err := w.Submit(func(ctx context.Context) {}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if err := w.Submit(func(ctx context.Context) {}); err != ErrQueueFull {
+		t.Errorf("Submit() error = %v, want %v", err, ErrQueueFull)
+	}
+
+	close(release)
+	_ = w.Shutdown(context.Background())
+}
+
+func TestWorker_RecoversPanic(t *testing.T) {
+	w := NewWorker(1, 10)
+
+	done := make(chan struct{})
+	_ = w.Submit(func(ctx context.Context) {
+		panic("boom")
+	})
+	_ = w.Submit(func(ctx context.Context) {
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker stopped after a panicking task")
+	}
+	_ = w.Shutdown(context.Background())
+}
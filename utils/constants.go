@@ -9,13 +9,64 @@ const (
 )
 
 const (
-	GIN_CONTEXT_KEY  key = "GIN"
-	USER_CONTEXT_KEY key = "USER"
+	GIN_CONTEXT_KEY       key = "GIN"
+	USER_CONTEXT_KEY      key = "USER"
+	TOKEN_CONTEXT_KEY     key = "TOKEN"
+	PRINCIPAL_CONTEXT_KEY key = "PRINCIPAL"
+	LANGUAGE_CONTEXT_KEY  key = "LANGUAGE"
 )
 
 const (
-	USER_ACCESS_TOKEN_IAT  = 15 * 60           // 15 minutes
-	USER_REFRESH_TOKEN_IAT = 30 * 24 * 60 * 60 // 30 days
+	API_KEY_HEADER = "X-API-Key"
+	API_KEY_PREFIX = "sk_"
+)
+
+const (
+	USER_ACCESS_TOKEN_IAT    = 15 * 60           // 15 minutes
+	USER_REFRESH_TOKEN_IAT   = 30 * 24 * 60 * 60 // 30 days
+	PASSWORD_RESET_TOKEN_IAT = 15 * 60           // 15 minutes
+	MFA_CHALLENGE_TOKEN_IAT  = 5 * 60            // 5 minutes
+	VERIFICATION_CODE_IAT    = 10 * 60           // 10 minutes
+	VERIFICATION_RESEND_IAT  = 60                // 1 minute
+	LOGIN_FAILURE_WINDOW     = 60 * 60           // 1 hour
+	LOGIN_LOCKOUT_BASE       = 60                // 1 minute
+	LOGIN_LOCKOUT_MAX        = 60 * 60           // 1 hour
+	API_KEY_LAST_USED_IAT    = 60                // 1 minute, how often last_used_at of an API key is written
+	OIDC_STATE_IAT           = 10 * 60           // 10 minutes
+	IMPERSONATION_TOKEN_IAT  = 10 * 60           // 10 minutes
+)
+
+const (
+	REDIS_REFRESH_TOKEN_FAMILY_KEY  = "refresh_token_family:%s"
+	REDIS_REFRESH_TOKEN_USED_KEY    = "refresh_token_used:%s"
+	REDIS_ACCESS_TOKEN_DENYLIST_KEY = "access_token_denylist:%s"
+	REDIS_USER_TOKEN_VERSION_KEY    = "user_token_version:%s"
+	REDIS_REVOKED_SESSION_KEY       = "revoked_session:%s"
+	REDIS_PASSWORD_RESET_TOKEN_KEY  = "password_reset_token:%s"
+	REDIS_INVITATION_TOKEN_KEY      = "invitation_token:%s"
+	REDIS_MFA_CHALLENGE_TOKEN_KEY   = "mfa_challenge_token:%s"
+	REDIS_MFA_CHALLENGE_ATTEMPT_KEY = "mfa_challenge_attempt:%s"
+	REDIS_VERIFICATION_CODE_KEY     = "verification_code:%s:%s"
+	REDIS_VERIFICATION_ATTEMPT_KEY  = "verification_attempt:%s:%s"
+	REDIS_VERIFICATION_RESEND_KEY   = "verification_resend:%s:%s"
+	REDIS_LOGIN_FAILED_USERNAME_KEY = "login_failed_username:%s"
+	REDIS_LOGIN_FAILED_IP_KEY       = "login_failed_ip:%s"
+	REDIS_LOGIN_LOCK_USERNAME_KEY   = "login_lock_username:%s"
+	REDIS_LOGIN_LOCK_IP_KEY         = "login_lock_ip:%s"
+	REDIS_OIDC_STATE_KEY            = "oidc_state:%s"
+)
+
+const (
+	MAX_MFA_CHALLENGE_ATTEMPTS = 5
+	MFA_RECOVERY_CODES         = 10
+
+	VERIFICATION_CODE_LENGTH       = 6
+	MAX_VERIFICATION_CODE_ATTEMPTS = 5
+
+	MAX_LOGIN_FAILURES_PER_USERNAME = 5
+	MAX_LOGIN_FAILURES_PER_IP       = 20
+
+	API_KEY_LENGTH = 32
 )
 
 const (
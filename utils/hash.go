@@ -1,16 +1,66 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
 
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	return string(bytes), err
+	"sondth-test_soa/package/password"
+)
+
+// passwordHashParams are the params for new password hashes, configured once on startup
+var passwordHashParams = password.DefaultParams()
+
+// SetPasswordHashParams sets the algorithm and parameters used by HashPassword
+func SetPasswordHashParams(params password.Params) {
+	passwordHashParams = params
+}
+
+func HashPassword(pwd string) (string, error) {
+	return password.Hash(pwd, passwordHashParams)
 }
 
-func CheckPasswordHash(password, hash string) error {
-	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+func CheckPasswordHash(pwd, hash string) error {
+	if err := password.Verify(pwd, hash); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// PasswordNeedsRehash reports whether hash uses outdated parameters and should be replaced
+func PasswordNeedsRehash(hash string) bool {
+	return password.NeedsRehash(hash, passwordHashParams)
+}
+
+// GenerateRandomToken returns a URL-safe random token built from n random bytes
+func GenerateRandomToken(n int) (string, error) {
+	bytes := make([]byte, n)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// GenerateNumericCode returns a random code of n digits, e.g: one-time verification codes
+func GenerateNumericCode(n int) (string, error) {
+	code := make([]byte, n)
+	for i := range code {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + digit.Int64())
+	}
+
+	return string(code), nil
+}
+
+// HashToken returns the hex encoded SHA-256 of a token, used to store tokens at rest
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
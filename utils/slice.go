@@ -1 +1,16 @@
 package utils
+
+// Unique returns the elements of s without duplicates, keeping the order of first occurrence
+func Unique[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	result := make([]T, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+
+	return result
+}